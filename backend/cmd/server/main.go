@@ -1,8 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"quiz-game-backend/internal/config"
@@ -40,94 +47,318 @@ func main() {
 
 	hub := ws.NewHub()
 
-	authService := services.NewAuthService(db, cfg.JWTSecret)
+	idleSec, _ := strconv.Atoi(cfg.PresenceIdle)
+	if idleSec <= 0 {
+		idleSec = 90
+	}
+	presenceService := services.NewPresenceService(time.Duration(idleSec) * time.Second)
+	presenceService.OnChange(func(ev services.PresenceEvent) {
+		if ev.RoomID > 0 {
+			hub.BroadcastToRoom(ev.RoomID, ws.WSMessage{Type: "presence", Data: ev})
+		}
+	})
+	presenceService.Start()
+	defer presenceService.Stop()
+
+	var blocked []string
+	if cfg.NickBlocklist != "" {
+		blocked = strings.Split(cfg.NickBlocklist, ",")
+	}
+	nicknameFilter := services.NewNicknameFilter(blocked)
+
+	accessMin, _ := strconv.Atoi(cfg.AccessTTL)
+	if accessMin <= 0 {
+		accessMin = 15
+	}
+	refreshDays, _ := strconv.Atoi(cfg.RefreshTTL)
+	if refreshDays <= 0 {
+		refreshDays = 30
+	}
+	authService := services.NewAuthService(db, cfg.JWTSecret, time.Duration(accessMin)*time.Minute, time.Duration(refreshDays)*24*time.Hour)
+	playerHours, _ := strconv.Atoi(cfg.PlayerTTL)
+	if playerHours <= 0 {
+		playerHours = 12
+	}
+	playerRefreshDays, _ := strconv.Atoi(cfg.PlayerRefresh)
+	if playerRefreshDays <= 0 {
+		playerRefreshDays = 7
+	}
+	playerTokenService := services.NewPlayerTokenService(db, cfg.JWTSecret, time.Duration(playerHours)*time.Hour, time.Duration(playerRefreshDays)*24*time.Hour)
+	oidcService := services.NewOIDCService(db, authService, services.OIDCConfig{
+		Issuer:       cfg.OIDCIssuer,
+		ClientID:     cfg.OIDCClientID,
+		ClientSecret: cfg.OIDCSecret,
+		RedirectURL:  cfg.OIDCRedirect,
+		Scopes:       strings.Fields(cfg.OIDCScopes),
+		Name:         cfg.OIDCName,
+		AutoRegister: cfg.OIDCRegister == "true",
+	})
+	if oidcService.Enabled() {
+		log.Printf("single sign-on enabled with issuer %s", cfg.OIDCIssuer)
+	}
+	accessService := services.NewAccessService(db)
+	apiKeyService := services.NewAPIKeyService(db)
+	tokenKeys, err := services.ParseTokenKeys(cfg.BotTokenKeys, cfg.JWTSecret)
+	if err != nil {
+		log.Fatalf("BOT_TOKEN_KEYS: %v", err)
+	}
+	if !tokenKeys.Configured() {
+		log.Println("BOT_TOKEN_KEYS not set, bot tokens are encrypted with a key derived from JWT_SECRET")
+	}
+	botTokenService := services.NewBotTokenService(db, tokenKeys)
+	if n, err := botTokenService.Reencrypt(); err != nil {
+		log.Printf("failed to re-encrypt bot tokens: %v", err)
+	} else if n > 0 {
+		log.Printf("re-encrypted %d bot tokens with the current key", n)
+	}
+	remoteAuthService := services.NewRemoteAuthService(db)
+	if n, err := remoteAuthService.HashPlaintextPasswords(); err != nil {
+		log.Printf("failed to hash remote passwords: %v", err)
+	} else if n > 0 {
+		log.Printf("hashed %d plaintext remote passwords", n)
+	}
 	quizService := services.NewQuizService(db)
 	scoringService := services.NewScoringService()
-	sessionService := services.NewSessionService(db, scoringService)
-	tgUserService := services.NewTelegramUserService(db)
-	roomService := services.NewRoomService(db)
+	eventBus := services.NewEventBus()
+	auditService := services.NewAuditService(db)
+	sessionService := services.NewSessionService(db, scoringService, presenceService, nicknameFilter, eventBus, auditService)
+	unsubscribeHub := eventBus.Subscribe(func(ev services.SessionEvent) {
+		var data interface{} = ev.State
+		if ev.State == nil {
+			data = gin.H{"session_id": ev.SessionID}
+			if ev.QuestionID > 0 {
+				data = gin.H{"session_id": ev.SessionID, "question_id": ev.QuestionID, "answer_count": ev.AnswerCount}
+			}
+		}
+		msg := ws.WSMessage{Type: ev.Type, Data: data}
+		hub.Broadcast(ev.SessionID, msg)
+		if ev.RoomID > 0 {
+			hub.BroadcastToRoom(ev.RoomID, msg)
+		}
+	})
+	defer unsubscribeHub()
+	tgUserService := services.NewTelegramUserService(db, nicknameFilter)
+	roomService := services.NewRoomService(db, presenceService, nicknameFilter)
+	roomService.OnKick(func(k services.KickResult) {
+		if k.MemberID > 0 {
+			hub.DisconnectMember(k.MemberID, ws.WSMessage{Type: "kicked", Data: k})
+		}
+		hub.BroadcastToRoom(k.RoomID, ws.WSMessage{Type: "member_kicked", Data: k})
+		sessionService.RecheckAutoReveal(k.RoomID)
+	})
+	chatService := services.NewChatService(db)
+
+	var rateStore services.RateStore = services.NewMemoryRateStore()
+	switch cfg.RateLimitStore {
+	case "postgres":
+		rateStore = services.NewPostgresRateStore(db)
+	case "memory":
+	default:
+		log.Printf("unknown RATE_LIMIT_STORE %q, using memory", cfg.RateLimitStore)
+	}
+	limiter := services.NewRateLimiter(rateStore)
+
+	var notifier services.Notifier = services.LogNotifier{}
+	switch cfg.Notifier {
+	case "file":
+		notifier = services.NewFileNotifier(cfg.NotifierFile)
+	case "log":
+	default:
+		log.Printf("unknown NOTIFIER %q, using log", cfg.Notifier)
+	}
+	resetMin, _ := strconv.Atoi(cfg.ResetTTL)
+	if resetMin <= 0 {
+		resetMin = 60
+	}
+	accountService := services.NewAccountService(db, authService, botTokenService, notifier, time.Duration(resetMin)*time.Minute, cfg.AppURL, "/uploads")
 
 	aiService := services.NewAIGenerateService(cfg.QwenAPIKey, cfg.QwenAPIURL, cfg.QwenModel)
 
 	authHandler := handlers.NewAuthHandler(authService)
-	quizHandler := handlers.NewQuizHandler(quizService)
+	oidcHandler := handlers.NewOIDCHandler(oidcService)
+	accountHandler := handlers.NewAccountHandler(accountService, roomService, hub)
+	workspaceHandler := handlers.NewWorkspaceHandler(accessService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	quizHandler := handlers.NewQuizHandler(quizService, auditService)
 	questionHandler := handlers.NewQuestionHandler(quizService)
-	sessionHandler := handlers.NewSessionHandler(sessionService, hub, db)
+	sessionHandler := handlers.NewSessionHandler(sessionService, auditService, hub, db)
 	participantHandler := handlers.NewParticipantHandler(sessionService, hub)
-	settingsHandler := handlers.NewSettingsHandler(db)
+	settingsHandler := handlers.NewSettingsHandler(db, cfg.TelegramAPIURL, botTokenService, remoteAuthService, oidcService, auditService)
 	tgUserHandler := handlers.NewTelegramUserHandler(tgUserService)
 	wsHandler := handlers.NewWSHandler(hub)
-	aiHandler := handlers.NewAIGenerateHandler(quizService, aiService)
-	roomHandler := handlers.NewRoomHandler(roomService, sessionService, hub)
-	playHandler := handlers.NewPlayHandler(roomService, sessionService, hub)
+	aiHandler := handlers.NewAIGenerateHandler(quizService, aiService, auditService)
+	roomHandler := handlers.NewRoomHandler(roomService, sessionService, auditService, hub)
+	displayHandler := handlers.NewDisplayHandler(roomService, sessionService, hub)
+	playHandler := handlers.NewPlayHandler(roomService, sessionService, presenceService, playerTokenService, hub)
+	chatHandler := handlers.NewChatHandler(chatService, roomService, presenceService, playerTokenService, hub)
 
 	r := gin.Default()
+	// Rate limits key on the client address, so only the reverse proxy may
+	// set X-Forwarded-For.
+	if cfg.TrustedProxies != "" {
+		if err := r.SetTrustedProxies(strings.Split(cfg.TrustedProxies, ",")); err != nil {
+			log.Fatalf("TRUSTED_PROXIES: %v", err)
+		}
+	}
 	r.MaxMultipartMemory = 100 << 20
 
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Bot-API-Key"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept-Language", "X-Bot-API-Key", "X-Workspace-ID", "X-API-Key"},
+		ExposeHeaders:    []string{"Retry-After"},
 		AllowCredentials: true,
 	}))
 
 	r.Static("/uploads", "/uploads")
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	r.GET("/ws/session/:id", wsHandler.HandleWebSocket)
-	r.GET("/ws/room/:code", playHandler.HandleRoomWebSocket)
+	// Lookups by room code or display token are what guessing looks like:
+	// clients that miss too often are locked out for a while.
+	codeLockout := func(code func(c *gin.Context) string) gin.HandlerFunc {
+		return middleware.CodeLockout(limiter, "code", code, 20, 15*time.Minute)
+	}
+	perIP := func(name string, limit int, window time.Duration) middleware.RatePolicy {
+		return middleware.RatePolicy{Name: name + ":ip", Limit: limit, Window: window, Key: middleware.ByIP}
+	}
+	perField := func(name, field string, limit int, window time.Duration) middleware.RatePolicy {
+		return middleware.RatePolicy{Name: name + ":" + field, Limit: limit, Window: window, Key: middleware.ByField(field)}
+	}
+
+	r.GET("/ws/room/:code", codeLockout(middleware.ByParam("code")), middleware.RateLimit(limiter, perIP("ws", 60, time.Minute)), playHandler.HandleRoomWebSocket)
+	r.GET("/ws/display/:token", codeLockout(middleware.ByParam("token")), middleware.RateLimit(limiter, perIP("ws", 60, time.Minute)), displayHandler.HandleWebSocket)
 
 	pollSec, _ := strconv.Atoi(cfg.PollInterval)
 	if pollSec <= 0 {
-		pollSec = 2
+		pollSec = 15
+	}
+	stateTTLHours, _ := strconv.Atoi(cfg.BotStateTTL)
+	if stateTTLHours <= 0 {
+		stateTTLHours = 72
 	}
 	botManager := telegram.NewBotManager(
-		db, sessionService, roomService, quizService, tgUserService, hub,
-		cfg.WebhookBaseURL, cfg.BotAPIKey,
+		db, botTokenService, sessionService, roomService, quizService, tgUserService, presenceService, chatService, aiService, remoteAuthService, auditService, hub,
+		cfg.WebhookBaseURL, cfg.BotAPIKey, cfg.BotMode, cfg.TelegramAPIURL,
 		time.Duration(pollSec)*time.Second,
 		30*time.Second,
+		time.Duration(stateTTLHours)*time.Hour,
 	)
-	if cfg.WebhookBaseURL != "" {
+	switch {
+	case cfg.BotMode != telegram.BotModeWebhook && cfg.BotMode != telegram.BotModePolling:
+		log.Printf("unknown BOT_MODE %q, bot manager disabled", cfg.BotMode)
+	case cfg.BotMode == telegram.BotModeWebhook && cfg.WebhookBaseURL == "":
+		log.Println("WEBHOOK_BASE_URL not set, bot manager disabled (set BOT_MODE=polling to run without it)")
+	default:
 		botManager.Start()
 		defer botManager.Stop()
-	} else {
-		log.Println("WEBHOOK_BASE_URL not set, bot manager disabled")
 	}
+	accountService.OnDelete(botManager.RemoveHost)
 	r.POST("/webhook/bot/:secret", botManager.HandleWebhook)
 
 	api := r.Group("/api/v1")
+	api.Use(middleware.Localize())
 	{
 		auth := api.Group("/auth")
 		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
+			auth.POST("/register", middleware.RateLimit(limiter, perIP("register", 5, time.Hour)), authHandler.Register)
+			auth.POST("/login", middleware.RateLimit(limiter,
+				perIP("login", 20, time.Minute),
+				perField("login", "username", 10, 15*time.Minute),
+			), authHandler.Login)
+			auth.POST("/refresh", middleware.RateLimit(limiter, perIP("refresh", 60, time.Minute)), authHandler.Refresh)
+			auth.POST("/logout", authHandler.Logout)
+			auth.POST("/logout-all", middleware.JWTAuth(authService), authHandler.LogoutAll)
+			auth.GET("/sessions", middleware.JWTAuth(authService), authHandler.ListSessions)
+			auth.DELETE("/sessions/:id", middleware.JWTAuth(authService), authHandler.RevokeSession)
+			auth.PUT("/password", middleware.JWTAuth(authService), middleware.RateLimit(limiter, perIP("password", 10, time.Minute)), accountHandler.ChangePassword)
+			auth.POST("/password/reset", middleware.RateLimit(limiter, perIP("password_reset", 10, time.Minute)), accountHandler.ResetPassword)
+			auth.PUT("/username", middleware.JWTAuth(authService), accountHandler.ChangeUsername)
+			auth.DELETE("/account", middleware.JWTAuth(authService), middleware.RateLimit(limiter, perIP("password", 10, time.Minute)), accountHandler.DeleteAccount)
+
+			ssoLimit := middleware.RateLimit(limiter, perIP("sso", 20, time.Minute))
+			auth.GET("/oidc", oidcHandler.GetConfig)
+			auth.POST("/oidc/start", ssoLimit, oidcHandler.Start)
+			auth.POST("/oidc/callback", ssoLimit, oidcHandler.Callback)
+			identities := auth.Group("/identities")
+			identities.Use(middleware.JWTAuth(authService))
+			{
+				identities.GET("", oidcHandler.ListIdentities)
+				identities.POST("/link", ssoLimit, oidcHandler.StartLink)
+				identities.POST("/callback", ssoLimit, oidcHandler.FinishLink)
+				identities.DELETE("/:id", oidcHandler.Unlink)
+			}
+		}
+
+		if cfg.AdminAPIKey != "" {
+			admin := api.Group("/admin")
+			admin.Use(middleware.AdminAuth(cfg.AdminAPIKey))
+			{
+				admin.POST("/password-resets", accountHandler.IssuePasswordReset)
+			}
 		}
 
+		hostAuth := middleware.HostAuth(authService, apiKeyService)
+		workspace := middleware.Workspace(accessService)
+		read := middleware.Require(services.PermQuizzesRead)
+		write := middleware.Require(services.PermQuizzesWrite)
+		control := middleware.Require(services.PermRoomsControl)
+		analytics := middleware.Require(services.PermAnalyticsRead)
+
 		settings := api.Group("/settings")
-		settings.Use(middleware.JWTAuth(authService))
+		settings.Use(middleware.JWTAuth(authService), workspace)
 		{
+			manage := middleware.Require(services.PermSettings)
 			settings.GET("", settingsHandler.GetSettings)
-			settings.PUT("", settingsHandler.UpdateSettings)
+			settings.PUT("", manage, settingsHandler.UpdateSettings)
+			settings.POST("/remote/pairing-code", manage, settingsHandler.CreatePairingCode)
+			settings.GET("/remote/authorizations", manage, settingsHandler.ListRemoteAuthorizations)
+			settings.DELETE("/remote/authorizations/:id", manage, settingsHandler.RevokeRemoteAuthorization)
+		}
+
+		workspaces := api.Group("/workspaces")
+		workspaces.Use(middleware.JWTAuth(authService))
+		{
+			workspaces.GET("", workspaceHandler.ListWorkspaces)
+			workspaces.GET("/members", workspace, workspaceHandler.ListMembers)
+			workspaces.POST("/members", workspace, middleware.Require(services.PermMembers), workspaceHandler.AddMember)
+			workspaces.PUT("/members/:id", workspace, middleware.Require(services.PermMembers), workspaceHandler.UpdateMember)
+			workspaces.DELETE("/members/:id", workspace, workspaceHandler.RemoveMember)
+		}
+
+		apiKeys := api.Group("/api-keys")
+		apiKeys.Use(middleware.JWTAuth(authService), workspace, middleware.Require(services.PermSettings))
+		{
+			apiKeys.GET("", apiKeyHandler.ListAPIKeys)
+			apiKeys.POST("", apiKeyHandler.CreateAPIKey)
+			apiKeys.DELETE("/:id", apiKeyHandler.DeleteAPIKey)
+		}
+
+		audit := api.Group("/audit")
+		audit.Use(middleware.JWTAuth(authService), workspace, middleware.Require(services.PermSettings))
+		{
+			audit.GET("", auditHandler.ListAudit)
 		}
 
 		quizzes := api.Group("/quizzes")
-		quizzes.Use(middleware.JWTAuth(authService))
-		{
-			quizzes.GET("/ai-status", aiHandler.CheckAI)
-			quizzes.POST("/generate", aiHandler.Generate)
-			quizzes.GET("", quizHandler.ListQuizzes)
-			quizzes.POST("", quizHandler.CreateQuiz)
-			quizzes.GET("/:id", quizHandler.GetQuiz)
-			quizzes.PUT("/:id", quizHandler.UpdateQuiz)
-			quizzes.DELETE("/:id", quizHandler.DeleteQuiz)
-			quizzes.POST("/:id/questions", questionHandler.CreateQuestion)
-			quizzes.POST("/:id/categories", questionHandler.CreateCategory)
-			quizzes.PUT("/:id/reorder", questionHandler.ReorderQuiz)
-			quizzes.GET("/:id/export", quizHandler.ExportQuiz)
-			quizzes.POST("/:id/import", quizHandler.ImportQuiz)
+		quizzes.Use(hostAuth, workspace)
+		{
+			quizzes.GET("/ai-status", read, aiHandler.CheckAI)
+			quizzes.POST("/generate", write, aiHandler.Generate)
+			quizzes.GET("", read, quizHandler.ListQuizzes)
+			quizzes.POST("", write, quizHandler.CreateQuiz)
+			quizzes.GET("/:id", read, quizHandler.GetQuiz)
+			quizzes.PUT("/:id", write, quizHandler.UpdateQuiz)
+			quizzes.DELETE("/:id", write, quizHandler.DeleteQuiz)
+			quizzes.POST("/:id/questions", write, questionHandler.CreateQuestion)
+			quizzes.POST("/:id/categories", write, questionHandler.CreateCategory)
+			quizzes.PUT("/:id/reorder", write, questionHandler.ReorderQuiz)
+			quizzes.GET("/:id/export", read, quizHandler.ExportQuiz)
+			quizzes.POST("/:id/import", write, quizHandler.ImportQuiz)
 		}
 
 		questions := api.Group("/questions")
-		questions.Use(middleware.JWTAuth(authService))
+		questions.Use(hostAuth, workspace, write)
 		{
 			questions.PUT("/:id", questionHandler.UpdateQuestion)
 			questions.DELETE("/:id", questionHandler.DeleteQuestion)
@@ -135,60 +366,88 @@ func main() {
 		}
 
 		categories := api.Group("/categories")
-		categories.Use(middleware.JWTAuth(authService))
+		categories.Use(hostAuth, workspace, write)
 		{
 			categories.PUT("/:id", questionHandler.UpdateCategory)
 			categories.DELETE("/:id", questionHandler.DeleteCategory)
 		}
 
 		images := api.Group("/images")
-		images.Use(middleware.JWTAuth(authService))
+		images.Use(hostAuth, workspace, write)
 		{
 			images.DELETE("/:id", questionHandler.DeleteQuestionImage)
 		}
 
 		upload := api.Group("/upload")
-		upload.Use(middleware.JWTAuth(authService))
+		upload.Use(hostAuth, workspace, write)
 		{
 			upload.POST("", questionHandler.UploadImage)
 		}
 
 		rooms := api.Group("/rooms")
-		rooms.Use(middleware.JWTAuth(authService))
+		rooms.Use(hostAuth, workspace)
 		{
-			rooms.POST("", roomHandler.CreateRoom)
-			rooms.GET("", roomHandler.ListActiveRooms)
-			rooms.GET("/history", roomHandler.ListRoomHistory)
-			rooms.GET("/:id", roomHandler.GetRoom)
-			rooms.POST("/:id/close", roomHandler.CloseRoom)
-			rooms.POST("/:id/start", roomHandler.StartQuizInRoom)
-			rooms.POST("/:id/reveal", roomHandler.SessionReveal)
-			rooms.POST("/:id/next", roomHandler.SessionNext)
-			rooms.POST("/:id/finish", roomHandler.SessionFinish)
-			rooms.GET("/:id/leaderboard", roomHandler.GetRoomLeaderboard)
+			rooms.POST("", control, roomHandler.CreateRoom)
+			rooms.GET("", control, roomHandler.ListActiveRooms)
+			rooms.GET("/history", analytics, roomHandler.ListRoomHistory)
+			rooms.GET("/:id", control, roomHandler.GetRoom)
+			rooms.POST("/:id/close", control, roomHandler.CloseRoom)
+			rooms.POST("/:id/start", control, roomHandler.StartQuizInRoom)
+			rooms.POST("/:id/reveal", control, roomHandler.SessionReveal)
+			rooms.POST("/:id/next", control, roomHandler.SessionNext)
+			rooms.POST("/:id/finish", control, roomHandler.SessionFinish)
+			rooms.GET("/:id/leaderboard", analytics, roomHandler.GetRoomLeaderboard)
+			rooms.POST("/:id/display-token", control, displayHandler.RotateDisplayToken)
+			rooms.GET("/:id/chat", control, chatHandler.RoomHistory)
+			rooms.DELETE("/:id/chat", control, chatHandler.ClearChat)
+			rooms.PUT("/:id/chat/settings", control, chatHandler.UpdateSettings)
+			rooms.POST("/:id/chat/mute", control, chatHandler.Mute)
+			rooms.GET("/:id/chat/mutes", control, chatHandler.ListMutes)
+			rooms.POST("/:id/kick", control, roomHandler.KickMember)
+			rooms.PUT("/:id/rename", control, roomHandler.RenameMember)
+			rooms.GET("/:id/bans", control, roomHandler.ListBans)
+			rooms.DELETE("/:id/bans/:banId", control, roomHandler.Unban)
 		}
 
+		display := api.Group("/display")
+		{
+			display.GET("/:token", codeLockout(middleware.ByParam("token")), middleware.RateLimit(limiter, perIP("display", 60, time.Minute)), displayHandler.GetState)
+		}
+
+		// A class behind one NAT shares an address, so per-address limits
+		// are loose and per-player limits do the real work.
 		play := api.Group("/play")
+		play.Use(middleware.RateLimit(limiter, perIP("play", 1200, time.Minute)))
 		{
-			play.POST("/join", playHandler.Join)
-			play.GET("/reconnect", playHandler.Reconnect)
-			play.POST("/answer", playHandler.Answer)
-			play.POST("/answer-complex", playHandler.AnswerComplex)
-			play.GET("/state", playHandler.GetState)
-			play.PUT("/nickname", playHandler.UpdateNickname)
-			play.POST("/leave", playHandler.Leave)
+			answerLimit := middleware.RateLimit(limiter, perField("answer", "token", 30, time.Minute))
+			byCode := codeLockout(middleware.ByField("code"))
+			play.POST("/join", byCode, middleware.RateLimit(limiter,
+				perIP("join", 60, time.Minute),
+				perField("join", "token", 10, time.Minute),
+			), playHandler.Join)
+			play.POST("/reconnect", byCode, playHandler.Reconnect)
+			play.POST("/answer", answerLimit, playHandler.Answer)
+			play.POST("/answer-complex", answerLimit, playHandler.AnswerComplex)
+			play.GET("/state", byCode, playHandler.GetState)
+			play.PUT("/nickname", middleware.RateLimit(limiter, perField("nickname", "token", 10, time.Minute)), playHandler.UpdateNickname)
+			play.POST("/leave", middleware.RateLimit(limiter, perField("leave", "token", 10, time.Minute)), playHandler.Leave)
 			play.GET("/my-result", playHandler.GetMyResult)
+			play.POST("/react", middleware.RateLimit(limiter, perField("react", "token", 30, time.Minute)), chatHandler.React)
+			play.POST("/chat", middleware.RateLimit(limiter, perField("chat", "token", 20, time.Minute)), chatHandler.SendMessage)
+			play.GET("/chat", chatHandler.PlayHistory)
 		}
 
 		sessions := api.Group("/sessions")
 		{
-			sessions.GET("", middleware.JWTAuth(authService), sessionHandler.ListSessions)
-			sessions.POST("", middleware.JWTAuth(authService), sessionHandler.CreateSession)
-			sessions.GET("/:id", middleware.FlexAuth(authService, cfg.BotAPIKey), sessionHandler.GetSession)
-			sessions.POST("/:id/reveal", middleware.JWTAuth(authService), sessionHandler.RevealAnswer)
-			sessions.POST("/:id/next", middleware.JWTAuth(authService), sessionHandler.NextQuestion)
-			sessions.POST("/:id/finish", middleware.JWTAuth(authService), sessionHandler.ForceFinish)
-			sessions.GET("/:id/leaderboard", middleware.FlexAuth(authService, cfg.BotAPIKey), sessionHandler.GetLeaderboard)
+			flexAuth := middleware.FlexAuth(authService, apiKeyService, cfg.BotAPIKey)
+			sessions.GET("", hostAuth, workspace, analytics, sessionHandler.ListSessions)
+			sessions.POST("", hostAuth, workspace, control, sessionHandler.CreateSession)
+			sessions.GET("/:id", flexAuth, workspace, analytics, sessionHandler.GetSession)
+			sessions.POST("/:id/reveal", hostAuth, workspace, control, sessionHandler.RevealAnswer)
+			sessions.POST("/:id/next", hostAuth, workspace, control, sessionHandler.NextQuestion)
+			sessions.POST("/:id/finish", hostAuth, workspace, control, sessionHandler.ForceFinish)
+			sessions.PUT("/:id/auto-reveal", hostAuth, workspace, control, sessionHandler.SetAutoReveal)
+			sessions.GET("/:id/leaderboard", flexAuth, workspace, analytics, sessionHandler.GetLeaderboard)
 
 			sessions.POST("/join", middleware.BotAuth(cfg.BotAPIKey), participantHandler.JoinSession)
 			sessions.POST("/:id/answer", middleware.BotAuth(cfg.BotAPIKey), participantHandler.SubmitAnswer)
@@ -206,12 +465,32 @@ func main() {
 		internal := api.Group("/internal")
 		internal.Use(middleware.BotAuth(cfg.BotAPIKey))
 		{
-			internal.GET("/bot-tokens", settingsHandler.GetBotTokens)
+			// Hands out every customer's bot token; only for a separate bot
+			// service that needs them.
+			if cfg.BotTokenExport == "true" {
+				internal.GET("/bot-tokens", settingsHandler.GetBotTokens)
+			}
+			internal.GET("/bot-metrics", botManager.Metrics)
 		}
 	}
 
-	log.Printf("server starting on :%s", cfg.ServerPort)
-	if err := r.Run(":" + cfg.ServerPort); err != nil {
-		log.Fatalf("failed to start server: %v", err)
+	srv := &http.Server{Addr: ":" + cfg.ServerPort, Handler: r}
+	go func() {
+		log.Printf("server starting on :%s", cfg.ServerPort)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for a stop signal so deferred shutdowns (bot pollers, presence) run.
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("server shutdown: %v", err)
 	}
 }
@@ -9,10 +9,35 @@ type Config struct {
 	DBPassword     string
 	DBName         string
 	JWTSecret      string
+	AccessTTL      string
+	RefreshTTL     string
+	PlayerTTL      string
+	PlayerRefresh  string
 	BotAPIKey      string
+	BotTokenKeys   string
+	BotTokenExport string
 	ServerPort     string
 	WebhookBaseURL string
+	BotMode        string
+	TelegramAPIURL string
 	PollInterval   string
+	BotStateTTL    string
+	PresenceIdle   string
+	NickBlocklist  string
+	RateLimitStore string
+	OIDCIssuer     string
+	OIDCClientID   string
+	OIDCSecret     string
+	OIDCRedirect   string
+	OIDCScopes     string
+	OIDCName       string
+	OIDCRegister   string
+	TrustedProxies string
+	AdminAPIKey    string
+	AppURL         string
+	Notifier       string
+	NotifierFile   string
+	ResetTTL       string
 	QwenAPIKey     string
 	QwenAPIURL     string
 	QwenModel      string
@@ -26,10 +51,35 @@ func Load() *Config {
 		DBPassword:     getEnv("DB_PASSWORD", "postgres"),
 		DBName:         getEnv("DB_NAME", "quizgame"),
 		JWTSecret:      getEnv("JWT_SECRET", "super-secret-key-change-me"),
+		AccessTTL:      getEnv("ACCESS_TOKEN_TTL_MINUTES", "15"),
+		RefreshTTL:     getEnv("REFRESH_TOKEN_TTL_DAYS", "30"),
+		PlayerTTL:      getEnv("PLAYER_TOKEN_TTL_HOURS", "12"),
+		PlayerRefresh:  getEnv("PLAYER_TOKEN_REFRESH_DAYS", "7"),
 		BotAPIKey:      getEnv("BOT_API_KEY", "bot-api-key-change-me"),
+		BotTokenKeys:   getEnv("BOT_TOKEN_KEYS", ""),
+		BotTokenExport: getEnv("BOT_TOKEN_EXPORT", "false"),
 		ServerPort:     getEnv("SERVER_PORT", "8080"),
 		WebhookBaseURL: getEnv("WEBHOOK_BASE_URL", ""),
-		PollInterval:   getEnv("POLL_INTERVAL", "2"),
+		BotMode:        getEnv("BOT_MODE", "webhook"),
+		TelegramAPIURL: getEnv("TELEGRAM_API_URL", "https://api.telegram.org"),
+		PollInterval:   getEnv("POLL_INTERVAL", "15"),
+		BotStateTTL:    getEnv("BOT_STATE_TTL_HOURS", "72"),
+		PresenceIdle:   getEnv("PRESENCE_IDLE_TIMEOUT", "90"),
+		NickBlocklist:  getEnv("NICKNAME_BLOCKLIST", ""),
+		RateLimitStore: getEnv("RATE_LIMIT_STORE", "memory"),
+		OIDCIssuer:     getEnv("OIDC_ISSUER", ""),
+		OIDCClientID:   getEnv("OIDC_CLIENT_ID", ""),
+		OIDCSecret:     getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirect:   getEnv("OIDC_REDIRECT_URL", ""),
+		OIDCScopes:     getEnv("OIDC_SCOPES", "openid profile email"),
+		OIDCName:       getEnv("OIDC_PROVIDER_NAME", "SSO"),
+		OIDCRegister:   getEnv("OIDC_AUTO_REGISTER", "false"),
+		TrustedProxies: getEnv("TRUSTED_PROXIES", ""),
+		AdminAPIKey:    getEnv("ADMIN_API_KEY", ""),
+		AppURL:         getEnv("APP_URL", ""),
+		Notifier:       getEnv("NOTIFIER", "log"),
+		NotifierFile:   getEnv("NOTIFIER_FILE", "notifications.log"),
+		ResetTTL:       getEnv("PASSWORD_RESET_TTL_MINUTES", "60"),
 		QwenAPIKey:     getEnv("QWEN_API_KEY", ""),
 		QwenAPIURL:     getEnv("QWEN_API_URL", "https://dashscope.aliyuncs.com/compatible-mode/v1"),
 		QwenModel:      getEnv("QWEN_MODEL", "qwen-plus"),
@@ -132,6 +132,15 @@ func AutoMigrate(db *gorm.DB) {
 		END IF;
 	END $$;`)
 
+	// Encrypted bot tokens don't fit in the old varchar(255)
+	db.Exec(`DO $$
+	BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'hosts' AND column_name = 'bot_token' AND data_type <> 'text')
+		THEN
+			ALTER TABLE hosts ALTER COLUMN bot_token TYPE text;
+		END IF;
+	END $$;`)
+
 	err := db.AutoMigrate(
 		&models.Host{},
 		&models.TelegramUser{},
@@ -145,9 +154,54 @@ func AutoMigrate(db *gorm.DB) {
 		&models.Session{},
 		&models.Participant{},
 		&models.Answer{},
+		&models.ChatMessage{},
+		&models.RoomMute{},
+		&models.RoomBan{},
+		&models.TelegramFile{},
+		&models.TelegramState{},
+		&models.AuthSession{},
+		&models.HostIdentity{},
+		&models.OIDCLoginState{},
+		&models.WorkspaceMember{},
+		&models.APIKey{},
+		&models.RemoteAuthorization{},
+		&models.RemoteLoginAttempt{},
+		&models.RemotePairingCode{},
+		&models.RateLimitCounter{},
+		&models.AuditEntry{},
+		&models.PasswordReset{},
 	)
 	if err != nil {
 		log.Fatalf("failed to auto-migrate: %v", err)
 	}
+
+	// Conversation state used to keep the typed host remote password; scrub
+	// what was written before remote passwords were hashed.
+	res := db.Exec("UPDATE telegram_states SET data = data - 'host_auth_password' WHERE data->'host_auth_password' IS NOT NULL")
+	if res.Error != nil {
+		log.Fatalf("failed to scrub remote passwords from bot state: %v", res.Error)
+	}
+	if res.RowsAffected > 0 {
+		log.Printf("removed stored remote passwords from %d bot conversation states", res.RowsAffected)
+	}
+
+	// The audit log is append-only, also for anyone with database access
+	// through the application's user.
+	err = db.Exec(`
+	CREATE OR REPLACE FUNCTION audit_entries_append_only() RETURNS trigger AS $$
+	BEGIN
+		RAISE EXCEPTION 'audit log is append-only';
+	END;
+	$$ LANGUAGE plpgsql;
+
+	DROP TRIGGER IF EXISTS audit_entries_no_change ON audit_entries;
+	CREATE TRIGGER audit_entries_no_change BEFORE UPDATE OR DELETE ON audit_entries
+		FOR EACH ROW EXECUTE FUNCTION audit_entries_append_only();
+	DROP TRIGGER IF EXISTS audit_entries_no_truncate ON audit_entries;
+	CREATE TRIGGER audit_entries_no_truncate BEFORE TRUNCATE ON audit_entries
+		FOR EACH STATEMENT EXECUTE FUNCTION audit_entries_append_only();`).Error
+	if err != nil {
+		log.Fatalf("failed to protect the audit log: %v", err)
+	}
 	log.Println("database migrated")
 }
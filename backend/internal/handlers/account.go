@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"quiz-game-backend/internal/services"
+	"quiz-game-backend/internal/ws"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler struct {
+	account     *services.AccountService
+	roomService *services.RoomService
+	hub         *ws.Hub
+}
+
+func NewAccountHandler(account *services.AccountService, roomService *services.RoomService, hub *ws.Hub) *AccountHandler {
+	return &AccountHandler{account: account, roomService: roomService, hub: hub}
+}
+
+type ChangePasswordRequest struct {
+	// CurrentPassword is empty for accounts created by single sign-on, which
+	// need a recent single sign-on login instead.
+	CurrentPassword string `json:"current_password" example:"password123"`
+	NewPassword     string `json:"new_password" binding:"required,min=6,max=72" example:"new-password"`
+}
+
+type ChangeUsernameRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=100" example:"host2"`
+}
+
+type DeleteAccountRequest struct {
+	// Username must be typed to confirm the deletion.
+	Username string `json:"username" binding:"required" example:"host1"`
+	Password string `json:"password" example:"password123"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6,max=72" example:"new-password"`
+}
+
+type IssueResetRequest struct {
+	Username string `json:"username" binding:"required" example:"host1"`
+}
+
+type IssueResetResponse struct {
+	Message   string    `json:"message" example:"reset link sent"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func accountStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrWrongPassword), errors.Is(err, services.ErrUsernameNotConfirm),
+		errors.Is(err, services.ErrReauthRequired):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrInvalidResetToken), errors.Is(err, services.ErrInvalidUsername):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrUsernameTaken):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrUserNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// ChangePassword godoc
+// @Summary      Change password
+// @Description  Set a new password after confirming the current one. Accounts without a password need a single sign-on login from the last 10 minutes instead. Other devices are signed out; this login stays
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body ChangePasswordRequest true "Current and new password"
+// @Success      200 {object} MessageResponse
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Router       /api/v1/auth/password [put]
+func (h *AccountHandler) ChangePassword(c *gin.Context) {
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.account.ChangePassword(c.GetUint("user_id"), c.GetUint("auth_session_id"), req.CurrentPassword, req.NewPassword); err != nil {
+		c.JSON(accountStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "password changed"})
+}
+
+// ChangeUsername godoc
+// @Summary      Change username
+// @Description  Rename the account. The new name is used to log in
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body ChangeUsernameRequest true "New username"
+// @Success      200 {object} map[string]interface{}
+// @Failure      400 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      409 {object} ErrorResponse
+// @Router       /api/v1/auth/username [put]
+func (h *AccountHandler) ChangeUsername(c *gin.Context) {
+	var req ChangeUsernameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	username, err := h.account.ChangeUsername(c.GetUint("user_id"), req.Username)
+	if err != nil {
+		c.JSON(accountStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "username changed", "username": username})
+}
+
+// DeleteAccount godoc
+// @Summary      Delete account
+// @Description  Delete the account with its quizzes, rooms, sessions, bot settings and uploaded media. Open rooms are closed. Requires the username typed for confirmation and the password, or for accounts without one a single sign-on login from the last 10 minutes. Cannot be undone
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body DeleteAccountRequest true "Confirmation"
+// @Success      200 {object} MessageResponse
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Router       /api/v1/auth/account [delete]
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	hostID := c.GetUint("user_id")
+	rooms, _ := h.roomService.GetActiveRooms(hostID)
+
+	if err := h.account.DeleteAccount(hostID, c.GetUint("auth_session_id"), req.Username, req.Password); err != nil {
+		c.JSON(accountStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	for _, room := range rooms {
+		h.hub.BroadcastToRoom(room.ID, ws.WSMessage{Type: "room_closed", Data: nil})
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "account deleted"})
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Set a new password with a reset token issued by an administrator. The token works once; every device of the account is signed out
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ResetPasswordRequest true "Reset token and new password"
+// @Success      200 {object} MessageResponse
+// @Failure      400 {object} ErrorResponse
+// @Router       /api/v1/auth/password/reset [post]
+func (h *AccountHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.account.ResetPassword(req.Token, req.NewPassword); err != nil {
+		c.JSON(accountStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "password reset"})
+}
+
+// IssuePasswordReset godoc
+// @Summary      Issue a password reset
+// @Description  Create a one-time password reset link for an account and deliver it through the configured notifier. The token is not returned. Requires the administrator key
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        X-Admin-API-Key header string true "Administrator key"
+// @Param        request body IssueResetRequest true "Account"
+// @Success      200 {object} IssueResetResponse
+// @Failure      401 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/admin/password-resets [post]
+func (h *AccountHandler) IssuePasswordReset(c *gin.Context) {
+	var req IssueResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	expiresAt, err := h.account.IssueReset(req.Username)
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, services.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, IssueResetResponse{Message: "reset link sent", ExpiresAt: expiresAt})
+}
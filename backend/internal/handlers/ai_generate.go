@@ -11,12 +11,14 @@ import (
 type AIGenerateHandler struct {
 	quizService *services.QuizService
 	aiService   *services.AIGenerateService
+	audit       *services.AuditService
 }
 
-func NewAIGenerateHandler(quizService *services.QuizService, aiService *services.AIGenerateService) *AIGenerateHandler {
+func NewAIGenerateHandler(quizService *services.QuizService, aiService *services.AIGenerateService, audit *services.AuditService) *AIGenerateHandler {
 	return &AIGenerateHandler{
 		quizService: quizService,
 		aiService:   aiService,
+		audit:       audit,
 	}
 }
 
@@ -82,6 +84,7 @@ func (h *AIGenerateHandler) Generate(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to import generated questions: " + err.Error()})
 		return
 	}
+	h.audit.Record(hostID, auditActor(c), services.AuditQuizCreate, services.AuditTargetQuiz, quiz.ID, nil, h.audit.QuizSummary(quiz.ID))
 
 	fullQuiz, _ := h.quizService.GetQuizByID(quiz.ID, hostID)
 
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"quiz-game-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	keyService *services.APIKeyService
+}
+
+func NewAPIKeyHandler(keyService *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{keyService: keyService}
+}
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required" example:"CI deploy"`
+	Scopes []string `json:"scopes" binding:"required" example:"quizzes:write,rooms:control"`
+	// ExpiresInDays is the key lifetime; 0 means it doesn't expire.
+	ExpiresInDays int `json:"expires_in_days" example:"90"`
+}
+
+type CreateAPIKeyResponse struct {
+	// Key is shown only once. Send it as "Authorization: Bearer <key>" or in X-API-Key.
+	Key    string              `json:"key" example:"qk_9fJc..."`
+	APIKey services.APIKeyInfo `json:"api_key"`
+}
+
+// ListAPIKeys godoc
+// @Summary      List API keys
+// @Description  API keys of the current workspace, without the keys themselves
+// @Tags         api-keys
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Workspace-ID header int false "Workspace ID"
+// @Success      200 {array} services.APIKeyInfo
+// @Router       /api/v1/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.keyService.List(c.GetUint("host_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// CreateAPIKey godoc
+// @Summary      Create API key
+// @Description  Issue a key for scripts with scopes quizzes:read, quizzes:write, rooms:control and analytics:read. The key is returned only in this response
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Workspace-ID header int false "Workspace ID"
+// @Param        request body CreateAPIKeyRequest true "Key settings"
+// @Success      201 {object} CreateAPIKeyResponse
+// @Failure      400 {object} ErrorResponse
+// @Router       /api/v1/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if req.ExpiresInDays < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "expires_in_days must not be negative"})
+		return
+	}
+
+	expiresIn := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	key, info, err := h.keyService.Create(c.GetUint("host_id"), c.GetUint("user_id"), req.Name, req.Scopes, expiresIn)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, CreateAPIKeyResponse{Key: key, APIKey: *info})
+}
+
+// DeleteAPIKey godoc
+// @Summary      Revoke API key
+// @Tags         api-keys
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Workspace-ID header int false "Workspace ID"
+// @Param        id path int true "API key ID"
+// @Success      200 {object} MessageResponse
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/api-keys/{id} [delete]
+func (h *APIKeyHandler) DeleteAPIKey(c *gin.Context) {
+	keyID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid key id"})
+		return
+	}
+	if err := h.keyService.Delete(uint(keyID), c.GetUint("host_id")); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: "API key revoked"})
+}
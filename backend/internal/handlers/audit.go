@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"quiz-game-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	audit *services.AuditService
+}
+
+func NewAuditHandler(audit *services.AuditService) *AuditHandler {
+	return &AuditHandler{audit: audit}
+}
+
+// auditActor is who made a request: the API key it was authenticated with,
+// or the logged-in user.
+func auditActor(c *gin.Context) services.AuditActor {
+	if v, ok := c.Get("api_key"); ok {
+		if key, ok := v.(*services.APIKeyAuth); ok {
+			return services.APIKeyActor(key.KeyID, c.ClientIP())
+		}
+	}
+	return services.UserActor(c.GetUint("user_id"), c.ClientIP())
+}
+
+// ListAudit godoc
+// @Summary      Audit log
+// @Description  Administrative actions in the workspace, newest first: quiz changes, rooms, session control and settings, with who did them. Action takes an exact action such as session.finish or a group such as session. Page back by passing the smallest id seen as before_id
+// @Tags         audit
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Workspace-ID header int false "Workspace ID"
+// @Param        action query string false "Action or action group" example(session.finish)
+// @Param        actor_type query string false "user, api_key, telegram or system"
+// @Param        actor_id query string false "User, API key or Telegram account ID"
+// @Param        target_type query string false "quiz, room, session or settings"
+// @Param        target_id query int false "Target ID"
+// @Param        from query string false "Not before, RFC 3339"
+// @Param        to query string false "Before, RFC 3339"
+// @Param        before_id query int false "Only entries older than this one"
+// @Param        limit query int false "Page size, at most 200" default(50)
+// @Success      200 {array} services.AuditEntryInfo
+// @Failure      400 {object} ErrorResponse
+// @Router       /api/v1/audit [get]
+func (h *AuditHandler) ListAudit(c *gin.Context) {
+	filter := services.AuditFilter{
+		Action:     c.Query("action"),
+		ActorType:  c.Query("actor_type"),
+		ActorID:    c.Query("actor_id"),
+		TargetType: c.Query("target_type"),
+	}
+
+	for name, dest := range map[string]*uint{"target_id": &filter.TargetID, "before_id": &filter.BeforeID} {
+		if v := c.Query(name); v != "" {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid " + name})
+				return
+			}
+			*dest = uint(n)
+		}
+	}
+	for name, dest := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := c.Query(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid " + name})
+				return
+			}
+			*dest = &t
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid limit"})
+			return
+		}
+		filter.Limit = n
+	}
+
+	entries, err := h.audit.List(c.GetUint("host_id"), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
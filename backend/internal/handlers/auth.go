@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"quiz-game-backend/internal/services"
 
@@ -27,12 +30,37 @@ type LoginRequest struct {
 }
 
 type AuthResponse struct {
-	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIs..."`
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIs..."`
+	RefreshToken string `json:"refresh_token" example:"q0Hn3v5s..."`
+	// ExpiresIn is the access token lifetime in seconds.
+	ExpiresIn int `json:"expires_in" example:"900"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LoginSessionResponse struct {
+	ID         uint      `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Method     string    `json:"method" example:"sso"`
+	Current    bool      `json:"current"`
+}
+
+func clientInfo(c *gin.Context) services.ClientInfo {
+	return services.ClientInfo{UserAgent: c.Request.UserAgent(), IP: c.ClientIP()}
+}
+
+func authResponse(pair *services.TokenPair) AuthResponse {
+	return AuthResponse{Token: pair.AccessToken, RefreshToken: pair.RefreshToken, ExpiresIn: pair.ExpiresIn}
 }
 
 // Register godoc
 // @Summary      Register a new host
-// @Description  Create a new host account and return JWT token
+// @Description  Create a new host account and return an access and refresh token pair
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -47,24 +75,25 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authService.Register(req.Username, req.Password)
+	pair, err := h.authService.Register(req.Username, req.Password, clientInfo(c))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, AuthResponse{Token: token})
+	c.JSON(http.StatusCreated, authResponse(pair))
 }
 
 // Login godoc
 // @Summary      Login as host
-// @Description  Authenticate host and return JWT token
+// @Description  Authenticate host, start a login session and return an access and refresh token pair
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        request body LoginRequest true "Login data"
 // @Success      200 {object} AuthResponse
 // @Failure      401 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse "The account requires single sign-on"
 // @Router       /api/v1/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
@@ -73,11 +102,150 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authService.Login(req.Username, req.Password)
+	pair, err := h.authService.Login(req.Username, req.Password, clientInfo(c))
+	if errors.Is(err, services.ErrSSORequired) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, AuthResponse{Token: token})
+	c.JSON(http.StatusOK, authResponse(pair))
+}
+
+// Refresh godoc
+// @Summary      Refresh access token
+// @Description  Exchange a refresh token for a new token pair. The refresh token is rotated: the old one stops working, and reusing it later ends the login session
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body RefreshRequest true "Refresh token"
+// @Success      200 {object} AuthResponse
+// @Failure      401 {object} ErrorResponse
+// @Router       /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	pair, err := h.authService.Refresh(req.RefreshToken, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenRotated) {
+			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, authResponse(pair))
+}
+
+// Logout godoc
+// @Summary      Log out
+// @Description  End the login session the refresh token belongs to. Works with an expired access token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body RefreshRequest true "Refresh token"
+// @Success      200 {object} MessageResponse
+// @Failure      400 {object} ErrorResponse
+// @Router       /api/v1/auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.authService.Logout(req.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "logged out"})
+}
+
+// LogoutAll godoc
+// @Summary      Sign out all devices
+// @Description  End every login session of the host, including the current one
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} map[string]interface{}
+// @Router       /api/v1/auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	hostID := c.GetUint("user_id")
+
+	count, err := h.authService.RevokeAllSessions(hostID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out everywhere", "revoked": count})
+}
+
+// ListSessions godoc
+// @Summary      List login sessions
+// @Description  Active login sessions of the host; the one making the request is marked current
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} LoginSessionResponse
+// @Router       /api/v1/auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	hostID := c.GetUint("user_id")
+	current := c.GetUint("auth_session_id")
+
+	sessions, err := h.authService.ListSessions(hostID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	result := make([]LoginSessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		result = append(result, LoginSessionResponse{
+			ID:         s.ID,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+			Method:     s.Method,
+			Current:    s.ID == current,
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RevokeSession godoc
+// @Summary      End a login session
+// @Description  Sign out one device. Its access token stops working immediately
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Login session ID"
+// @Success      200 {object} MessageResponse
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	hostID := c.GetUint("user_id")
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid session id"})
+		return
+	}
+
+	if err := h.authService.RevokeSession(uint(sessionID), hostID); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "session revoked"})
 }
@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"quiz-game-backend/internal/models"
+	"quiz-game-backend/internal/services"
+	"quiz-game-backend/internal/ws"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChatHandler struct {
+	chatService     *services.ChatService
+	roomService     *services.RoomService
+	presenceService *services.PresenceService
+	playerTokens    *services.PlayerTokenService
+	hub             *ws.Hub
+}
+
+func NewChatHandler(chatService *services.ChatService, roomService *services.RoomService, presenceService *services.PresenceService, playerTokens *services.PlayerTokenService, hub *ws.Hub) *ChatHandler {
+	return &ChatHandler{chatService: chatService, roomService: roomService, presenceService: presenceService, playerTokens: playerTokens, hub: hub}
+}
+
+type PlayReactionRequest struct {
+	Token    string `json:"token" binding:"required"`
+	RoomCode string `json:"room_code" binding:"required"`
+	Emoji    string `json:"emoji" binding:"required"`
+}
+
+type PlayChatRequest struct {
+	Token    string `json:"token" binding:"required"`
+	RoomCode string `json:"room_code" binding:"required"`
+	Text     string `json:"text" binding:"required,max=1000"`
+}
+
+type ChatMuteRequest struct {
+	MemberID   uint  `json:"member_id"`
+	TelegramID int64 `json:"telegram_id"`
+	Muted      bool  `json:"muted"`
+}
+
+type ChatHistoryResponse struct {
+	Messages  []models.ChatMessage `json:"messages"`
+	Reactions []string             `json:"reactions"`
+}
+
+func (h *ChatHandler) member(code, token string) (*services.RoomWithMembers, *models.RoomMember, error) {
+	room, err := h.roomService.GetRoomByCode(code)
+	if err != nil {
+		return nil, nil, err
+	}
+	member, err := h.playerTokens.Member(token, room.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return room, member, nil
+}
+
+// React godoc
+// @Summary      Send an emoji reaction
+// @Description  Broadcast a reaction from a web player to everyone in the room. Rate-limited per player.
+// @Tags         play
+// @Accept       json
+// @Produce      json
+// @Param        request body PlayReactionRequest true "Reaction"
+// @Success      200 {object} services.ReactionEvent
+// @Failure      400 {object} ErrorResponse
+// @Failure      401 {object} ErrorResponse
+// @Failure      429 {object} ErrorResponse
+// @Router       /api/v1/play/react [post]
+func (h *ChatHandler) React(c *gin.Context) {
+	var req PlayReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	room, member, err := h.member(req.RoomCode, req.Token)
+	if err != nil {
+		c.JSON(playerTokenStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	sender := services.ChatSender{MemberID: member.ID, Nickname: member.Nickname}
+	event, err := h.chatService.SendReaction(room.ID, sender, req.Emoji)
+	if err != nil {
+		c.JSON(chatErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.presenceService.TouchMember(room.ID, member.ID)
+
+	h.hub.BroadcastToRoom(room.ID, ws.WSMessage{Type: "reaction", Data: event})
+	c.JSON(http.StatusOK, event)
+}
+
+// SendMessage godoc
+// @Summary      Post a chat message
+// @Description  Post a short chat message to the room. Rate-limited, and refused when the player is muted or chat is paused.
+// @Tags         play
+// @Accept       json
+// @Produce      json
+// @Param        request body PlayChatRequest true "Message"
+// @Success      201 {object} models.ChatMessage
+// @Failure      400 {object} ErrorResponse
+// @Failure      401 {object} ErrorResponse
+// @Failure      429 {object} ErrorResponse
+// @Router       /api/v1/play/chat [post]
+func (h *ChatHandler) SendMessage(c *gin.Context) {
+	var req PlayChatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	room, member, err := h.member(req.RoomCode, req.Token)
+	if err != nil {
+		c.JSON(playerTokenStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	sender := services.ChatSender{MemberID: member.ID, Nickname: member.Nickname}
+	msg, err := h.chatService.SendMessage(room.ID, sender, req.Text)
+	if err != nil {
+		c.JSON(chatErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.presenceService.TouchMember(room.ID, member.ID)
+
+	h.hub.BroadcastToRoom(room.ID, ws.WSMessage{Type: "chat_message", Data: msg})
+	c.JSON(http.StatusCreated, msg)
+}
+
+// PlayHistory godoc
+// @Summary      Get room chat history
+// @Tags         play
+// @Produce      json
+// @Param        code  query string true "Room code"
+// @Param        token query string true "Player token"
+// @Success      200 {object} ChatHistoryResponse
+// @Failure      401 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/play/chat [get]
+func (h *ChatHandler) PlayHistory(c *gin.Context) {
+	room, _, err := h.member(c.Query("code"), c.Query("token"))
+	if err != nil {
+		c.JSON(playerTokenStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.writeHistory(c, room.ID)
+}
+
+// RoomHistory godoc
+// @Summary      Get room chat history (host)
+// @Tags         rooms
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Room ID"
+// @Success      200 {object} ChatHistoryResponse
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/rooms/{id}/chat [get]
+func (h *ChatHandler) RoomHistory(c *gin.Context) {
+	roomID, ok := h.hostRoomID(c)
+	if !ok {
+		return
+	}
+	h.writeHistory(c, roomID)
+}
+
+func (h *ChatHandler) writeHistory(c *gin.Context, roomID uint) {
+	messages, err := h.chatService.ListMessages(roomID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ChatHistoryResponse{Messages: messages, Reactions: services.Reactions})
+}
+
+// ClearChat godoc
+// @Summary      Clear room chat
+// @Tags         rooms
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Room ID"
+// @Success      200 {object} MessageResponse
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/rooms/{id}/chat [delete]
+func (h *ChatHandler) ClearChat(c *gin.Context) {
+	hostID := c.GetUint("host_id")
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid room id"})
+		return
+	}
+
+	if err := h.chatService.ClearChat(uint(roomID), hostID); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	h.hub.BroadcastToRoom(uint(roomID), ws.WSMessage{Type: "chat_cleared", Data: gin.H{"room_id": roomID}})
+	c.JSON(http.StatusOK, MessageResponse{Message: "chat cleared"})
+}
+
+// UpdateSettings godoc
+// @Summary      Update room chat settings
+// @Description  Disable chat entirely or pause it while a question is open
+// @Tags         rooms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path int                   true "Room ID"
+// @Param        request body services.ChatSettings true "Chat settings"
+// @Success      200 {object} services.ChatSettings
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/rooms/{id}/chat/settings [put]
+func (h *ChatHandler) UpdateSettings(c *gin.Context) {
+	hostID := c.GetUint("host_id")
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid room id"})
+		return
+	}
+
+	var req services.ChatSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	settings, err := h.chatService.UpdateSettings(uint(roomID), hostID, req)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	h.hub.BroadcastToRoom(uint(roomID), ws.WSMessage{Type: "chat_settings", Data: settings})
+	c.JSON(http.StatusOK, settings)
+}
+
+// Mute godoc
+// @Summary      Mute or unmute a player
+// @Description  Muted players cannot chat or react. Identify the player by member_id (web) or telegram_id.
+// @Tags         rooms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path int             true "Room ID"
+// @Param        request body ChatMuteRequest true "Mute"
+// @Success      200 {object} MessageResponse
+// @Failure      400 {object} ErrorResponse
+// @Router       /api/v1/rooms/{id}/chat/mute [post]
+func (h *ChatHandler) Mute(c *gin.Context) {
+	hostID := c.GetUint("host_id")
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid room id"})
+		return
+	}
+
+	var req ChatMuteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.chatService.SetMuted(uint(roomID), hostID, req.MemberID, req.TelegramID, req.Muted); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	h.hub.BroadcastToRoom(uint(roomID), ws.WSMessage{Type: "member_muted", Data: req})
+	c.JSON(http.StatusOK, MessageResponse{Message: "ok"})
+}
+
+// ListMutes godoc
+// @Summary      List muted players
+// @Tags         rooms
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Room ID"
+// @Success      200 {array} models.RoomMute
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/rooms/{id}/chat/mutes [get]
+func (h *ChatHandler) ListMutes(c *gin.Context) {
+	roomID, ok := h.hostRoomID(c)
+	if !ok {
+		return
+	}
+	mutes, err := h.chatService.ListMutes(roomID, c.GetUint("host_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mutes)
+}
+
+func (h *ChatHandler) hostRoomID(c *gin.Context) (uint, bool) {
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid room id"})
+		return 0, false
+	}
+	room, err := h.roomService.GetRoom(uint(roomID))
+	if err != nil || room.HostID != c.GetUint("host_id") {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "room not found"})
+		return 0, false
+	}
+	return room.ID, true
+}
+
+func chatErrorStatus(err error) int {
+	switch err.Error() {
+	case "too many reactions, slow down", "too many messages, slow down":
+		return http.StatusTooManyRequests
+	case "you are muted in this room", "chat is disabled in this room", "chat is paused while a question is open":
+		return http.StatusForbidden
+	case "room not found":
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"quiz-game-backend/internal/models"
+	"quiz-game-backend/internal/services"
+	"quiz-game-backend/internal/ws"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DisplayHandler struct {
+	roomService    *services.RoomService
+	sessionService *services.SessionService
+	hub            *ws.Hub
+}
+
+func NewDisplayHandler(roomService *services.RoomService, sessionService *services.SessionService, hub *ws.Hub) *DisplayHandler {
+	return &DisplayHandler{roomService: roomService, sessionService: sessionService, hub: hub}
+}
+
+type DisplayTokenResponse struct {
+	Token string `json:"token"`
+}
+
+type DisplayState struct {
+	Room         models.Room                      `json:"room"`
+	MemberCount  int                              `json:"member_count"`
+	Session      *services.SessionState           `json:"session"`
+	Distribution *services.AnswerDistribution     `json:"distribution,omitempty"`
+	Leaderboard  []services.LeaderboardDeltaEntry `json:"leaderboard,omitempty"`
+}
+
+// RotateDisplayToken godoc
+// @Summary      Issue a display token
+// @Description  Generate a new token for the projector view of a room. The previous token stops working.
+// @Tags         display
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Room ID"
+// @Success      200 {object} DisplayTokenResponse
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/rooms/{id}/display-token [post]
+func (h *DisplayHandler) RotateDisplayToken(c *gin.Context) {
+	hostID := c.GetUint("host_id")
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid room id"})
+		return
+	}
+
+	token, previous, err := h.roomService.RotateDisplayToken(uint(roomID), hostID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if previous != "" {
+		h.hub.DisconnectDisplay(previous, ws.WSMessage{Type: "display_revoked", Data: nil})
+	}
+
+	c.JSON(http.StatusOK, DisplayTokenResponse{Token: token})
+}
+
+// GetState godoc
+// @Summary      Get display state
+// @Description  Current question with media, live answer count, revealed distribution and leaderboard for a projector
+// @Tags         display
+// @Produce      json
+// @Param        token path string true "Display token"
+// @Success      200 {object} DisplayState
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/display/{token} [get]
+func (h *DisplayHandler) GetState(c *gin.Context) {
+	room, err := h.roomService.GetRoomByDisplayToken(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.buildState(room))
+}
+
+func (h *DisplayHandler) buildState(room *services.RoomWithMembers) DisplayState {
+	state := DisplayState{Room: room.Room, MemberCount: len(room.Members)}
+
+	currentSession, _ := h.roomService.GetCurrentSession(room.ID)
+	if currentSession == nil {
+		currentSession, _ = h.roomService.GetLatestSession(room.ID)
+	}
+	if currentSession == nil {
+		return state
+	}
+
+	state.Session, _ = h.sessionService.GetSession(currentSession.ID)
+	if state.Session == nil {
+		return state
+	}
+
+	if state.Session.Status == models.SessionStatusRevealed || state.Session.Status == models.SessionStatusFinished {
+		state.Distribution, _ = h.sessionService.GetAnswerDistribution(currentSession.ID)
+		state.Leaderboard, _ = h.sessionService.GetLeaderboardWithDelta(currentSession.ID)
+	}
+	return state
+}
+
+// HandleWebSocket godoc
+// @Summary      WebSocket feed for a projector
+// @Description  Receives the room broadcasts; the first message is the full display state
+// @Tags         websocket
+// @Param        token path string true "Display token"
+// @Router       /ws/display/{token} [get]
+func (h *DisplayHandler) HandleWebSocket(c *gin.Context) {
+	room, err := h.roomService.GetRoomByDisplayToken(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "room not found"})
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("websocket upgrade error: %v", err)
+		return
+	}
+
+	// Written before the connection joins the hub, so no concurrent writers yet.
+	if err := conn.WriteJSON(ws.WSMessage{Type: "display_state", Data: h.buildState(room)}); err != nil {
+		conn.Close()
+		return
+	}
+
+	h.hub.AddRoomConnection(room.ID, conn)
+	h.hub.AddDisplayConnection(room.DisplayToken, conn)
+	defer h.hub.RemoveDisplayConnection(room.DisplayToken, conn)
+	defer h.hub.RemoveRoomConnection(room.ID, conn)
+
+	for {
+		_, _, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"quiz-game-backend/internal/services"
+	"quiz-game-backend/internal/ws"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KickRequest struct {
+	MemberID   uint  `json:"member_id"`
+	TelegramID int64 `json:"telegram_id"`
+	Ban        bool  `json:"ban"`
+}
+
+type RenameMemberRequest struct {
+	MemberID   uint   `json:"member_id"`
+	TelegramID int64  `json:"telegram_id"`
+	Nickname   string `json:"nickname" binding:"required,min=1,max=100"`
+}
+
+// KickMember godoc
+// @Summary      Kick a player
+// @Description  Remove a web member (member_id) or Telegram player (telegram_id) from the room. With ban, they cannot rejoin.
+// @Tags         rooms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path int         true "Room ID"
+// @Param        request body KickRequest true "Player to kick"
+// @Success      200 {object} services.KickResult
+// @Failure      400 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/rooms/{id}/kick [post]
+func (h *RoomHandler) KickMember(c *gin.Context) {
+	hostID := c.GetUint("host_id")
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid room id"})
+		return
+	}
+
+	var req KickRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ref := services.PlayerRef{MemberID: req.MemberID, TelegramID: req.TelegramID}
+	result, err := h.roomService.KickPlayer(uint(roomID), hostID, ref, req.Ban)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RenameMember godoc
+// @Summary      Force-rename a player
+// @Tags         rooms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path int                 true "Room ID"
+// @Param        request body RenameMemberRequest true "New nickname"
+// @Success      200 {object} services.RenameResult
+// @Failure      400 {object} ErrorResponse
+// @Router       /api/v1/rooms/{id}/rename [put]
+func (h *RoomHandler) RenameMember(c *gin.Context) {
+	hostID := c.GetUint("host_id")
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid room id"})
+		return
+	}
+
+	var req RenameMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ref := services.PlayerRef{MemberID: req.MemberID, TelegramID: req.TelegramID}
+	result, err := h.roomService.RenameMember(uint(roomID), hostID, ref, req.Nickname)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	h.hub.BroadcastToRoom(uint(roomID), ws.WSMessage{Type: "member_renamed", Data: result})
+	c.JSON(http.StatusOK, result)
+}
+
+// ListBans godoc
+// @Summary      List banned players
+// @Tags         rooms
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Room ID"
+// @Success      200 {array} models.RoomBan
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/rooms/{id}/bans [get]
+func (h *RoomHandler) ListBans(c *gin.Context) {
+	hostID := c.GetUint("host_id")
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid room id"})
+		return
+	}
+
+	bans, err := h.roomService.ListBans(uint(roomID), hostID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, bans)
+}
+
+// Unban godoc
+// @Summary      Lift a ban
+// @Tags         rooms
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path int true "Room ID"
+// @Param        banId path int true "Ban ID"
+// @Success      200 {object} MessageResponse
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/rooms/{id}/bans/{banId} [delete]
+func (h *RoomHandler) Unban(c *gin.Context) {
+	hostID := c.GetUint("host_id")
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid room id"})
+		return
+	}
+	banID, err := strconv.ParseUint(c.Param("banId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid ban id"})
+		return
+	}
+
+	if err := h.roomService.Unban(uint(roomID), hostID, uint(banID)); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: "ban lifted"})
+}
+
+// joinErrorStatus maps join failures to a status so clients can tell a ban apart.
+func joinErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrBannedFromRoom):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrNicknameBlocked):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrRoomNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"quiz-game-backend/internal/models"
+	"quiz-game-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCHandler struct {
+	oidc *services.OIDCService
+}
+
+func NewOIDCHandler(oidc *services.OIDCService) *OIDCHandler {
+	return &OIDCHandler{oidc: oidc}
+}
+
+type OIDCConfigResponse struct {
+	Enabled bool   `json:"enabled"`
+	Name    string `json:"name" example:"Corporate SSO"`
+}
+
+type OIDCStartResponse struct {
+	// URL is the provider page to send the browser to.
+	URL string `json:"url"`
+}
+
+// OIDCCallbackRequest carries the code and state the provider appended to
+// the redirect URL.
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+type OIDCLoginResponse struct {
+	AuthResponse
+	Username string `json:"username" example:"host1"`
+}
+
+// oidcStateCookie holds the state of the sign-in started by this browser, so
+// a callback carrying a state from someone else's sign-in is refused.
+const (
+	oidcStateCookie     = "oidc_state"
+	oidcStateCookiePath = "/api/v1/auth"
+)
+
+func setOIDCStateCookie(c *gin.Context, state string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, maxAge, oidcStateCookiePath, "", secure, true)
+}
+
+// checkOIDCState requires the state of a callback to be the one this browser
+// started, and clears the cookie.
+func checkOIDCState(c *gin.Context, state string) error {
+	cookie, err := c.Cookie(oidcStateCookie)
+	setOIDCStateCookie(c, "", -1)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie), []byte(state)) != 1 {
+		return services.ErrOIDCInvalidState
+	}
+	return nil
+}
+
+func oidcErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrOIDCDisabled):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrOIDCInvalidState):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrIdentityNotLinked):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrIdentityLinked):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrOIDCProvider):
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
+// GetConfig godoc
+// @Summary      Single sign-on availability
+// @Description  Whether login with the identity provider is available, and its name for the login button
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} OIDCConfigResponse
+// @Router       /api/v1/auth/oidc [get]
+func (h *OIDCHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, OIDCConfigResponse{Enabled: h.oidc.Enabled(), Name: h.oidc.Name()})
+}
+
+// Start godoc
+// @Summary      Start single sign-on
+// @Description  Start a login at the identity provider. The browser is sent to the returned URL and comes back to the configured redirect page with code and state. The state is also set in an HttpOnly cookie that the callback checks
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} OIDCStartResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      502 {object} ErrorResponse
+// @Router       /api/v1/auth/oidc/start [post]
+func (h *OIDCHandler) Start(c *gin.Context) {
+	u, state, err := h.oidc.AuthURL(0)
+	if err != nil {
+		c.JSON(oidcErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	setOIDCStateCookie(c, state, int(services.OIDCStateTTL.Seconds()))
+	c.JSON(http.StatusOK, OIDCStartResponse{URL: u})
+}
+
+// Callback godoc
+// @Summary      Finish single sign-on
+// @Description  Redeem the code from the identity provider and log in to the account the identity is linked to. The state has to match the cookie set when this browser started the sign-in
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body OIDCCallbackRequest true "Code and state from the provider"
+// @Success      200 {object} OIDCLoginResponse
+// @Failure      400 {object} ErrorResponse
+// @Failure      401 {object} ErrorResponse
+// @Failure      502 {object} ErrorResponse
+// @Router       /api/v1/auth/oidc/callback [post]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	var req OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := checkOIDCState(c, req.State); err != nil {
+		c.JSON(oidcErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	pair, host, err := h.oidc.Login(req.Code, req.State, clientInfo(c))
+	if err != nil {
+		c.JSON(oidcErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OIDCLoginResponse{AuthResponse: authResponse(pair), Username: host.Username})
+}
+
+// StartLink godoc
+// @Summary      Start linking an identity
+// @Description  Start a login at the identity provider whose identity will be linked to the current account
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} OIDCStartResponse
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/auth/identities/link [post]
+func (h *OIDCHandler) StartLink(c *gin.Context) {
+	u, state, err := h.oidc.AuthURL(c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(oidcErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	setOIDCStateCookie(c, state, int(services.OIDCStateTTL.Seconds()))
+	c.JSON(http.StatusOK, OIDCStartResponse{URL: u})
+}
+
+// FinishLink godoc
+// @Summary      Finish linking an identity
+// @Description  Redeem the code from the identity provider and link the identity to the current account. The sign-in has to have been started by the same account
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body OIDCCallbackRequest true "Code and state from the provider"
+// @Success      201 {object} models.HostIdentity
+// @Failure      400 {object} ErrorResponse
+// @Failure      409 {object} ErrorResponse
+// @Router       /api/v1/auth/identities/callback [post]
+func (h *OIDCHandler) FinishLink(c *gin.Context) {
+	var req OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := checkOIDCState(c, req.State); err != nil {
+		c.JSON(oidcErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	identity, err := h.oidc.Link(req.Code, req.State, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(oidcErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, identity)
+}
+
+// ListIdentities godoc
+// @Summary      List linked identities
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} models.HostIdentity
+// @Router       /api/v1/auth/identities [get]
+func (h *OIDCHandler) ListIdentities(c *gin.Context) {
+	identities, err := h.oidc.Identities(c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if identities == nil {
+		identities = []models.HostIdentity{}
+	}
+	c.JSON(http.StatusOK, identities)
+}
+
+// Unlink godoc
+// @Summary      Unlink an identity
+// @Description  Remove a linked identity. The last one can't be removed while the account has no password or requires single sign-on
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Identity ID"
+// @Success      200 {object} MessageResponse
+// @Failure      400 {object} ErrorResponse
+// @Router       /api/v1/auth/identities/{id} [delete]
+func (h *OIDCHandler) Unlink(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid identity id"})
+		return
+	}
+
+	if err := h.oidc.Unlink(uint(id), c.GetUint("user_id")); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: "identity unlinked"})
+}
@@ -50,7 +50,7 @@ func (h *ParticipantHandler) JoinSession(c *gin.Context) {
 
 	result, err := h.sessionService.JoinSession(req.Code, req.TelegramID, req.Nickname)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		c.JSON(joinErrorStatus(err), ErrorResponse{Error: err.Error()})
 		return
 	}
 
@@ -92,11 +92,6 @@ func (h *ParticipantHandler) SubmitAnswer(c *gin.Context) {
 		return
 	}
 
-	h.hub.Broadcast(uint(sessionID), ws.WSMessage{
-		Type: "answer_received",
-		Data: gin.H{"session_id": sessionID},
-	})
-
 	c.JSON(http.StatusOK, MessageResponse{Message: "answer accepted"})
 }
 
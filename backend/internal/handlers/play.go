@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
 
+	"quiz-game-backend/internal/models"
 	"quiz-game-backend/internal/services"
 	"quiz-game-backend/internal/ws"
 
@@ -13,24 +15,35 @@ import (
 )
 
 type PlayHandler struct {
-	roomService    *services.RoomService
-	sessionService *services.SessionService
-	hub            *ws.Hub
+	roomService     *services.RoomService
+	sessionService  *services.SessionService
+	presenceService *services.PresenceService
+	playerTokens    *services.PlayerTokenService
+	hub             *ws.Hub
 }
 
-func NewPlayHandler(roomService *services.RoomService, sessionService *services.SessionService, hub *ws.Hub) *PlayHandler {
-	return &PlayHandler{roomService: roomService, sessionService: sessionService, hub: hub}
+func NewPlayHandler(roomService *services.RoomService, sessionService *services.SessionService, presenceService *services.PresenceService, playerTokens *services.PlayerTokenService, hub *ws.Hub) *PlayHandler {
+	return &PlayHandler{roomService: roomService, sessionService: sessionService, presenceService: presenceService, playerTokens: playerTokens, hub: hub}
 }
 
+// PlayJoinRequest joins a room. Token is the player token from an earlier
+// join, if any, so a returning browser gets its membership back.
 type PlayJoinRequest struct {
 	Code     string `json:"code" binding:"required"`
 	Nickname string `json:"nickname" binding:"required,min=1,max=100"`
-	Token    string `json:"token" binding:"required"`
+	Token    string `json:"token"`
+}
+
+type PlayReconnectRequest struct {
+	Token string `json:"token" binding:"required"`
+	Code  string `json:"code" binding:"required"`
 }
 
+// PlayAnswerRequest submits an answer. The player is taken from Token;
+// MemberID is optional and only checked against it.
 type PlayAnswerRequest struct {
 	SessionID uint   `json:"session_id" binding:"required"`
-	MemberID  uint   `json:"member_id" binding:"required"`
+	MemberID  uint   `json:"member_id"`
 	Token     string `json:"token" binding:"required"`
 	OptionID  uint   `json:"option_id" binding:"required"`
 }
@@ -48,9 +61,24 @@ func (h *PlayHandler) Join(c *gin.Context) {
 		return
 	}
 
-	result, err := h.roomService.JoinRoom(req.Code, req.Nickname, req.Token, 0)
+	deviceID, err := h.playerTokens.DeviceID(req.Token)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to issue player token"})
+		return
+	}
+	result, err := h.roomService.JoinRoom(req.Code, req.Nickname, deviceID, 0)
+	if err != nil {
+		c.JSON(joinErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	// A rejoin takes over the membership, so earlier tokens for it are revoked.
+	issue := h.playerTokens.Issue
+	if result.IsRejoin {
+		issue = h.playerTokens.Reissue
+	}
+	issued, err := issue(&result.Member)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to issue player token"})
 		return
 	}
 
@@ -84,41 +112,50 @@ func (h *PlayHandler) Join(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"room":            result.Room,
-		"member":          result.Member,
-		"members":         members,
-		"is_rejoin":       result.IsRejoin,
-		"current_session": sessionState,
-		"leaderboard":     leaderboard,
+		"room":             result.Room,
+		"member":           result.Member,
+		"members":          members,
+		"is_rejoin":        result.IsRejoin,
+		"current_session":  sessionState,
+		"leaderboard":      leaderboard,
+		"token":            issued.Token,
+		"token_expires_at": issued.ExpiresAt,
 	})
 }
 
+// Reconnect exchanges a player token, which may have recently expired, for a
+// new one and returns the room state. The old token stops working.
 func (h *PlayHandler) Reconnect(c *gin.Context) {
-	token := c.Query("token")
-	code := c.Query("code")
-	if token == "" || code == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "token and code required"})
+	var req PlayReconnectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	result, err := h.roomService.Reconnect(token, code)
+	room, err := h.roomService.GetRoomByCode(req.Code)
 	if err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	currentSession, _ := h.roomService.GetCurrentSession(result.Room.ID)
+	member, issued, err := h.playerTokens.Rotate(req.Token, room.ID)
+	if err != nil {
+		c.JSON(playerTokenStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	currentSession, _ := h.roomService.GetCurrentSession(room.ID)
 	if currentSession == nil {
-		currentSession, _ = h.roomService.GetLatestSession(result.Room.ID)
+		currentSession, _ = h.roomService.GetLatestSession(room.ID)
 	}
 	var sessionState *services.SessionState
 	var myResult *services.ParticipantResult
 	if currentSession != nil {
 		sessionState, _ = h.sessionService.GetSession(currentSession.ID)
-		myResult, _ = h.sessionService.GetParticipantResultByMember(currentSession.ID, result.Member.ID)
+		myResult, _ = h.sessionService.GetParticipantResultByMember(currentSession.ID, member.ID)
 	}
 
-	members, _ := h.roomService.ListMembers(result.Room.ID)
+	members, _ := h.roomService.ListMembers(room.ID)
 
 	var leaderboard []services.LeaderboardEntry
 	if sessionState != nil && sessionState.Status == "finished" {
@@ -126,16 +163,44 @@ func (h *PlayHandler) Reconnect(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"room":            result.Room,
-		"member":          result.Member,
-		"members":         members,
-		"is_rejoin":       true,
-		"current_session": sessionState,
-		"my_result":       myResult,
-		"leaderboard":     leaderboard,
+		"room":             room.Room,
+		"member":           member,
+		"members":          members,
+		"is_rejoin":        true,
+		"current_session":  sessionState,
+		"my_result":        myResult,
+		"leaderboard":      leaderboard,
+		"token":            issued.Token,
+		"token_expires_at": issued.ExpiresAt,
 	})
 }
 
+// sessionMember returns the player a token belongs to, who has to be in the
+// room of the session. memberID, when given, has to be that player.
+func (h *PlayHandler) sessionMember(sessionID, memberID uint, token string) (*services.SessionState, *models.RoomMember, int, error) {
+	session, err := h.sessionService.GetSession(sessionID)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, err
+	}
+	member, err := h.playerTokens.Member(token, session.RoomID)
+	if err != nil {
+		return nil, nil, playerTokenStatus(err), err
+	}
+	if memberID != 0 && memberID != member.ID {
+		return nil, nil, http.StatusForbidden, errors.New("token does not belong to this member")
+	}
+	return session, member, 0, nil
+}
+
+// playerTokenStatus tells clients to reconnect when their token is no longer
+// accepted, as opposed to the member being gone.
+func playerTokenStatus(err error) int {
+	if errors.Is(err, services.ErrInvalidPlayerToken) || errors.Is(err, services.ErrPlayerTokenRotated) {
+		return http.StatusUnauthorized
+	}
+	return http.StatusNotFound
+}
+
 func (h *PlayHandler) Answer(c *gin.Context) {
 	var req PlayAnswerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -143,24 +208,17 @@ func (h *PlayHandler) Answer(c *gin.Context) {
 		return
 	}
 
-	if err := h.sessionService.SubmitAnswerByMember(req.SessionID, req.MemberID, req.OptionID); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	session, member, status, err := h.sessionMember(req.SessionID, req.MemberID, req.Token)
+	if err != nil {
+		c.JSON(status, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	var session services.SessionState
-	if s, err := h.sessionService.GetSession(req.SessionID); err == nil {
-		session = *s
+	if err := h.sessionService.SubmitAnswerByMember(req.SessionID, member.ID, req.OptionID); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
 	}
-
-	h.hub.Broadcast(req.SessionID, ws.WSMessage{
-		Type: "answer_received",
-		Data: gin.H{"session_id": req.SessionID},
-	})
-	h.hub.BroadcastToRoom(session.RoomID, ws.WSMessage{
-		Type: "answer_received",
-		Data: gin.H{"session_id": req.SessionID},
-	})
+	h.presenceService.TouchMember(session.RoomID, member.ID)
 
 	c.JSON(http.StatusOK, MessageResponse{Message: "answer accepted"})
 }
@@ -179,9 +237,9 @@ func (h *PlayHandler) GetState(c *gin.Context) {
 		return
 	}
 
-	member, err := h.roomService.GetMemberByToken(room.ID, token)
+	member, err := h.playerTokens.Member(token, room.ID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "member not found"})
+		c.JSON(playerTokenStatus(err), ErrorResponse{Error: err.Error()})
 		return
 	}
 
@@ -226,15 +284,15 @@ func (h *PlayHandler) UpdateNickname(c *gin.Context) {
 		return
 	}
 
-	member, err := h.roomService.GetMemberByToken(room.ID, req.Token)
+	member, err := h.playerTokens.Member(req.Token, room.ID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "member not found"})
+		c.JSON(playerTokenStatus(err), ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	updated, err := h.roomService.UpdateNickname(member.ID, req.Token, req.Nickname)
+	updated, err := h.roomService.UpdateNickname(member.ID, req.Nickname)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		c.JSON(joinErrorStatus(err), ErrorResponse{Error: err.Error()})
 		return
 	}
 
@@ -264,9 +322,9 @@ func (h *PlayHandler) Leave(c *gin.Context) {
 		return
 	}
 
-	member, err := h.roomService.GetMemberByToken(room.ID, req.Token)
+	member, err := h.playerTokens.Member(req.Token, room.ID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "member not found"})
+		c.JSON(playerTokenStatus(err), ErrorResponse{Error: err.Error()})
 		return
 	}
 
@@ -279,13 +337,14 @@ func (h *PlayHandler) Leave(c *gin.Context) {
 		Type: "member_left",
 		Data: member,
 	})
+	h.sessionService.RecheckAutoReveal(room.ID)
 
 	c.JSON(http.StatusOK, MessageResponse{Message: "left room"})
 }
 
 type PlayComplexAnswerRequest struct {
 	SessionID  uint            `json:"session_id" binding:"required"`
-	MemberID   uint            `json:"member_id" binding:"required"`
+	MemberID   uint            `json:"member_id"`
 	Token      string          `json:"token" binding:"required"`
 	AnswerData json.RawMessage `json:"answer_data" binding:"required"`
 }
@@ -297,24 +356,17 @@ func (h *PlayHandler) AnswerComplex(c *gin.Context) {
 		return
 	}
 
-	if err := h.sessionService.SubmitComplexAnswerByMember(req.SessionID, req.MemberID, req.AnswerData); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	session, member, status, err := h.sessionMember(req.SessionID, req.MemberID, req.Token)
+	if err != nil {
+		c.JSON(status, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	var session services.SessionState
-	if s, err := h.sessionService.GetSession(req.SessionID); err == nil {
-		session = *s
+	if err := h.sessionService.SubmitComplexAnswerByMember(req.SessionID, member.ID, req.AnswerData); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
 	}
-
-	h.hub.Broadcast(req.SessionID, ws.WSMessage{
-		Type: "answer_received",
-		Data: gin.H{"session_id": req.SessionID},
-	})
-	h.hub.BroadcastToRoom(session.RoomID, ws.WSMessage{
-		Type: "answer_received",
-		Data: gin.H{"session_id": req.SessionID},
-	})
+	h.presenceService.TouchMember(session.RoomID, member.ID)
 
 	c.JSON(http.StatusOK, MessageResponse{Message: "answer accepted"})
 }
@@ -325,13 +377,26 @@ func (h *PlayHandler) GetMyResult(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid session_id"})
 		return
 	}
-	memberID, err := strconv.ParseUint(c.Query("member_id"), 10, 64)
+	var memberID uint64
+	if v := c.Query("member_id"); v != "" {
+		if memberID, err = strconv.ParseUint(v, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid member_id"})
+			return
+		}
+	}
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "token required"})
+		return
+	}
+
+	_, member, status, err := h.sessionMember(uint(sessionID), uint(memberID), token)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid member_id"})
+		c.JSON(status, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	result, err := h.sessionService.GetParticipantResultByMember(uint(sessionID), uint(memberID))
+	result, err := h.sessionService.GetParticipantResultByMember(uint(sessionID), member.ID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
@@ -348,6 +413,13 @@ func (h *PlayHandler) HandleRoomWebSocket(c *gin.Context) {
 		return
 	}
 
+	// Players pass their token so the connection counts towards their presence;
+	// the host panel connects without one.
+	var member *models.RoomMember
+	if token := c.Query("token"); token != "" {
+		member, _ = h.playerTokens.Member(token, room.ID)
+	}
+
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("websocket upgrade error: %v", err)
@@ -355,6 +427,12 @@ func (h *PlayHandler) HandleRoomWebSocket(c *gin.Context) {
 	}
 
 	h.hub.AddRoomConnection(room.ID, conn)
+	if member != nil {
+		h.hub.AddMemberConnection(member.ID, conn)
+		defer h.hub.RemoveMemberConnection(member.ID, conn)
+		h.presenceService.MemberConnected(room.ID, member.ID)
+		defer h.presenceService.MemberDisconnected(room.ID, member.ID)
+	}
 	defer h.hub.RemoveRoomConnection(room.ID, conn)
 
 	for {
@@ -362,5 +440,8 @@ func (h *PlayHandler) HandleRoomWebSocket(c *gin.Context) {
 		if err != nil {
 			break
 		}
+		if member != nil {
+			h.presenceService.TouchMember(room.ID, member.ID)
+		}
 	}
 }
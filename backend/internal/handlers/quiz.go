@@ -11,10 +11,11 @@ import (
 
 type QuizHandler struct {
 	quizService *services.QuizService
+	audit       *services.AuditService
 }
 
-func NewQuizHandler(quizService *services.QuizService) *QuizHandler {
-	return &QuizHandler{quizService: quizService}
+func NewQuizHandler(quizService *services.QuizService, audit *services.AuditService) *QuizHandler {
+	return &QuizHandler{quizService: quizService, audit: audit}
 }
 
 type CreateQuizRequest struct {
@@ -73,6 +74,7 @@ func (h *QuizHandler) CreateQuiz(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
+	h.audit.Record(hostID, auditActor(c), services.AuditQuizCreate, services.AuditTargetQuiz, quiz.ID, nil, h.audit.QuizSummary(quiz.ID))
 
 	c.JSON(http.StatusCreated, quiz)
 }
@@ -131,11 +133,13 @@ func (h *QuizHandler) UpdateQuiz(c *gin.Context) {
 		return
 	}
 
+	before := h.audit.QuizSummary(uint(quizID))
 	quiz, err := h.quizService.UpdateQuiz(uint(quizID), hostID, req.Title, req.Mode)
 	if err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
 		return
 	}
+	h.audit.Record(hostID, auditActor(c), services.AuditQuizUpdate, services.AuditTargetQuiz, quiz.ID, before, h.audit.QuizSummary(quiz.ID))
 
 	c.JSON(http.StatusOK, quiz)
 }
@@ -158,10 +162,12 @@ func (h *QuizHandler) DeleteQuiz(c *gin.Context) {
 		return
 	}
 
+	before := h.audit.QuizSummary(uint(quizID))
 	if err := h.quizService.DeleteQuiz(uint(quizID), hostID); err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
 		return
 	}
+	h.audit.Record(hostID, auditActor(c), services.AuditQuizDelete, services.AuditTargetQuiz, uint(quizID), before, nil)
 
 	c.JSON(http.StatusOK, MessageResponse{Message: "quiz deleted"})
 }
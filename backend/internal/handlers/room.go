@@ -13,11 +13,12 @@ import (
 type RoomHandler struct {
 	roomService    *services.RoomService
 	sessionService *services.SessionService
+	audit          *services.AuditService
 	hub            *ws.Hub
 }
 
-func NewRoomHandler(roomService *services.RoomService, sessionService *services.SessionService, hub *ws.Hub) *RoomHandler {
-	return &RoomHandler{roomService: roomService, sessionService: sessionService, hub: hub}
+func NewRoomHandler(roomService *services.RoomService, sessionService *services.SessionService, audit *services.AuditService, hub *ws.Hub) *RoomHandler {
+	return &RoomHandler{roomService: roomService, sessionService: sessionService, audit: audit, hub: hub}
 }
 
 type CreateRoomRequest struct {
@@ -25,7 +26,9 @@ type CreateRoomRequest struct {
 }
 
 type StartQuizInRoomRequest struct {
-	QuizID uint `json:"quiz_id" binding:"required"`
+	QuizID          uint `json:"quiz_id" binding:"required"`
+	AutoReveal      bool `json:"auto_reveal"`
+	AutoRevealDelay int  `json:"auto_reveal_delay"`
 }
 
 func (h *RoomHandler) CreateRoom(c *gin.Context) {
@@ -39,6 +42,7 @@ func (h *RoomHandler) CreateRoom(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
+	h.audit.Record(hostID, auditActor(c), services.AuditRoomOpen, services.AuditTargetRoom, room.ID, nil, h.audit.RoomSummary(room.ID))
 	c.JSON(http.StatusCreated, room)
 }
 
@@ -93,10 +97,12 @@ func (h *RoomHandler) CloseRoom(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid room id"})
 		return
 	}
+	before := h.audit.RoomSummary(uint(roomID))
 	if err := h.roomService.CloseRoom(uint(roomID), hostID); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
+	h.audit.Record(hostID, auditActor(c), services.AuditRoomClose, services.AuditTargetRoom, uint(roomID), before, h.audit.RoomSummary(uint(roomID)))
 
 	h.hub.BroadcastToRoom(uint(roomID), ws.WSMessage{Type: "room_closed", Data: nil})
 
@@ -122,13 +128,16 @@ func (h *RoomHandler) StartQuizInRoom(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
+	h.audit.Record(hostID, auditActor(c), services.AuditSessionCreate, services.AuditTargetSession, session.ID, nil, h.audit.SessionSummary(session.ID))
 
-	state, _ := h.sessionService.GetSession(session.ID)
+	if req.AutoReveal {
+		if _, err := h.sessionService.SetAutoReveal(session.ID, hostID, true, req.AutoRevealDelay); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
 
-	h.hub.BroadcastToRoom(uint(roomID), ws.WSMessage{
-		Type: "quiz_started",
-		Data: state,
-	})
+	state, _ := h.sessionService.GetSession(session.ID)
 
 	c.JSON(http.StatusOK, state)
 }
@@ -143,15 +152,14 @@ func (h *RoomHandler) SessionReveal(c *gin.Context) {
 		return
 	}
 
-	state, err := h.sessionService.RevealAnswer(currentSession.ID, hostID)
+	state, err := h.audit.SessionChange(hostID, auditActor(c), services.AuditSessionReveal, currentSession.ID, func() (*services.SessionState, error) {
+		return h.sessionService.RevealAnswer(currentSession.ID, hostID)
+	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	h.hub.BroadcastToRoom(uint(roomID), ws.WSMessage{Type: "revealed", Data: state})
-	h.hub.Broadcast(currentSession.ID, ws.WSMessage{Type: "revealed", Data: state})
-
 	c.JSON(http.StatusOK, state)
 }
 
@@ -165,20 +173,14 @@ func (h *RoomHandler) SessionNext(c *gin.Context) {
 		return
 	}
 
-	state, err := h.sessionService.NextQuestion(currentSession.ID, hostID)
+	state, err := h.audit.SessionChange(hostID, auditActor(c), services.AuditSessionNext, currentSession.ID, func() (*services.SessionState, error) {
+		return h.sessionService.NextQuestion(currentSession.ID, hostID)
+	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	msgType := "question"
-	if state.Status == "finished" {
-		msgType = "finished"
-	}
-
-	h.hub.BroadcastToRoom(uint(roomID), ws.WSMessage{Type: msgType, Data: state})
-	h.hub.Broadcast(currentSession.ID, ws.WSMessage{Type: msgType, Data: state})
-
 	c.JSON(http.StatusOK, state)
 }
 
@@ -192,15 +194,14 @@ func (h *RoomHandler) SessionFinish(c *gin.Context) {
 		return
 	}
 
-	state, err := h.sessionService.ForceFinish(currentSession.ID, hostID)
+	state, err := h.audit.SessionChange(hostID, auditActor(c), services.AuditSessionFinish, currentSession.ID, func() (*services.SessionState, error) {
+		return h.sessionService.ForceFinish(currentSession.ID, hostID)
+	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	h.hub.BroadcastToRoom(uint(roomID), ws.WSMessage{Type: "finished", Data: state})
-	h.hub.Broadcast(currentSession.ID, ws.WSMessage{Type: "finished", Data: state})
-
 	c.JSON(http.StatusOK, state)
 }
 
@@ -14,12 +14,13 @@ import (
 
 type SessionHandler struct {
 	sessionService *services.SessionService
+	audit          *services.AuditService
 	hub            *ws.Hub
 	db             *gorm.DB
 }
 
-func NewSessionHandler(sessionService *services.SessionService, hub *ws.Hub, db *gorm.DB) *SessionHandler {
-	return &SessionHandler{sessionService: sessionService, hub: hub, db: db}
+func NewSessionHandler(sessionService *services.SessionService, audit *services.AuditService, hub *ws.Hub, db *gorm.DB) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService, audit: audit, hub: hub, db: db}
 }
 
 type CreateSessionRequest struct {
@@ -51,6 +52,7 @@ func (h *SessionHandler) CreateSession(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
+	h.audit.Record(hostID, auditActor(c), services.AuditSessionCreate, services.AuditTargetSession, session.ID, nil, h.audit.SessionSummary(session.ID))
 
 	state, _ := h.sessionService.GetSession(session.ID)
 
@@ -84,6 +86,20 @@ func (h *SessionHandler) ListSessions(c *gin.Context) {
 	c.JSON(http.StatusOK, sessions)
 }
 
+// inWorkspace answers 404 for a session outside the caller's workspace. Only
+// the bot key, which has no workspace, reads every session.
+func (h *SessionHandler) inWorkspace(c *gin.Context, sessionID uint) bool {
+	hostID := c.GetUint("host_id")
+	if hostID == 0 {
+		return true
+	}
+	if err := h.sessionService.CheckInWorkspace(sessionID, hostID); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return false
+	}
+	return true
+}
+
 // GetSession godoc
 // @Summary      Get session state
 // @Description  Get current state of a quiz session including current question
@@ -101,6 +117,10 @@ func (h *SessionHandler) GetSession(c *gin.Context) {
 		return
 	}
 
+	if !h.inWorkspace(c, uint(sessionID)) {
+		return
+	}
+
 	state, err := h.sessionService.GetSession(uint(sessionID))
 	if err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
@@ -128,17 +148,14 @@ func (h *SessionHandler) RevealAnswer(c *gin.Context) {
 		return
 	}
 
-	state, err := h.sessionService.RevealAnswer(uint(sessionID), hostID)
+	state, err := h.audit.SessionChange(hostID, auditActor(c), services.AuditSessionReveal, uint(sessionID), func() (*services.SessionState, error) {
+		return h.sessionService.RevealAnswer(uint(sessionID), hostID)
+	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	h.hub.Broadcast(uint(sessionID), ws.WSMessage{
-		Type: "revealed",
-		Data: state,
-	})
-
 	c.JSON(http.StatusOK, state)
 }
 
@@ -160,22 +177,14 @@ func (h *SessionHandler) NextQuestion(c *gin.Context) {
 		return
 	}
 
-	state, err := h.sessionService.NextQuestion(uint(sessionID), hostID)
+	state, err := h.audit.SessionChange(hostID, auditActor(c), services.AuditSessionNext, uint(sessionID), func() (*services.SessionState, error) {
+		return h.sessionService.NextQuestion(uint(sessionID), hostID)
+	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	msgType := "question"
-	if state.Status == "finished" {
-		msgType = "finished"
-	}
-
-	h.hub.Broadcast(uint(sessionID), ws.WSMessage{
-		Type: msgType,
-		Data: state,
-	})
-
 	c.JSON(http.StatusOK, state)
 }
 
@@ -197,16 +206,53 @@ func (h *SessionHandler) ForceFinish(c *gin.Context) {
 		return
 	}
 
-	state, err := h.sessionService.ForceFinish(uint(sessionID), hostID)
+	state, err := h.audit.SessionChange(hostID, auditActor(c), services.AuditSessionFinish, uint(sessionID), func() (*services.SessionState, error) {
+		return h.sessionService.ForceFinish(uint(sessionID), hostID)
+	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	h.hub.Broadcast(uint(sessionID), ws.WSMessage{
-		Type: "finished",
-		Data: state,
-	})
+	c.JSON(http.StatusOK, state)
+}
+
+type AutoRevealRequest struct {
+	Enabled bool `json:"enabled" example:"true"`
+	Delay   int  `json:"delay" example:"3"`
+}
+
+// SetAutoReveal godoc
+// @Summary      Configure auto-reveal
+// @Description  Reveal automatically once every active participant has answered, after an optional grace period in seconds
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Session ID"
+// @Param        request body AutoRevealRequest true "Auto-reveal settings"
+// @Success      200 {object} services.SessionState
+// @Failure      400 {object} ErrorResponse
+// @Router       /api/v1/sessions/{id}/auto-reveal [put]
+func (h *SessionHandler) SetAutoReveal(c *gin.Context) {
+	hostID := c.GetUint("host_id")
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid session id"})
+		return
+	}
+
+	var req AutoRevealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	state, err := h.sessionService.SetAutoReveal(uint(sessionID), hostID, req.Enabled, req.Delay)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, state)
 }
@@ -228,6 +274,10 @@ func (h *SessionHandler) GetLeaderboard(c *gin.Context) {
 		return
 	}
 
+	if !h.inWorkspace(c, uint(sessionID)) {
+		return
+	}
+
 	entries, err := h.sessionService.GetLeaderboard(uint(sessionID))
 	if err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
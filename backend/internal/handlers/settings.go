@@ -4,36 +4,67 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"quiz-game-backend/internal/i18n"
 	"quiz-game-backend/internal/models"
+	"quiz-game-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type SettingsHandler struct {
-	db *gorm.DB
+	db         *gorm.DB
+	apiURL     string
+	tokens     *services.BotTokenService
+	remoteAuth *services.RemoteAuthService
+	oidc       *services.OIDCService
+	audit      *services.AuditService
 }
 
-func NewSettingsHandler(db *gorm.DB) *SettingsHandler {
-	return &SettingsHandler{db: db}
+func NewSettingsHandler(db *gorm.DB, telegramAPIURL string, tokens *services.BotTokenService, remoteAuth *services.RemoteAuthService, oidc *services.OIDCService, audit *services.AuditService) *SettingsHandler {
+	return &SettingsHandler{db: db, apiURL: strings.TrimRight(telegramAPIURL, "/"), tokens: tokens, remoteAuth: remoteAuth, oidc: oidc, audit: audit}
 }
 
 type SettingsResponse struct {
-	BotToken       string `json:"bot_token"`
-	BotLink        string `json:"bot_link"`
-	RemotePassword string `json:"remote_password"`
+	// BotToken is masked to the bot ID and last characters of the token.
+	BotToken string `json:"bot_token" example:"123456:••••wxyz"`
+	BotLink  string `json:"bot_link"`
+	// RemotePasswordSet tells whether a remote password is set; the password
+	// itself is stored hashed and never returned.
+	RemotePasswordSet bool   `json:"remote_password_set"`
+	BotLanguage       string `json:"bot_language"`
+	BotQuizPolls      bool   `json:"bot_quiz_polls"`
+	// SSORequired admits members to the workspace only when they signed in
+	// with single sign-on; SSOAvailable tells whether a provider is set up.
+	SSORequired  bool `json:"sso_required"`
+	SSOAvailable bool `json:"sso_available"`
 }
 
 type UpdateSettingsRequest struct {
-	BotToken       string `json:"bot_token" example:"123456:ABC-DEF"`
-	RemotePassword string `json:"remote_password"`
+	// BotToken replaces the bot when present; an empty string disconnects it.
+	BotToken *string `json:"bot_token" example:"123456:ABC-DEF"`
+	// RemotePassword replaces the remote password when present; an empty
+	// string removes it.
+	RemotePassword *string `json:"remote_password"`
+	// BotLanguage changes the default bot language for users whose Telegram
+	// language isn't supported when present; empty means the built-in default.
+	BotLanguage *string `json:"bot_language" example:"ru"`
+	// BotQuizPolls changes whether single-choice questions are sent as native
+	// Telegram quiz polls when present.
+	BotQuizPolls *bool `json:"bot_quiz_polls"`
+	// SSORequired changes the single sign-on requirement when present. Turning
+	// it on takes a login session started with single sign-on.
+	SSORequired *bool `json:"sso_required"`
 }
 
-func resolveBotUsername(token string) (string, error) {
-	resp, err := http.Get(fmt.Sprintf("https://api.telegram.org/bot%s/getMe", token))
+func resolveBotUsername(apiURL, token string) (string, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/bot%s/getMe", apiURL, token))
 	if err != nil {
 		return "", err
 	}
@@ -57,7 +88,7 @@ func resolveBotUsername(token string) (string, error) {
 
 // GetSettings godoc
 // @Summary      Get host settings
-// @Description  Get bot token and link settings
+// @Description  Get bot token and link settings. Members without settings access get them without the bot token
 // @Tags         settings
 // @Produce      json
 // @Security     BearerAuth
@@ -65,24 +96,45 @@ func resolveBotUsername(token string) (string, error) {
 // @Failure      404 {object} ErrorResponse
 // @Router       /api/v1/settings [get]
 func (h *SettingsHandler) GetSettings(c *gin.Context) {
-	hostID := c.GetUint("host_id")
+	resp, err := h.loadSettings(c.GetUint("host_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "host not found"})
+		return
+	}
+	// Co-hosts see whether a bot is set up, not its token.
+	if role := c.GetString("role"); role != "" && !services.RoleCan(role, services.PermSettings) {
+		resp.BotToken = ""
+	}
+	c.JSON(http.StatusOK, resp)
+}
 
+func (h *SettingsHandler) loadSettings(hostID uint) (*SettingsResponse, error) {
 	var host models.Host
 	if err := h.db.First(&host, hostID).Error; err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "host not found"})
-		return
+		return nil, err
 	}
 
-	c.JSON(http.StatusOK, SettingsResponse{
-		BotToken:       host.BotToken,
-		BotLink:        host.BotLink,
-		RemotePassword: host.RemotePassword,
-	})
+	resp := &SettingsResponse{
+		BotLink:           host.BotLink,
+		RemotePasswordSet: host.RemotePassword != "",
+		BotLanguage:       host.BotLanguage,
+		BotQuizPolls:      host.BotQuizPolls,
+		SSORequired:       host.SSORequired,
+		SSOAvailable:      h.oidc.Enabled(),
+	}
+	if host.BotToken != "" {
+		token, err := h.tokens.Decrypt(host.BotToken)
+		if err != nil {
+			log.Printf("settings: bot token of host %d: %v", hostID, err)
+		}
+		resp.BotToken = services.MaskBotToken(token)
+	}
+	return resp, nil
 }
 
 // UpdateSettings godoc
 // @Summary      Update host settings
-// @Description  Update bot token and link
+// @Description  Update bot token and link, remote password, default bot language, quiz poll delivery and the single sign-on requirement
 // @Tags         settings
 // @Accept       json
 // @Produce      json
@@ -100,33 +152,190 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 		return
 	}
 
-	botLink := ""
-	token := strings.TrimSpace(req.BotToken)
-	if token != "" {
-		username, err := resolveBotUsername(token)
+	updates := map[string]interface{}{}
+	if req.BotLanguage != nil {
+		lang := strings.TrimSpace(*req.BotLanguage)
+		if lang == "" {
+			lang = i18n.Default
+		}
+		if !i18n.IsSupported(lang) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "unsupported bot language"})
+			return
+		}
+		updates["bot_language"] = lang
+	}
+	if req.BotQuizPolls != nil {
+		updates["bot_quiz_polls"] = *req.BotQuizPolls
+	}
+
+	// The audit log keeps the settings as they are shown, with the bot token
+	// masked and the remote password left out.
+	before, err := h.loadSettings(hostID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "host not found"})
+		return
+	}
+
+	if req.SSORequired != nil {
+		// Requiring single sign-on from a password login would lock the owner out.
+		if *req.SSORequired && !h.oidc.Enabled() {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: services.ErrOIDCDisabled.Error()})
+			return
+		}
+		if *req.SSORequired && c.GetString("auth_method") != models.AuthMethodSSO {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "sign in with single sign-on before requiring it"})
+			return
+		}
+		updates["sso_required"] = *req.SSORequired
+	}
+
+	if req.BotToken != nil {
+		botLink := ""
+		token := strings.TrimSpace(*req.BotToken)
+		if token != "" {
+			username, err := resolveBotUsername(h.apiURL, token)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bot token"})
+				return
+			}
+			botLink = fmt.Sprintf("https://t.me/%s", username)
+		}
+		columns, err := h.tokens.Columns(token, botLink)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Невалидный токен бота. Проверьте токен и попробуйте снова."})
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 			return
 		}
-		botLink = fmt.Sprintf("https://t.me/%s", username)
+		for k, v := range columns {
+			updates[k] = v
+		}
 	}
 
-	remotePass := strings.TrimSpace(req.RemotePassword)
+	if req.RemotePassword != nil {
+		hash, err := h.remoteAuth.HashPassword(*req.RemotePassword)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		updates["remote_password"] = hash
+	}
 
-	if err := h.db.Model(&models.Host{}).Where("id = ?", hostID).Updates(map[string]interface{}{
-		"bot_token":       token,
-		"bot_link":        botLink,
-		"remote_password": remotePass,
-	}).Error; err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	// Everything is checked by now; the change is saved whole or not at all.
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			if err := tx.Model(&models.Host{}).Where("id = ?", hostID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if req.RemotePassword != nil {
+			return h.remoteAuth.RevokePasswordLogins(tx, hostID)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, SettingsResponse{
-		BotToken:       token,
-		BotLink:        botLink,
-		RemotePassword: remotePass,
-	})
+	resp, err := h.loadSettings(hostID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "host not found"})
+		return
+	}
+
+	after := settingsAudit(resp)
+	if req.RemotePassword != nil {
+		// Only the hash is stored, so a new password shows up as a flag.
+		after["remote_password_changed"] = true
+	}
+	h.audit.Record(hostID, auditActor(c), services.AuditSettingsUpdate, services.AuditTargetSettings, hostID, settingsAudit(before), after)
+	c.JSON(http.StatusOK, resp)
+}
+
+func settingsAudit(s *SettingsResponse) gin.H {
+	return gin.H{
+		"bot_token":           s.BotToken,
+		"bot_link":            s.BotLink,
+		"remote_password_set": s.RemotePasswordSet,
+		"bot_language":        s.BotLanguage,
+		"bot_quiz_polls":      s.BotQuizPolls,
+		"sso_required":        s.SSORequired,
+	}
+}
+
+type PairingCodeResponse struct {
+	Code      string    `json:"code" example:"K7M2QX9P"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Link opens the bot and logs into the remote in one tap; empty while no
+	// bot is connected.
+	Link string `json:"link,omitempty" example:"https://t.me/my_quiz_bot?start=pair-K7M2QX9P"`
+}
+
+// CreatePairingCode godoc
+// @Summary      Create remote pairing code
+// @Description  Issue a one-time code that logs a Telegram account into the host remote without the password. It expires after 10 minutes
+// @Tags         settings
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Workspace-ID header int false "Workspace ID"
+// @Success      201 {object} PairingCodeResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/v1/settings/remote/pairing-code [post]
+func (h *SettingsHandler) CreatePairingCode(c *gin.Context) {
+	hostID := c.GetUint("host_id")
+	code, expiresAt, err := h.remoteAuth.CreatePairingCode(hostID, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	resp := PairingCodeResponse{Code: code, ExpiresAt: expiresAt}
+	var host models.Host
+	if err := h.db.Select("bot_link").First(&host, hostID).Error; err == nil && host.BotLink != "" {
+		resp.Link = host.BotLink + "?start=" + services.RemotePairingPrefix + code
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListRemoteAuthorizations godoc
+// @Summary      List remote authorizations
+// @Description  Telegram accounts that are logged into the host remote
+// @Tags         settings
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Workspace-ID header int false "Workspace ID"
+// @Success      200 {array} models.RemoteAuthorization
+// @Router       /api/v1/settings/remote/authorizations [get]
+func (h *SettingsHandler) ListRemoteAuthorizations(c *gin.Context) {
+	auths, err := h.remoteAuth.ListAuthorizations(c.GetUint("host_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, auths)
+}
+
+// RevokeRemoteAuthorization godoc
+// @Summary      Revoke remote authorization
+// @Description  Log a Telegram account out of the host remote
+// @Tags         settings
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Workspace-ID header int false "Workspace ID"
+// @Param        id path int true "Authorization ID"
+// @Success      200 {object} MessageResponse
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/v1/settings/remote/authorizations/{id} [delete]
+func (h *SettingsHandler) RevokeRemoteAuthorization(c *gin.Context) {
+	authID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid authorization id"})
+		return
+	}
+	if err := h.remoteAuth.Revoke(uint(authID), c.GetUint("host_id")); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: "remote access revoked"})
 }
 
 type BotTokenEntry struct {
@@ -136,21 +345,28 @@ type BotTokenEntry struct {
 
 // GetBotTokens godoc
 // @Summary      Get all bot tokens
-// @Description  Internal endpoint for bot service to fetch all registered bot tokens
+// @Description  Internal endpoint for an external bot service to fetch every registered bot token. Registered only when BOT_TOKEN_EXPORT=true
 // @Tags         internal
 // @Produce      json
 // @Param        X-Bot-API-Key header string true "Bot API Key"
 // @Success      200 {array} BotTokenEntry
+// @Failure      500 {object} ErrorResponse
 // @Router       /api/v1/internal/bot-tokens [get]
 func (h *SettingsHandler) GetBotTokens(c *gin.Context) {
-	var hosts []models.Host
-	h.db.Where("bot_token != '' AND bot_token IS NOT NULL").Find(&hosts)
+	bots, err := h.tokens.Active()
+	if err != nil {
+		log.Printf("bot token export: %v", err)
+		if bots == nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
 
-	entries := make([]BotTokenEntry, 0, len(hosts))
-	for _, host := range hosts {
+	entries := make([]BotTokenEntry, 0, len(bots))
+	for _, b := range bots {
 		entries = append(entries, BotTokenEntry{
-			HostID:   host.ID,
-			BotToken: host.BotToken,
+			HostID:   b.Host.ID,
+			BotToken: b.Token,
 		})
 	}
 
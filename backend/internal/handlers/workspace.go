@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"quiz-game-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkspaceHandler struct {
+	accessService *services.AccessService
+}
+
+func NewWorkspaceHandler(accessService *services.AccessService) *WorkspaceHandler {
+	return &WorkspaceHandler{accessService: accessService}
+}
+
+type AddMemberRequest struct {
+	Username string `json:"username" binding:"required" example:"cohost"`
+	Role     string `json:"role" binding:"required" example:"presenter"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required" example:"editor"`
+}
+
+// ListWorkspaces godoc
+// @Summary      List workspaces
+// @Description  Workspaces the host can work in with their role: their own, then the ones they were added to. Send the chosen ID in the X-Workspace-ID header
+// @Tags         workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} services.WorkspaceInfo
+// @Router       /api/v1/workspaces [get]
+func (h *WorkspaceHandler) ListWorkspaces(c *gin.Context) {
+	workspaces, err := h.accessService.Workspaces(c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, workspaces)
+}
+
+// ListMembers godoc
+// @Summary      List workspace members
+// @Description  Accounts added to the current workspace and their roles. The owner account is not listed
+// @Tags         workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Workspace-ID header int false "Workspace ID"
+// @Success      200 {array} services.WorkspaceMemberInfo
+// @Router       /api/v1/workspaces/members [get]
+func (h *WorkspaceHandler) ListMembers(c *gin.Context) {
+	members, err := h.accessService.Members(c.GetUint("host_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// AddMember godoc
+// @Summary      Add workspace member
+// @Description  Give an existing account a role (owner, editor, presenter, viewer) in the current workspace
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Workspace-ID header int false "Workspace ID"
+// @Param        request body AddMemberRequest true "Member"
+// @Success      201 {object} services.WorkspaceMemberInfo
+// @Failure      400 {object} ErrorResponse
+// @Router       /api/v1/workspaces/members [post]
+func (h *WorkspaceHandler) AddMember(c *gin.Context) {
+	var req AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	member, err := h.accessService.AddMember(c.GetUint("host_id"), req.Username, req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, member)
+}
+
+// UpdateMember godoc
+// @Summary      Change member role
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Workspace-ID header int false "Workspace ID"
+// @Param        id path int true "Member ID"
+// @Param        request body UpdateMemberRequest true "Role"
+// @Success      200 {object} services.WorkspaceMemberInfo
+// @Failure      400 {object} ErrorResponse
+// @Router       /api/v1/workspaces/members/{id} [put]
+func (h *WorkspaceHandler) UpdateMember(c *gin.Context) {
+	memberID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid member id"})
+		return
+	}
+	var req UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	member, err := h.accessService.UpdateMemberRole(c.GetUint("host_id"), uint(memberID), req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember godoc
+// @Summary      Remove workspace member
+// @Description  Owners can remove anyone; other members can only leave, by removing themselves
+// @Tags         workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Workspace-ID header int false "Workspace ID"
+// @Param        id path int true "Member ID"
+// @Success      200 {object} MessageResponse
+// @Failure      403 {object} ErrorResponse
+// @Router       /api/v1/workspaces/members/{id} [delete]
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	memberID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid member id"})
+		return
+	}
+
+	canManage := services.RoleCan(c.GetString("role"), services.PermMembers)
+	err = h.accessService.RemoveMember(c.GetUint("host_id"), uint(memberID), c.GetUint("user_id"), canManage)
+	if errors.Is(err, services.ErrNoWorkspaceAccess) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: "member removed"})
+}
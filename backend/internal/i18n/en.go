@@ -0,0 +1,220 @@
+package i18n
+
+var en = map[string]string{
+	"lang.name": "English",
+
+	// Reply keyboard
+	"btn.join":    "🎮 Join a quiz",
+	"btn.profile": "👤 My profile",
+	"btn.history": "📊 Game history",
+	"btn.rejoin":  "🔄 Reconnect",
+	"btn.host":    "🎯 Host remote",
+
+	// Inline keyboards
+	"kb.start_quiz":   "▶️ Start quiz",
+	"kb.reveal":       "👁 Reveal answer",
+	"kb.stop_quiz":    "⏭ End quiz",
+	"kb.next":         "➡️ Next question",
+	"kb.finish_quiz":  "🏆 End quiz",
+	"kb.refresh":      "🔄 Refresh",
+	"kb.back_room":    "🔙 Back to room",
+	"kb.back_rooms":   "🔙 Back to rooms",
+	"kb.new_room":     "➕ New room",
+	"kb.create_room":  "➕ Create room",
+	"kb.pick_quiz":    "📋 Choose quiz",
+	"kb.players":      "👥 Players",
+	"kb.close_room":   "❌ Close room",
+	"kb.confirm":      "✅ Confirm",
+	"kb.undo":         "↩️ Undo",
+	"kb.lang_auto":    "🔄 Same as Telegram",
+	"kb.join":         "🙋 I'm in",
+	"kb.cancel":       "✖️ Cancel",
+	"kb.answer_dm":    "💬 Answer in private chat",
+	"kb.quizzes":      "📚 Quizzes",
+	"kb.new_quiz":     "➕ New quiz",
+	"kb.import_quiz":  "📥 Import from file",
+	"kb.ai_quiz":      "✨ Generate",
+	"kb.add_question": "➕ Add question",
+	"kb.play_quiz":    "▶️ Play in a new room",
+	"kb.back_quizzes": "🔙 Back to quizzes",
+	"kb.type_single":  "🔘 Single answer",
+	"kb.type_multi":   "☑️ Multiple answers",
+	"kb.type_numeric": "🔢 Number",
+
+	// General
+	"menu.hint":         "Use /start or the menu buttons.",
+	"error.generic":     "Error: %s",
+	"error.retry_start": "Something went wrong. Try /start",
+	"error.bad_data":    "Invalid data",
+	"error.unknown_cmd": "Unknown command",
+	"common.refreshed":  "🔄 Refreshed",
+
+	// Language
+	"lang.choose": "🌐 Choose the bot language:",
+	"lang.set":    "✅ Bot language: <b>%s</b>",
+	"lang.auto":   "✅ The bot will follow your Telegram language.",
+
+	// Start and join
+	"start.in_session":   "🎮 You are in an active session.\n\nTap <b>%s</b> to get back into the game, or enter a new code.",
+	"start.welcome_code": "👋 Welcome to Quiz Game!\n\nRoom code: <b>%s</b>\nEnter your nickname:",
+	"start.greeting":     "👋 Hi, <b>%s</b>!\n\nChoose an action:",
+	"start.welcome":      "👋 Welcome to Quiz Game!\n\nEnter your nickname:",
+	"join.enter_code":    "Enter the 6-digit room code:",
+	"join.bad_code":      "❌ The code must be 6 digits. Try again:",
+	"join.code_ok":       "✅ Code accepted: <b>%s</b>\n\nEnter your nickname:",
+	"join.banned":        "⛔ The host has blocked you from this room.",
+	"join.failed":        "❌ Error: %s\n\nTry /start again.",
+	"join.rejoined":      "🔄 You are back in the quiz!\n\nNickname: <b>%s</b>",
+	"join.joined":        "🎮 You joined the quiz!\n\nNickname: <b>%s</b>\nWaiting for the game to start...",
+
+	// Session
+	"session.not_found":    "Session not found. Use /start",
+	"session.finished":     "The session is over. Tap /start for a new game.",
+	"session.reconnecting": "🔄 Reconnecting to the quiz...",
+	"session.not_joined":   "You are not in a session. Tap /start",
+
+	// Nickname, profile, history
+	"nick.bad_length":  "❌ The nickname must be 1 to 100 characters. Try again:",
+	"nick.blocked":     "❌ This nickname is not allowed. Choose another one:",
+	"nick.set":         "✅ Nickname set: <b>%s</b>\n\nChoose an action:",
+	"nick.usage":       "Usage: /nickname Your_new_nickname",
+	"nick.too_long":    "The nickname is too long (max 100 characters)",
+	"nick.changed":     "✅ Nickname changed to: <b>%s</b>",
+	"nick.not_allowed": "❌ This nickname is not allowed.",
+	"profile.failed":   "Could not load the profile",
+	"profile.show":     "👤 <b>Your profile</b>\n\nNickname: <b>%s</b>\n\nTo change it, send:\n/nickname New_nickname\n\nChange language: /language",
+	"history.empty":    "📊 You have no finished games yet.",
+	"history.title":    "📊 <b>Your game history:</b>\n",
+	"history.entry":    "%s <b>%s</b>\n   Points: %d | Place: %d/%d",
+
+	// Questions
+	"status.waiting":    "waiting",
+	"status.question":   "question",
+	"status.revealed":   "answer revealed",
+	"status.finished":   "finished",
+	"question.header":   "❓ <b>Question %d of %d</b>",
+	"question.closed":   "This question is already closed",
+	"question.multi":    "<i>Select all correct answers and tap “Confirm”</i>",
+	"question.numeric":  "<i>Type a number in the chat:</i>",
+	"question.ordering": "<i>Tap the options in order, starting with the first</i>",
+	"question.match_to": "What matches <b>%s</b>?",
+
+	// Answers
+	"answer.not_in_session": "You are not in an active session",
+	"answer.rejoin_hint":    "You are not in an active session. Tap /rejoin",
+	"answer.time_up":        "Time is up for this question",
+	"answer.reconnecting":   "Reconnecting...",
+	"answer.accepted":       "✅ Answer accepted!",
+	"answer.accepted_note":  "✅ <b>Answer accepted</b>",
+	"answer.yours_accepted": "✅ <b>Your answer is accepted</b>",
+	"answer.multi_selected": "<i>Selected: %d</i>",
+	"answer.multi_empty":    "Select at least one answer",
+	"answer.multi_accepted": "✅ <b>Answer accepted (selected: %d)</b>",
+	"answer.ordering_done":  "<i>The order is complete — confirm your answer</i>",
+	"answer.ordering_short": "Place all the options",
+	"answer.matching_done":  "<i>All pairs are chosen — confirm your answer</i>",
+	"answer.matching_short": "Match all the pairs",
+	"numeric.invalid":       "⚠️ Enter a valid number.",
+	"numeric.no_question":   "⚠️ There is no active question.",
+	"numeric.accepted":      "✅ <b>Your answer: %s</b>\n\nWaiting for the result...",
+
+	// Reactions
+	"react.not_in_room": "You are not in this room",
+	"react.slow_down":   "Not so fast 🙂",
+	"react.muted":       "The host has turned off your reactions",
+
+	// Results
+	"result.missed":     "⏰ You didn't answer in time",
+	"result.correct":    "✅ <b>Correct!</b>",
+	"result.wrong":      "❌ <b>Wrong</b>",
+	"result.total":      "Total points: <b>%d</b>",
+	"result.score":      "Points for the question: <b>+%d</b> | Total: <b>%d</b>",
+	"result.answer":     "Correct answer: <b>%s</b>",
+	"result.answers":    "Correct: <b>%s</b>",
+	"result.order":      "Correct order:",
+	"result.pairs":      "Correct pairs:",
+	"result.wait_next":  "⏳ Wait for the next question...",
+	"leaderboard.title": "🏆 <b>The quiz is over! Results:</b>\n",
+	"leaderboard.entry": "%s <b>%s</b> — %d points",
+	"leaderboard.place": "📍 Your place: <b>%d</b>",
+	"leaderboard.again": "Tap /start for a new game",
+
+	// Host remote
+	"remote.title":          "🎯 <b>Host remote</b>",
+	"remote.in_remote":      "🎯 You are in remote mode. Use the buttons in the message above.\n\nTap /start to leave",
+	"remote.pairing_only":   "🔐 The remote has no password. Enter a pairing code from the website settings or open the pairing link from there:",
+	"remote.password":       "🔐 Enter the host remote password or a pairing code from the website settings:",
+	"remote.wrong_password": "❌ Wrong password or code. Try again:",
+	"remote.locked":         "🔒 Too many wrong attempts. Try again in %d min.",
+	"remote.revoked":        "🔒 Your remote access was revoked or the password changed. Log in again.",
+	"remote.auth_required":  "Log in first: /start → %s",
+	"remote.no_rooms":       "📋 No active rooms.\n\nCreate a room right here or on the website.",
+	"remote.pick_room":      "✅ Logged in!\nChoose a room:",
+	"remote.room":           "🚪 <b>Room %s</b>\n\n👥 Players: <b>%d</b>\nMode: <b>%s</b>",
+	"remote.room_session":   "🎮 Quiz: <b>%s</b>\nStatus: <b>%s</b>",
+	"remote.quiz_label":     "📝 %s (%d q.)",
+	"remote.pick_quiz":      "📋 <b>Choose a quiz</b>\n\n%d quizzes available",
+	"remote.room_created":   "✅ Room created",
+	"remote.room_closed":    "❌ Room closed",
+	"remote.quiz_started":   "▶️ Quiz started!",
+	"remote.revealed":       "👁 Answer revealed",
+	"remote.next":           "➡️ Next",
+	"remote.finished":       "🏆 Quiz finished",
+	"remote.no_session":     "Session not found",
+	"remote.no_access":      "No access to this session",
+	"control.waiting":       "📋 Quiz: <b>%s</b>\n🔑 Code: <b>%s</b>\n👥 Players: <b>%d</b>\n📝 Questions: <b>%d</b>\n\n⏳ Waiting for players...",
+	"control.category":      "📁 Category: <b>%s</b>",
+	"control.answered":      "📊 Answered: <b>%d</b> of <b>%d</b>",
+	"control.correct":       "✅ Correct answer: <b>%s</b>",
+	"control.finished":      "🏆 <b>The quiz is over!</b>\n📋 %s\n👥 Players: <b>%d</b>",
+	"control.not_found":     "Session not found.",
+
+	// Quiz editing
+	"quizzes.title":                 "📚 <b>Your quizzes</b>\n\nTotal: %d. Pick a quiz or add a new one.",
+	"quizzes.empty":                 "📚 You have no quizzes yet.\n\nCreate one by hand, upload a file or generate it.",
+	"quiz.card":                     "📋 <b>%s</b>\n\n📝 Questions: <b>%d</b>",
+	"quiz.enter_title":              "✏️ Enter the quiz title:",
+	"quiz.bad_title":                "The title must be 1 to %d characters long. Try again:",
+	"quiz.created":                  "✅ Quiz created",
+	"question.pick_type":            "❓ <b>New question</b>\n\nChoose the question type:",
+	"question.enter_text":           "✏️ Enter the question text:",
+	"question.enter_options_single": "📝 Send the answer options, one per line (2 to 6). Mark the correct one with an asterisk:\n\n<code>*Paris\nLondon\nBerlin</code>",
+	"question.enter_options_multi":  "📝 Send the answer options, one per line (2 to 6). Mark every correct one with an asterisk:\n\n<code>*Mars\n*Venus\nMoon</code>",
+	"question.enter_number":         "🔢 Send the correct answer. Add a tolerance with ±:\n\n<code>1961</code> or <code>100 ± 5</code>",
+	"question.bad_number":           "Couldn't read the number. Example: <code>42</code> or <code>42 ± 2</code>",
+	"question.added":                "✅ Question added",
+	"import.prompt":                 "📥 Send a quiz file in JSON or CSV format (up to 1 MB), the same as the website exports.",
+	"import.not_file":               "Send the file as a document.",
+	"import.too_large":              "The file is too large. The limit is %d KB.",
+	"import.failed":                 "Couldn't import the file: %s",
+	"import.done":                   "✅ Questions imported: %d",
+	"ai.prompt":                     "✨ Describe the quiz to generate, for example: <i>10 space questions for school kids</i>",
+	"ai.unavailable":                "AI generation isn't configured on the server.",
+	"ai.generating":                 "⏳ Generating the quiz, this can take up to a minute...",
+	"ai.failed":                     "Couldn't generate the quiz. Try a different request.",
+	"ai.done":                       "✅ Questions generated: %d",
+
+	// Players
+	"players.title":   "👥 <b>Players in room %s</b>",
+	"players.legend":  "✏️ — rename, 🚪 — kick, ⛔ — kick and block",
+	"players.empty":   "Nobody here yet.",
+	"players.rename":  "✏️ Enter a new nickname for the player:",
+	"players.renamed": "✅ Player renamed: <b>%s</b>",
+	"players.banned":  "⛔ %s blocked",
+	"players.kicked":  "🚪 %s removed",
+	"kicked.removed":  "🚪 The host removed you from the room.",
+	"kicked.banned":   "⛔ The host removed you from the room and blocked access.",
+
+	// Group chats
+	"group.help":       "👋 This group can play quizzes together.\n\nThe host starts a quiz with /quiz (after logging into the host remote in a private chat with the bot), and everyone answers with the buttons under the question.",
+	"group.only_host":  "Only the host can start and run a quiz. Log into the host remote in a private chat with the bot.",
+	"group.no_quizzes": "The host has no quizzes yet.",
+	"group.pick_quiz":  "📋 <b>Choose a quiz for the group</b>",
+	"group.lobby":      "🎲 <b>%s</b>\n📝 Questions: <b>%d</b>\n👥 Players: <b>%d</b>\n\nTap “I'm in” to play. You can also just tap an answer once the quiz starts.",
+	"group.cancelled":  "✖️ Quiz <b>%s</b> cancelled.",
+	"group.joined":     "🎮 You're in as %s",
+	"group.already":    "You're already in",
+	"group.answers":    "📊 Answered: <b>%d</b>",
+	"group.dm_only":    "<i>This question is answered in a private chat with the bot.</i>",
+	"group.again":      "To play again, the host sends /quiz",
+}
@@ -0,0 +1,209 @@
+package i18n
+
+import "strings"
+
+// APILocale is the language API error messages are written in.
+const APILocale = "en"
+
+// apiErrors translates API error messages from English. Keys ending in ": "
+// are prefixes followed by a detail, which is translated on its own.
+var apiErrors = map[string]map[string]string{
+	"ru": {
+		// Request validation
+		"invalid room id":         "неверный id комнаты",
+		"invalid session id":      "неверный id сессии",
+		"invalid session_id":      "неверный session_id",
+		"invalid quiz id":         "неверный id квиза",
+		"invalid question id":     "неверный id вопроса",
+		"invalid category id":     "неверный id категории",
+		"invalid image id":        "неверный id изображения",
+		"invalid member_id":       "неверный member_id",
+		"invalid ban id":          "неверный id блокировки",
+		"invalid telegram_id":     "неверный telegram_id",
+		"host_id is required":     "требуется host_id",
+		"token and code required": "требуются токен и код",
+		"invalid JSON: ":          "некорректный JSON: ",
+
+		// Auth
+		"authorization header required":       "требуется заголовок Authorization",
+		"authorization required":              "требуется авторизация",
+		"invalid authorization header format": "неверный формат заголовка Authorization",
+		"invalid authorization header":        "неверный заголовок Authorization",
+		"invalid or expired token":            "токен недействителен или истёк",
+		"invalid bot API key":                 "неверный API-ключ бота",
+		"invalid credentials":                 "неверный логин или пароль",
+		"username already taken":              "имя пользователя уже занято",
+		"invalid or expired refresh token":    "refresh-токен недействителен или истёк",
+		"refresh token already rotated":       "refresh-токен уже обновлён",
+		"login session not found":             "сеанс входа не найден",
+		"unauthorized":                        "нет доступа",
+		"access denied":                       "доступ запрещён",
+		"too many requests, try again later":  "слишком много запросов, попробуйте позже",
+
+		// Single sign-on
+		"this account must sign in with single sign-on":                        "этот аккаунт должен входить через единый вход (SSO)",
+		"single sign-on is not configured":                                     "единый вход (SSO) не настроен",
+		"sign-in expired or was already used, try again":                       "вход устарел или уже использован, попробуйте снова",
+		"identity provider error: ":                                            "ошибка провайдера входа: ",
+		"no account is linked to this identity":                                "к этой учётной записи не привязан аккаунт",
+		"this identity is linked to another account":                           "эта учётная запись привязана к другому аккаунту",
+		"identity not found":                                                   "учётная запись не найдена",
+		"invalid identity id":                                                  "неверный id учётной записи",
+		"the last identity can't be unlinked while single sign-on is required": "последнюю учётную запись нельзя отвязать, пока обязателен единый вход",
+		"set a password before unlinking the last identity":                    "задайте пароль, прежде чем отвязывать последнюю учётную запись",
+		"sign in with single sign-on before requiring it":                      "чтобы сделать единый вход обязательным, войдите через него",
+
+		// Workspaces
+		"invalid workspace id":                    "неверный id рабочего пространства",
+		"no access to this workspace":             "нет доступа к этому рабочему пространству",
+		"insufficient permissions":                "недостаточно прав",
+		"invalid member id":                       "неверный id участника",
+		"invalid role":                            "неверная роль",
+		"user not found":                          "пользователь не найден",
+		"user is already a member":                "пользователь уже в команде",
+		"the workspace owner is already a member": "владелец уже состоит в команде",
+
+		// API keys
+		"invalid API key":                      "неверный API-ключ",
+		"API key expired":                      "срок действия API-ключа истёк",
+		"API key not found":                    "API-ключ не найден",
+		"API key lacks scope: ":                "у API-ключа нет права: ",
+		"invalid key id":                       "неверный id ключа",
+		"key name must be 1 to 100 characters": "название ключа должно быть от 1 до 100 символов",
+		"invalid scope: ":                      "неизвестное право: ",
+		"at least one scope is required":       "нужно выбрать хотя бы одно право",
+		"expires_in_days must not be negative": "expires_in_days не может быть отрицательным",
+
+		// Settings
+		"host not found":           "ведущий не найден",
+		"invalid bot token":        "Невалидный токен бота. Проверьте токен и попробуйте снова.",
+		"unsupported bot language": "неподдерживаемый язык бота",
+
+		// Host remote
+		"remote password must be at most 72 characters": "пароль пульта должен быть не длиннее 72 символов",
+		"wrong remote password or pairing code":         "неверный пароль пульта или код подключения",
+		"too many failed attempts, try again later":     "слишком много неудачных попыток, попробуйте позже",
+		"remote authorization not found":                "доступ к пульту не найден",
+		"invalid authorization id":                      "неверный id доступа",
+
+		// Audit log
+		"invalid target_id": "неверный target_id",
+		"invalid before_id": "неверный before_id",
+		"invalid from":      "неверная дата from",
+		"invalid to":        "неверная дата to",
+		"invalid limit":     "неверный limit",
+
+		// Files and import
+		"no file provided":                      "файл не передан",
+		"file required":                         "требуется файл",
+		"cannot read file":                      "не удалось прочитать файл",
+		"failed to save file":                   "не удалось сохранить файл",
+		"file too large (max 100MB)":            "файл слишком большой (макс. 100 МБ)",
+		"unsupported file format":               "неподдерживаемый формат файла",
+		"invalid CSV: ":                         "некорректный CSV: ",
+		"CSV must have header + at least 1 row": "CSV должен содержать заголовок и хотя бы одну строку",
+
+		// AI generation
+		"AI generation is not configured. Set QWEN_API_KEY.": "AI-генерация не настроена. Задайте QWEN_API_KEY.",
+		"AI generation failed: ":                             "ошибка AI-генерации: ",
+		"Failed to create quiz: ":                            "не удалось создать квиз: ",
+		"Failed to import generated questions: ":             "не удалось импортировать вопросы: ",
+
+		// Quizzes and questions
+		"quiz not found":                                             "квиз не найден",
+		"quiz not found or access denied":                            "квиз не найден или нет доступа",
+		"question not found":                                         "вопрос не найден",
+		"category not found":                                         "категория не найдена",
+		"image not found":                                            "изображение не найдено",
+		"no questions in quiz":                                       "в квизе нет вопросов",
+		"quiz must have at least one question":                       "в квизе должен быть хотя бы один вопрос",
+		"unknown question type: ":                                    "неизвестный тип вопроса: ",
+		"exactly one option must be marked as correct":               "правильным должен быть ровно один вариант",
+		"at least one option must be correct":                        "хотя бы один вариант должен быть правильным",
+		"at least one option must be incorrect":                      "хотя бы один вариант должен быть неправильным",
+		"single choice must have 2 to 6 options":                     "в вопросе с одним ответом должно быть от 2 до 6 вариантов",
+		"multiple choice must have 2 to 6 options":                   "в вопросе с несколькими ответами должно быть от 2 до 6 вариантов",
+		"ordering must have 2 to 8 items":                            "в вопросе на порядок должно быть от 2 до 8 элементов",
+		"matching must have 2 to 8 pairs":                            "в вопросе на соответствие должно быть от 2 до 8 пар",
+		"each ordering item must have a correct_position":            "у каждого элемента должна быть correct_position",
+		"correct_position must be between 1 and the number of items": "correct_position должна быть от 1 до числа элементов",
+		"correct_position values must be unique":                     "значения correct_position не должны повторяться",
+		"each matching item must have a match_text":                  "у каждой пары должен быть match_text",
+		"numeric question must have a correct_number":                "у числового вопроса должен быть correct_number",
+
+		// Rooms and sessions
+		"room not found":                                     "комната не найдена",
+		"room not found or closed":                           "комната не найдена или закрыта",
+		"no active session":                                  "нет активной сессии",
+		"no session found":                                   "сессия не найдена",
+		"session not found":                                  "сессия не найдена",
+		"session not found or already finished":              "сессия не найдена или уже завершена",
+		"session already finished":                           "сессия уже завершена",
+		"quiz already started":                               "квиз уже начался",
+		"session is not accepting answers":                   "ответы сейчас не принимаются",
+		"session is not accepting new participants":          "сессия не принимает новых участников",
+		"must reveal answer before moving to next question":  "сначала покажите ответ, затем переходите к следующему вопросу",
+		"no active question to reveal":                       "нет открытого вопроса",
+		"answer not revealed yet":                            "ответ ещё не показан",
+		"auto_reveal_delay must be between 0 and 60 seconds": "auto_reveal_delay должна быть от 0 до 60 секунд",
+		"failed to join session: ":                           "не удалось подключиться к сессии: ",
+		"failed to join room: ":                              "не удалось войти в комнату: ",
+
+		// Players and answers
+		"member not found":                           "участник не найден",
+		"player not found":                           "игрок не найден",
+		"participant not found":                      "участник не найден",
+		"participant not found in session":           "участник не найден в сессии",
+		"member_id or telegram_id is required":       "требуется member_id или telegram_id",
+		"ban not found":                              "блокировка не найдена",
+		"you are banned from this room":              "вам закрыт доступ в эту комнату",
+		"nickname is not allowed":                    "такой никнейм недопустим",
+		"invalid or expired player token":            "токен игрока недействителен или истёк",
+		"player token was replaced, reconnect again": "токен игрока заменён, подключитесь заново",
+		"token does not belong to this member":       "токен принадлежит другому участнику",
+		"failed to issue player token":               "не удалось выдать токен игрока",
+		"token required":                             "требуется токен",
+		"nickname must be 1-100 characters":          "никнейм должен быть от 1 до 100 символов",
+		"invalid answer data":                        "некорректный ответ",
+		"invalid option for current question":        "такого варианта нет в текущем вопросе",
+		"no options selected":                        "не выбрано ни одного варианта",
+		"no numeric value provided":                  "не указано число",
+
+		// Chat
+		"message is empty":                        "сообщение пустое",
+		"message is too long":                     "сообщение слишком длинное",
+		"chat is disabled in this room":           "чат в этой комнате отключён",
+		"chat is paused while a question is open": "чат приостановлен, пока открыт вопрос",
+		"too many messages, slow down":            "слишком много сообщений, не так быстро",
+		"too many reactions, slow down":           "слишком много реакций, не так быстро",
+		"unsupported reaction":                    "неподдерживаемая реакция",
+		"you are muted in this room":              "вам отключены сообщения в этой комнате",
+
+		// Account
+		"current password is incorrect":                "текущий пароль неверен",
+		"invalid or expired reset token":               "ссылка для сброса пароля недействительна или истекла",
+		"sign in again with single sign-on to confirm": "для подтверждения войдите заново через единый вход",
+		"type your username to confirm":                "введите имя пользователя для подтверждения",
+		"username must be 3-100 characters":            "имя пользователя должно быть от 3 до 100 символов",
+		"invalid admin API key":                        "неверный ключ администратора",
+		"failed to deliver reset link: ":               "не удалось отправить ссылку для сброса: ",
+	},
+}
+
+// Error translates an API error message into locale. Messages without a
+// translation are returned unchanged.
+func Error(locale, msg string) string {
+	table, ok := apiErrors[locale]
+	if !ok {
+		return msg
+	}
+	if translated, ok := table[msg]; ok {
+		return translated
+	}
+	for key, translated := range table {
+		if strings.HasSuffix(key, ": ") && strings.HasPrefix(msg, key) {
+			return translated + Error(locale, strings.TrimPrefix(msg, key))
+		}
+	}
+	return msg
+}
@@ -0,0 +1,90 @@
+// Package i18n holds the message catalogs for bot and API texts.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Default is the locale used when neither the user nor the host picked one.
+const Default = "ru"
+
+// Supported lists the available locales in the order they are offered to users.
+var Supported = []string{"ru", "en"}
+
+var catalogs = map[string]map[string]string{
+	"ru": ru,
+	"en": en,
+}
+
+// buttonKeys are the reply-keyboard labels. Incoming message text is matched
+// against them in every locale, so routing doesn't depend on the language the
+// keyboard was rendered in.
+var buttonKeys = []string{"btn.join", "btn.profile", "btn.history", "btn.rejoin", "btn.host"}
+
+var buttonsByLabel = make(map[string]string)
+
+func init() {
+	for _, catalog := range catalogs {
+		for _, key := range buttonKeys {
+			if label, ok := catalog[key]; ok {
+				buttonsByLabel[label] = key
+			}
+		}
+	}
+}
+
+// T renders the message for key in locale, falling back to the default
+// locale and then to the key itself. args are applied with fmt.Sprintf.
+func T(locale, key string, args ...interface{}) string {
+	msg, ok := catalogs[locale][key]
+	if !ok {
+		msg, ok = catalogs[Default][key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Match returns the supported locale for a language tag such as "en-US", or
+// "" if there is none.
+func Match(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if _, ok := catalogs[tag]; ok {
+		return tag
+	}
+	return ""
+}
+
+// IsSupported reports whether locale has a catalog.
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Button returns the key of the reply-keyboard button with the given label in
+// any locale.
+func Button(label string) (string, bool) {
+	key, ok := buttonsByLabel[label]
+	return key, ok
+}
+
+// FromAcceptLanguage picks the first supported locale of an Accept-Language
+// header, ignoring quality weights beyond their order. It returns "" if the
+// header names no supported language.
+func FromAcceptLanguage(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		tag := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if locale := Match(tag); locale != "" {
+			return locale
+		}
+	}
+	return ""
+}
@@ -0,0 +1,220 @@
+package i18n
+
+var ru = map[string]string{
+	"lang.name": "Русский",
+
+	// Reply keyboard
+	"btn.join":    "🎮 Войти в квиз",
+	"btn.profile": "👤 Мой профиль",
+	"btn.history": "📊 История игр",
+	"btn.rejoin":  "🔄 Переподключиться",
+	"btn.host":    "🎯 Пульт ведущего",
+
+	// Inline keyboards
+	"kb.start_quiz":   "▶️ Начать квиз",
+	"kb.reveal":       "👁 Показать ответ",
+	"kb.stop_quiz":    "⏭ Завершить квиз",
+	"kb.next":         "➡️ Следующий вопрос",
+	"kb.finish_quiz":  "🏆 Завершить квиз",
+	"kb.refresh":      "🔄 Обновить",
+	"kb.back_room":    "🔙 К комнате",
+	"kb.back_rooms":   "🔙 К комнатам",
+	"kb.new_room":     "➕ Новая комната",
+	"kb.create_room":  "➕ Создать комнату",
+	"kb.pick_quiz":    "📋 Выбрать квиз",
+	"kb.players":      "👥 Игроки",
+	"kb.close_room":   "❌ Закрыть комнату",
+	"kb.confirm":      "✅ Подтвердить",
+	"kb.undo":         "↩️ Отменить",
+	"kb.lang_auto":    "🔄 Как в Telegram",
+	"kb.join":         "🙋 Играю",
+	"kb.cancel":       "✖️ Отменить",
+	"kb.answer_dm":    "💬 Ответить в личке",
+	"kb.quizzes":      "📚 Квизы",
+	"kb.new_quiz":     "➕ Новый квиз",
+	"kb.import_quiz":  "📥 Импорт из файла",
+	"kb.ai_quiz":      "✨ Сгенерировать",
+	"kb.add_question": "➕ Добавить вопрос",
+	"kb.play_quiz":    "▶️ Запустить в новой комнате",
+	"kb.back_quizzes": "🔙 К квизам",
+	"kb.type_single":  "🔘 Один ответ",
+	"kb.type_multi":   "☑️ Несколько ответов",
+	"kb.type_numeric": "🔢 Число",
+
+	// General
+	"menu.hint":         "Используйте /start или кнопки меню.",
+	"error.generic":     "Ошибка: %s",
+	"error.retry_start": "Ошибка. Попробуйте /start",
+	"error.bad_data":    "Неверные данные",
+	"error.unknown_cmd": "Неизвестная команда",
+	"common.refreshed":  "🔄 Обновлено",
+
+	// Language
+	"lang.choose": "🌐 Выберите язык бота:",
+	"lang.set":    "✅ Язык бота: <b>%s</b>",
+	"lang.auto":   "✅ Язык бота будет совпадать с языком Telegram.",
+
+	// Start and join
+	"start.in_session":   "🎮 Вы сейчас в активной сессии.\n\nНажмите <b>%s</b> чтобы вернуться в игру, или введите новый код.",
+	"start.welcome_code": "👋 Добро пожаловать в Quiz Game!\n\nКод комнаты: <b>%s</b>\nВведите ваш никнейм:",
+	"start.greeting":     "👋 Привет, <b>%s</b>!\n\nВыберите действие:",
+	"start.welcome":      "👋 Добро пожаловать в Quiz Game!\n\nВведите ваш никнейм:",
+	"join.enter_code":    "Введите 6-значный код комнаты:",
+	"join.bad_code":      "❌ Код должен состоять из 6 цифр. Попробуйте ещё раз:",
+	"join.code_ok":       "✅ Код принят: <b>%s</b>\n\nВведите ваш никнейм:",
+	"join.banned":        "⛔ Ведущий закрыл вам доступ в эту комнату.",
+	"join.failed":        "❌ Ошибка: %s\n\nПопробуйте /start заново.",
+	"join.rejoined":      "🔄 Вы переподключились к квизу!\n\nНикнейм: <b>%s</b>",
+	"join.joined":        "🎮 Вы подключились к квизу!\n\nНикнейм: <b>%s</b>\nОжидайте начала игры...",
+
+	// Session
+	"session.not_found":    "Сессия не найдена. Используйте /start",
+	"session.finished":     "Сессия завершена. Нажмите /start для новой игры.",
+	"session.reconnecting": "🔄 Переподключение к квизу...",
+	"session.not_joined":   "Вы не подключены к сессии. Нажмите /start",
+
+	// Nickname, profile, history
+	"nick.bad_length":  "❌ Никнейм должен быть от 1 до 100 символов. Попробуйте ещё раз:",
+	"nick.blocked":     "❌ Такой никнейм недопустим. Выберите другой:",
+	"nick.set":         "✅ Никнейм установлен: <b>%s</b>\n\nВыберите действие:",
+	"nick.usage":       "Использование: /nickname Ваш_новый_ник",
+	"nick.too_long":    "Никнейм слишком длинный (макс 100 символов)",
+	"nick.changed":     "✅ Никнейм изменён на: <b>%s</b>",
+	"nick.not_allowed": "❌ Такой никнейм недопустим.",
+	"profile.failed":   "Ошибка загрузки профиля",
+	"profile.show":     "👤 <b>Ваш профиль</b>\n\nНикнейм: <b>%s</b>\n\nЧтобы изменить ник, отправьте:\n/nickname Новый_ник\n\nСменить язык: /language",
+	"history.empty":    "📊 У вас пока нет завершённых игр.",
+	"history.title":    "📊 <b>Ваша история игр:</b>\n",
+	"history.entry":    "%s <b>%s</b>\n   Очки: %d | Место: %d/%d",
+
+	// Questions
+	"status.waiting":    "ожидание",
+	"status.question":   "вопрос",
+	"status.revealed":   "ответ показан",
+	"status.finished":   "завершён",
+	"question.header":   "❓ <b>Вопрос %d из %d</b>",
+	"question.closed":   "Этот вопрос уже закрыт",
+	"question.multi":    "<i>Выберите все правильные ответы и нажмите «Подтвердить»</i>",
+	"question.numeric":  "<i>Введите число в чат:</i>",
+	"question.ordering": "<i>Нажимайте варианты по порядку, начиная с первого</i>",
+	"question.match_to": "Что соответствует: <b>%s</b>?",
+
+	// Answers
+	"answer.not_in_session": "Вы не в активной сессии",
+	"answer.rejoin_hint":    "Вы не в активной сессии. Нажмите /rejoin",
+	"answer.time_up":        "Время для ответа вышло",
+	"answer.reconnecting":   "Переподключение...",
+	"answer.accepted":       "✅ Ответ принят!",
+	"answer.accepted_note":  "✅ <b>Ответ принят</b>",
+	"answer.yours_accepted": "✅ <b>Ваш ответ принят</b>",
+	"answer.multi_selected": "<i>Выбрано: %d</i>",
+	"answer.multi_empty":    "Выберите хотя бы один ответ",
+	"answer.multi_accepted": "✅ <b>Ответ принят (выбрано: %d)</b>",
+	"answer.ordering_done":  "<i>Порядок собран — подтвердите ответ</i>",
+	"answer.ordering_short": "Расставьте все варианты",
+	"answer.matching_done":  "<i>Все пары выбраны — подтвердите ответ</i>",
+	"answer.matching_short": "Сопоставьте все пары",
+	"numeric.invalid":       "⚠️ Введите корректное число.",
+	"numeric.no_question":   "⚠️ Нет активного вопроса.",
+	"numeric.accepted":      "✅ <b>Ваш ответ: %s</b>\n\nОжидайте результат...",
+
+	// Reactions
+	"react.not_in_room": "Вы не в этой комнате",
+	"react.slow_down":   "Не так быстро 🙂",
+	"react.muted":       "Ведущий отключил вам реакции",
+
+	// Results
+	"result.missed":     "⏰ Вы не успели ответить",
+	"result.correct":    "✅ <b>Правильно!</b>",
+	"result.wrong":      "❌ <b>Неправильно</b>",
+	"result.total":      "Всего очков: <b>%d</b>",
+	"result.score":      "Очки за вопрос: <b>+%d</b> | Всего: <b>%d</b>",
+	"result.answer":     "Правильный ответ: <b>%s</b>",
+	"result.answers":    "Правильные: <b>%s</b>",
+	"result.order":      "Правильный порядок:",
+	"result.pairs":      "Правильные пары:",
+	"result.wait_next":  "⏳ Ожидайте следующий вопрос...",
+	"leaderboard.title": "🏆 <b>Квиз завершён! Итоги:</b>\n",
+	"leaderboard.entry": "%s <b>%s</b> — %d очков",
+	"leaderboard.place": "📍 Ваше место: <b>%d</b>",
+	"leaderboard.again": "Для новой игры нажмите /start",
+
+	// Host remote
+	"remote.title":          "🎯 <b>Пульт ведущего</b>",
+	"remote.in_remote":      "🎯 Вы в режиме пульта. Используйте кнопки в сообщении выше.\n\nДля выхода нажмите /start",
+	"remote.pairing_only":   "🔐 Пароль для пульта не задан. Введите код подключения из настроек на сайте или откройте ссылку подключения оттуда:",
+	"remote.password":       "🔐 Введите пароль пульта ведущего или код подключения из настроек на сайте:",
+	"remote.wrong_password": "❌ Неверный пароль или код. Попробуйте ещё раз:",
+	"remote.locked":         "🔒 Слишком много неудачных попыток. Попробуйте через %d мин.",
+	"remote.revoked":        "🔒 Доступ к пульту отозван или пароль изменён. Авторизуйтесь заново.",
+	"remote.auth_required":  "Авторизуйтесь: /start → %s",
+	"remote.no_rooms":       "📋 Нет активных комнат.\n\nСоздайте комнату прямо здесь или на сайте.",
+	"remote.pick_room":      "✅ Авторизация успешна!\nВыберите комнату:",
+	"remote.room":           "🚪 <b>Комната %s</b>\n\n👥 Участников: <b>%d</b>\nРежим: <b>%s</b>",
+	"remote.room_session":   "🎮 Квиз: <b>%s</b>\nСтатус: <b>%s</b>",
+	"remote.quiz_label":     "📝 %s (%d вопр.)",
+	"remote.pick_quiz":      "📋 <b>Выберите квиз</b>\n\n%d квизов доступно",
+	"remote.room_created":   "✅ Комната создана",
+	"remote.room_closed":    "❌ Комната закрыта",
+	"remote.quiz_started":   "▶️ Квиз запущен!",
+	"remote.revealed":       "👁 Ответ показан",
+	"remote.next":           "➡️ Далее",
+	"remote.finished":       "🏆 Квиз завершён",
+	"remote.no_session":     "Сессия не найдена",
+	"remote.no_access":      "Нет доступа к этой сессии",
+	"control.waiting":       "📋 Квиз: <b>%s</b>\n🔑 Код: <b>%s</b>\n👥 Участников: <b>%d</b>\n📝 Вопросов: <b>%d</b>\n\n⏳ Ожидание участников...",
+	"control.category":      "📁 Категория: <b>%s</b>",
+	"control.answered":      "📊 Ответили: <b>%d</b> из <b>%d</b>",
+	"control.correct":       "✅ Правильный ответ: <b>%s</b>",
+	"control.finished":      "🏆 <b>Квиз завершён!</b>\n📋 %s\n👥 Участников: <b>%d</b>",
+	"control.not_found":     "Сессия не найдена.",
+
+	// Quiz editing
+	"quizzes.title":                 "📚 <b>Ваши квизы</b>\n\nВсего: %d. Выберите квиз или добавьте новый.",
+	"quizzes.empty":                 "📚 У вас пока нет квизов.\n\nСоздайте квиз вручную, загрузите файл или сгенерируйте его.",
+	"quiz.card":                     "📋 <b>%s</b>\n\n📝 Вопросов: <b>%d</b>",
+	"quiz.enter_title":              "✏️ Введите название квиза:",
+	"quiz.bad_title":                "Название должно быть от 1 до %d символов. Попробуйте ещё раз:",
+	"quiz.created":                  "✅ Квиз создан",
+	"question.pick_type":            "❓ <b>Новый вопрос</b>\n\nВыберите тип вопроса:",
+	"question.enter_text":           "✏️ Введите текст вопроса:",
+	"question.enter_options_single": "📝 Отправьте варианты ответа, по одному в строке (от 2 до 6). Отметьте правильный звёздочкой:\n\n<code>*Париж\nЛондон\nБерлин</code>",
+	"question.enter_options_multi":  "📝 Отправьте варианты ответа, по одному в строке (от 2 до 6). Отметьте звёздочкой все правильные:\n\n<code>*Марс\n*Венера\nЛуна</code>",
+	"question.enter_number":         "🔢 Отправьте правильный ответ. Допуск можно указать через ±:\n\n<code>1961</code> или <code>100 ± 5</code>",
+	"question.bad_number":           "Не удалось разобрать число. Пример: <code>42</code> или <code>42 ± 2</code>",
+	"question.added":                "✅ Вопрос добавлен",
+	"import.prompt":                 "📥 Отправьте файл квиза в формате JSON или CSV (до 1 МБ) — такой же, как при экспорте на сайте.",
+	"import.not_file":               "Отправьте файл документом.",
+	"import.too_large":              "Файл слишком большой. Максимум — %d КБ.",
+	"import.failed":                 "Не удалось импортировать файл: %s",
+	"import.done":                   "✅ Импортировано вопросов: %d",
+	"ai.prompt":                     "✨ Опишите квиз, который нужно сгенерировать, например: <i>10 вопросов о космосе для школьников</i>",
+	"ai.unavailable":                "AI-генерация не настроена на сервере.",
+	"ai.generating":                 "⏳ Генерирую квиз, это может занять до минуты...",
+	"ai.failed":                     "Не удалось сгенерировать квиз. Попробуйте другой запрос.",
+	"ai.done":                       "✅ Сгенерировано вопросов: %d",
+
+	// Players
+	"players.title":   "👥 <b>Игроки комнаты %s</b>",
+	"players.legend":  "✏️ — переименовать, 🚪 — выгнать, ⛔ — выгнать и заблокировать",
+	"players.empty":   "Пока никого нет.",
+	"players.rename":  "✏️ Введите новый никнейм для игрока:",
+	"players.renamed": "✅ Игрок переименован: <b>%s</b>",
+	"players.banned":  "⛔ %s заблокирован",
+	"players.kicked":  "🚪 %s удалён",
+	"kicked.removed":  "🚪 Ведущий удалил вас из комнаты.",
+	"kicked.banned":   "⛔ Ведущий удалил вас из комнаты и закрыл доступ.",
+
+	// Group chats
+	"group.help":       "👋 Здесь можно играть в квиз всей группой.\n\nВедущий запускает квиз командой /quiz (нужен вход в пульт ведущего в личке с ботом), участники отвечают кнопками под вопросом.",
+	"group.only_host":  "Запускать квиз и управлять им может только ведущий. Войдите в пульт ведущего в личке с ботом.",
+	"group.no_quizzes": "У ведущего пока нет квизов.",
+	"group.pick_quiz":  "📋 <b>Выберите квиз для группы</b>",
+	"group.lobby":      "🎲 <b>%s</b>\n📝 Вопросов: <b>%d</b>\n👥 Участников: <b>%d</b>\n\nНажмите «Играю», чтобы участвовать. Отвечать можно и без этого — просто нажмите на вариант.",
+	"group.cancelled":  "✖️ Квиз <b>%s</b> отменён.",
+	"group.joined":     "🎮 Вы в игре как %s",
+	"group.already":    "Вы уже в игре",
+	"group.answers":    "📊 Ответили: <b>%d</b>",
+	"group.dm_only":    "<i>На этот вопрос отвечают в личке с ботом.</i>",
+	"group.again":      "Чтобы сыграть ещё раз, ведущий отправляет /quiz",
+}
@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"quiz-game-backend/internal/models"
 	"quiz-game-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -23,17 +26,58 @@ func JWTAuth(authService *services.AuthService) gin.HandlerFunc {
 			return
 		}
 
-		hostID, err := authService.ValidateToken(parts[1])
+		info, err := authService.ValidateToken(parts[1])
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
 			return
 		}
 
-		c.Set("host_id", hostID)
+		setTokenInfo(c, info)
 		c.Next()
 	}
 }
 
+func setTokenInfo(c *gin.Context, info *services.TokenInfo) {
+	c.Set("host_id", info.HostID)
+	c.Set("user_id", info.HostID)
+	c.Set("auth_session_id", info.SessionID)
+	c.Set("auth_method", info.Method)
+}
+
+// HostAuth authenticates a host by JWT, or a script by a workspace API key
+// sent as "Bearer qk_..." or in the X-API-Key header.
+func HostAuth(authService *services.AuthService, keyService *services.APIKeyService) gin.HandlerFunc {
+	jwtAuth := JWTAuth(authService)
+	return func(c *gin.Context) {
+		if key := apiKeyFromRequest(c); key != "" {
+			authenticateAPIKey(c, keyService, key)
+			return
+		}
+		jwtAuth(c)
+	}
+}
+
+func apiKeyFromRequest(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && strings.HasPrefix(token, services.APIKeyPrefix) {
+		return token
+	}
+	return ""
+}
+
+func authenticateAPIKey(c *gin.Context, keyService *services.APIKeyService, key string) {
+	auth, err := keyService.Authenticate(key, c.ClientIP())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	c.Set("host_id", auth.HostID)
+	c.Set("api_key", auth)
+	c.Next()
+}
+
 func BotAuth(apiKey string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		key := c.GetHeader("X-Bot-API-Key")
@@ -45,12 +89,29 @@ func BotAuth(apiKey string) gin.HandlerFunc {
 	}
 }
 
-func FlexAuth(authService *services.AuthService, botAPIKey string) gin.HandlerFunc {
+// AdminAuth admits operator requests carrying the administrator key in
+// X-Admin-API-Key.
+func AdminAuth(apiKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-Admin-API-Key")
+		if key == "" || subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid admin API key"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func FlexAuth(authService *services.AuthService, keyService *services.APIKeyService, botAPIKey string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if key := c.GetHeader("X-Bot-API-Key"); key != "" && key == botAPIKey {
 			c.Next()
 			return
 		}
+		if key := apiKeyFromRequest(c); key != "" {
+			authenticateAPIKey(c, keyService, key)
+			return
+		}
 
 		header := c.GetHeader("Authorization")
 		if header == "" {
@@ -64,13 +125,88 @@ func FlexAuth(authService *services.AuthService, botAPIKey string) gin.HandlerFu
 			return
 		}
 
-		hostID, err := authService.ValidateToken(parts[1])
+		info, err := authService.ValidateToken(parts[1])
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
 			return
 		}
 
-		c.Set("host_id", hostID)
+		setTokenInfo(c, info)
+		c.Next()
+	}
+}
+
+// Workspace picks the workspace a host request acts in from the
+// X-Workspace-ID header, defaulting to the caller's own, and checks the
+// caller belongs to it, signed in with single sign-on if the workspace
+// requires it. host_id then holds the workspace, so handlers and
+// services scope by it unchanged; the caller's account stays in user_id.
+// API keys are bound to their workspace; requests authenticated with the
+// bot key pass through.
+func Workspace(access *services.AccessService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("api_key"); ok {
+			if raw := c.GetHeader("X-Workspace-ID"); raw != "" && raw != strconv.FormatUint(uint64(c.GetUint("host_id")), 10) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": services.ErrNoWorkspaceAccess.Error(), "code": "workspace_access"})
+				return
+			}
+			c.Next()
+			return
+		}
+		userID := c.GetUint("user_id")
+		if userID == 0 {
+			c.Next()
+			return
+		}
+
+		workspaceID := userID
+		if raw := c.GetHeader("X-Workspace-ID"); raw != "" {
+			id, err := strconv.ParseUint(raw, 10, 64)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid workspace id"})
+				return
+			}
+			workspaceID = uint(id)
+		}
+
+		role, err := access.Role(userID, workspaceID)
+		if err != nil {
+			// The code lets clients tell a lost workspace from a missing permission.
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": "workspace_access"})
+			return
+		}
+		if c.GetString("auth_method") != models.AuthMethodSSO && access.SSORequired(workspaceID) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": services.ErrSSORequired.Error(), "code": "sso_required"})
+			return
+		}
+
+		c.Set("host_id", workspaceID)
+		c.Set("role", role)
+		c.Next()
+	}
+}
+
+// Require lets the request through only if the caller's role in the
+// workspace, or the API key's scopes, grant perm. Bot-key requests carry
+// neither and pass.
+func Require(perm services.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if v, ok := c.Get("api_key"); ok {
+			if !v.(*services.APIKeyAuth).Can(perm) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key lacks scope: " + string(perm)})
+				return
+			}
+			c.Next()
+			return
+		}
+		if c.GetUint("user_id") == 0 {
+			c.Next()
+			return
+		}
+		if !services.RoleCan(c.GetString("role"), perm) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			return
+		}
 		c.Next()
 	}
 }
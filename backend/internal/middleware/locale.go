@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"quiz-game-backend/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// localeWriter holds back JSON error bodies so their message can be
+// translated before it is sent.
+type localeWriter struct {
+	gin.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *localeWriter) buffering() bool {
+	return w.Status() >= http.StatusBadRequest &&
+		strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+}
+
+func (w *localeWriter) Write(data []byte) (int, error) {
+	if w.buffering() {
+		return w.buf.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *localeWriter) WriteString(s string) (int, error) {
+	if w.buffering() {
+		return w.buf.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Localize translates the "error" field of JSON error responses into the
+// language requested by the Accept-Language header. Messages are written in
+// English, so other requests pass through untouched.
+func Localize() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := i18n.FromAcceptLanguage(c.GetHeader("Accept-Language"))
+		if locale == "" || locale == i18n.APILocale {
+			c.Next()
+			return
+		}
+
+		w := &localeWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if w.buf.Len() == 0 {
+			return
+		}
+		body := w.buf.Bytes()
+		var payload map[string]interface{}
+		if err := json.Unmarshal(body, &payload); err == nil {
+			if msg, ok := payload["error"].(string); ok {
+				payload["error"] = i18n.Error(locale, msg)
+				if translated, err := json.Marshal(payload); err == nil {
+					body = translated
+				}
+			}
+		}
+		w.ResponseWriter.Write(body)
+	}
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"quiz-game-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateBodyLimit caps how much of a request body is read to find a rate key.
+const rateBodyLimit = 64 << 10
+
+// RatePolicy allows Limit requests per Window for each key. Requests for
+// which Key returns "" are not counted.
+type RatePolicy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+	Key    func(c *gin.Context) string
+}
+
+// ByIP keys requests by client address.
+func ByIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// normalizeKey makes values that differ only in case or surrounding spaces,
+// such as "Host1" and "host1 ", count as one key.
+func normalizeKey(v string) string {
+	return strings.ToLower(strings.TrimSpace(v))
+}
+
+// ByParam keys requests by a path parameter.
+func ByParam(name string) func(c *gin.Context) string {
+	return func(c *gin.Context) string {
+		return normalizeKey(c.Param(name))
+	}
+}
+
+// ByField keys requests by a query parameter or top-level JSON body field,
+// such as a player token or a login name.
+func ByField(name string) func(c *gin.Context) string {
+	return func(c *gin.Context) string {
+		if v := normalizeKey(c.Query(name)); v != "" {
+			return v
+		}
+		if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+			return ""
+		}
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, rateBodyLimit))
+		if err != nil {
+			return ""
+		}
+		c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(body, &fields) != nil {
+			return ""
+		}
+		var v string
+		if json.Unmarshal(fields[name], &v) != nil {
+			return ""
+		}
+		return normalizeKey(v)
+	}
+}
+
+// RateLimit rejects requests over any of the policies with 429 and a
+// Retry-After header.
+func RateLimit(limiter *services.RateLimiter, policies ...RatePolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, p := range policies {
+			key := p.Key(c)
+			if key == "" {
+				continue
+			}
+			if ok, retryAfter := limiter.Allow("rl:"+p.Name+":"+key, p.Limit, p.Window); !ok {
+				tooManyRequests(c, retryAfter)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// CodeLockout blocks a client address for the rest of window after it asked
+// for maxMisses different codes that ended in 404, which is how guessing room
+// codes or display tokens looks. Repeats of one code, e.g. every player of a
+// class polling a room that was just closed, count once.
+func CodeLockout(limiter *services.RateLimiter, name string, code func(c *gin.Context) string, maxMisses int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+		key := "lock:" + name + ":" + ip
+		if blocked, retryAfter := limiter.Blocked(key, maxMisses); blocked {
+			tooManyRequests(c, retryAfter)
+			return
+		}
+		value := code(c)
+		c.Next()
+		if c.Writer.Status() != http.StatusNotFound || value == "" {
+			return
+		}
+		if ok, _ := limiter.Allow("miss:"+name+":"+ip+":"+value, 1, window); ok {
+			limiter.Record(key, window)
+		}
+	}
+}
+
+func tooManyRequests(c *gin.Context, retryAfter time.Duration) {
+	secs := int(math.Ceil(retryAfter.Seconds()))
+	if secs < 1 {
+		secs = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(secs))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"error":       "too many requests, try again later",
+		"retry_after": secs,
+	})
+}
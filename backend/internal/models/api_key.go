@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// APIKey lets scripts act in a workspace without a login. Only a SHA-256
+// hash of the key is stored; Prefix is kept to tell keys apart in lists.
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	HostID     uint       `gorm:"not null;index" json:"-"`
+	Host       Host       `gorm:"foreignKey:HostID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedBy  uint       `gorm:"not null" json:"created_by"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Prefix     string     `gorm:"size:16;not null" json:"prefix"`
+	KeyHash    string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Scopes     string     `gorm:"size:255;not null" json:"-"` // comma-separated permissions
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `gorm:"size:64" json:"last_used_ip,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// AuditEntry is one administrative action in a workspace. Entries are only
+// ever added: the database rejects updates and deletes, and WorkspaceID is
+// not a foreign key so the trail outlives what it describes. Before and
+// After are JSON summaries of the target around the action.
+type AuditEntry struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	WorkspaceID uint      `gorm:"not null;index:idx_audit_workspace_time" json:"workspace_id"`
+	ActorType   string    `gorm:"size:20;not null" json:"actor_type"`
+	ActorID     string    `gorm:"size:64" json:"actor_id"`
+	ActorName   string    `gorm:"size:255" json:"actor_name"`
+	Action      string    `gorm:"size:50;not null;index" json:"action"`
+	TargetType  string    `gorm:"size:20" json:"target_type"`
+	TargetID    uint      `gorm:"default:0" json:"target_id"`
+	Before      string    `gorm:"type:text" json:"-"`
+	After       string    `gorm:"type:text" json:"-"`
+	IP          string    `gorm:"size:64" json:"ip,omitempty"`
+	CreatedAt   time.Time `gorm:"index:idx_audit_workspace_time" json:"created_at"`
+}
+
+// Who performed an audited action.
+const (
+	AuditActorUser     = "user"
+	AuditActorAPIKey   = "api_key"
+	AuditActorTelegram = "telegram"
+	AuditActorSystem   = "system"
+)
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// AuthSession is a host login on one device. Only a SHA-256 hash of its
+// refresh token is stored; the token changes on every refresh and the
+// previous hash is kept to detect reuse of a stolen token.
+type AuthSession struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	HostID          uint       `gorm:"not null;index" json:"-"`
+	RefreshHash     string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	PrevRefreshHash string     `gorm:"size:64;index" json:"-"`
+	RotatedAt       time.Time  `json:"-"`
+	Method          string     `gorm:"size:20;not null;default:'password'" json:"method"`
+	UserAgent       string     `gorm:"size:255" json:"user_agent"`
+	IP              string     `gorm:"size:64" json:"ip"`
+	CreatedAt       time.Time  `json:"created_at"`
+	LastUsedAt      time.Time  `json:"last_used_at"`
+	ExpiresAt       time.Time  `gorm:"index" json:"expires_at"`
+	RevokedAt       *time.Time `json:"-"`
+}
+
+// How a login session was started.
+const (
+	AuthMethodPassword = "password"
+	AuthMethodSSO      = "sso"
+)
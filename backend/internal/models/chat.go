@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type ChatMessage struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	RoomID     uint      `gorm:"not null;index" json:"room_id"`
+	MemberID   uint      `gorm:"default:0" json:"member_id,omitempty"`
+	TelegramID int64     `gorm:"default:0" json:"telegram_id,omitempty"`
+	Nickname   string    `gorm:"size:100;not null" json:"nickname"`
+	Text       string    `gorm:"size:500;not null" json:"text"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+type RoomMute struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	RoomID     uint      `gorm:"not null;index" json:"room_id"`
+	MemberID   uint      `gorm:"default:0" json:"member_id,omitempty"`
+	TelegramID int64     `gorm:"default:0" json:"telegram_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
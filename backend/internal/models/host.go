@@ -5,9 +5,12 @@ import "time"
 type Host struct {
 	ID             uint      `gorm:"primaryKey" json:"id"`
 	Username       string    `gorm:"size:100;uniqueIndex;not null" json:"username"`
-	PasswordHash   string    `gorm:"size:255;not null" json:"-"`
-	BotToken       string    `gorm:"size:255" json:"bot_token,omitempty"`
+	PasswordHash   string    `gorm:"size:255;not null" json:"-"` // empty for accounts created by single sign-on
+	BotToken       string    `gorm:"type:text" json:"-"`         // encrypted, see services.BotTokenService
 	BotLink        string    `gorm:"size:255" json:"bot_link,omitempty"`
-	RemotePassword string    `gorm:"size:255" json:"-"`
+	RemotePassword string    `gorm:"size:255" json:"-"` // bcrypt hash
+	BotLanguage    string    `gorm:"size:10;default:ru" json:"bot_language"`
+	BotQuizPolls   bool      `gorm:"default:false" json:"bot_quiz_polls"`
+	SSORequired    bool      `gorm:"not null;default:false" json:"sso_required"` // workspace admits single sign-on logins only
 	CreatedAt      time.Time `json:"created_at"`
 }
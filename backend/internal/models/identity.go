@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// HostIdentity links an account at an external OpenID Connect provider to a
+// host account. An identity is the provider's issuer and subject; the email
+// is only shown to tell identities apart.
+type HostIdentity struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	HostID      uint       `gorm:"not null;index" json:"-"`
+	Host        Host       `gorm:"foreignKey:HostID;constraint:OnDelete:CASCADE" json:"-"`
+	Issuer      string     `gorm:"size:255;not null;uniqueIndex:idx_identity_subject" json:"issuer"`
+	Subject     string     `gorm:"size:255;not null;uniqueIndex:idx_identity_subject" json:"subject"`
+	Email       string     `gorm:"size:255" json:"email,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// OIDCLoginState is a sign-in started at the provider and not yet finished.
+// It is looked up by a hash of the state parameter and used once. LinkHostID
+// is set when a logged-in host is linking an identity rather than signing in.
+type OIDCLoginState struct {
+	StateHash  string    `gorm:"primaryKey;size:64"`
+	Nonce      string    `gorm:"size:64;not null"`
+	Verifier   string    `gorm:"size:128;not null"`
+	LinkHostID uint      `gorm:"default:0"`
+	ExpiresAt  time.Time `gorm:"not null;index"`
+}
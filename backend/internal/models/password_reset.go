@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// PasswordReset is a one-time token, issued by an administrator, that sets a
+// new password for a host account. Only a SHA-256 hash of the token is
+// stored, and a host has at most one at a time.
+type PasswordReset struct {
+	ID        uint      `gorm:"primaryKey"`
+	HostID    uint      `gorm:"not null;index"`
+	Host      Host      `gorm:"foreignKey:HostID;constraint:OnDelete:CASCADE"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time
+}
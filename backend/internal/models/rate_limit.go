@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// RateLimitCounter is a fixed-window request counter, used when rate limits
+// are shared between server instances.
+type RateLimitCounter struct {
+	Key       string    `gorm:"primaryKey;size:255"`
+	Count     int       `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// RemoteAuthorization lets a Telegram account use the host remote of a
+// workspace's bot until it is revoked from settings.
+type RemoteAuthorization struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	HostID     uint      `gorm:"not null;uniqueIndex:idx_remote_auth_host_tg" json:"-"`
+	Host       Host      `gorm:"foreignKey:HostID;constraint:OnDelete:CASCADE" json:"-"`
+	TelegramID int64     `gorm:"not null;uniqueIndex:idx_remote_auth_host_tg" json:"telegram_id"`
+	Name       string    `gorm:"size:255" json:"name"`
+	Method     string    `gorm:"size:20;not null" json:"method"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+const (
+	RemoteAuthPassword = "password"
+	RemoteAuthPairing  = "pairing"
+)
+
+// RemoteLoginAttempt counts failed remote logins of a Telegram account, for
+// lockout.
+type RemoteLoginAttempt struct {
+	ID          uint  `gorm:"primaryKey"`
+	HostID      uint  `gorm:"not null;uniqueIndex:idx_remote_attempt_host_tg"`
+	TelegramID  int64 `gorm:"not null;uniqueIndex:idx_remote_attempt_host_tg"`
+	Failures    int   `gorm:"not null;default:0"`
+	LockedUntil *time.Time
+	UpdatedAt   time.Time
+}
+
+// RemotePairingCode is a one-time code from the web panel that logs a
+// Telegram account into the host remote. Only its hash is stored.
+type RemotePairingCode struct {
+	ID        uint      `gorm:"primaryKey"`
+	HostID    uint      `gorm:"not null;index"`
+	Host      Host      `gorm:"foreignKey:HostID;constraint:OnDelete:CASCADE"`
+	CodeHash  string    `gorm:"size:64;not null;uniqueIndex"`
+	CreatedBy uint      `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time
+}
@@ -3,13 +3,16 @@ package models
 import "time"
 
 type Room struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	HostID    uint      `gorm:"not null;index" json:"host_id"`
-	Host      Host      `gorm:"foreignKey:HostID;constraint:OnDelete:CASCADE" json:"-"`
-	Code      string    `gorm:"size:6;index" json:"code"`
-	Mode      string    `gorm:"size:10;not null;default:'web'" json:"mode"`
-	Status    string    `gorm:"size:20;not null;default:'active'" json:"status"`
-	CreatedAt time.Time `json:"created_at"`
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	HostID       uint      `gorm:"not null;index" json:"host_id"`
+	Host         Host      `gorm:"foreignKey:HostID;constraint:OnDelete:CASCADE" json:"-"`
+	Code         string    `gorm:"size:6;index" json:"code"`
+	Mode         string    `gorm:"size:10;not null;default:'web'" json:"mode"`
+	Status       string    `gorm:"size:20;not null;default:'active'" json:"status"`
+	DisplayToken string    `gorm:"size:64;index" json:"-"`
+	ChatDisabled bool      `gorm:"not null;default:false" json:"chat_disabled"`
+	ChatQuiet    bool      `gorm:"not null;default:false" json:"chat_quiet_during_questions"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 const (
@@ -20,11 +23,26 @@ const (
 	RoomStatusClosed = "closed"
 )
 
+// RoomMember is a player in a room. Web players are identified by the device
+// ID from their signed player token; TokenVersion is bumped whenever that
+// token is rotated, which revokes the previous one.
 type RoomMember struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	RoomID       uint      `gorm:"not null;index" json:"room_id"`
+	Nickname     string    `gorm:"size:100;not null" json:"nickname"`
+	TelegramID   int64     `gorm:"default:0" json:"telegram_id,omitempty"`
+	WebToken     string    `gorm:"size:64" json:"-"`
+	TokenVersion int       `gorm:"not null;default:0" json:"-"`
+	JoinedAt     time.Time `json:"joined_at"`
+}
+
+// RoomBan keeps a kicked player from rejoining. Web players are matched by
+// their device ID, Telegram players by their Telegram ID.
+type RoomBan struct {
 	ID         uint      `gorm:"primaryKey" json:"id"`
 	RoomID     uint      `gorm:"not null;index" json:"room_id"`
-	Nickname   string    `gorm:"size:100;not null" json:"nickname"`
-	TelegramID int64     `gorm:"default:0" json:"telegram_id,omitempty"`
-	WebToken   string    `gorm:"size:64" json:"web_token,omitempty"`
-	JoinedAt   time.Time `json:"joined_at"`
+	WebToken   string    `gorm:"size:64;index" json:"-"`
+	TelegramID int64     `gorm:"default:0;index" json:"telegram_id,omitempty"`
+	Nickname   string    `gorm:"size:100" json:"nickname"`
+	CreatedAt  time.Time `json:"created_at"`
 }
@@ -11,6 +11,8 @@ type Session struct {
 	Code            string        `gorm:"size:6;index" json:"code"`
 	Status          string        `gorm:"size:20;not null;default:'waiting'" json:"status"`
 	CurrentQuestion int           `gorm:"not null;default:0" json:"current_question"`
+	AutoReveal      bool          `gorm:"not null;default:false" json:"auto_reveal"`
+	AutoRevealDelay int           `gorm:"not null;default:0" json:"auto_reveal_delay"`
 	Participants    []Participant `gorm:"foreignKey:SessionID" json:"participants,omitempty"`
 	CreatedAt       time.Time     `json:"created_at"`
 }
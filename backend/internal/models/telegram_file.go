@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// TelegramFile caches the file_id Telegram assigned to an uploaded question
+// media file. file_ids are only valid for the bot that uploaded them, so the
+// cache is keyed by bot as well.
+type TelegramFile struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ImageID   uint      `gorm:"not null;uniqueIndex:idx_telegram_file_image_bot" json:"image_id"`
+	BotKey    string    `gorm:"size:64;not null;uniqueIndex:idx_telegram_file_image_bot" json:"-"`
+	FileID    string    `gorm:"size:255;not null" json:"file_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
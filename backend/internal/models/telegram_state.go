@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// TelegramState stores the bot conversation state of one Telegram user, so a
+// restart doesn't drop host remotes or players mid-question. Data is the
+// JSON-encoded FSM state owned by the telegram package.
+type TelegramState struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	BotKey     string    `gorm:"size:64;not null;uniqueIndex:idx_telegram_state_bot_user" json:"-"`
+	TelegramID int64     `gorm:"not null;uniqueIndex:idx_telegram_state_bot_user" json:"telegram_id"`
+	Data       string    `gorm:"type:jsonb;not null" json:"-"`
+	UpdatedAt  time.Time `gorm:"index" json:"updated_at"`
+}
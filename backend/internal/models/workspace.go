@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// WorkspaceMember gives another account a role in a workspace. Every host
+// account is a workspace: quizzes, rooms and the bot belong to it through
+// HostID, and its own account is always its owner.
+type WorkspaceMember struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	WorkspaceID uint      `gorm:"not null;uniqueIndex:idx_workspace_member" json:"workspace_id"`
+	Workspace   Host      `gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE" json:"-"`
+	HostID      uint      `gorm:"not null;uniqueIndex:idx_workspace_member;index" json:"host_id"`
+	Host        Host      `gorm:"foreignKey:HostID;constraint:OnDelete:CASCADE" json:"-"`
+	Role        string    `gorm:"size:20;not null" json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+const (
+	RoleOwner     = "owner"
+	RoleEditor    = "editor"
+	RolePresenter = "presenter"
+	RoleViewer    = "viewer"
+)
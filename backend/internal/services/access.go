@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"quiz-game-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Permission is an action a role, or later an API key, may be granted in a
+// workspace.
+type Permission string
+
+const (
+	PermQuizzesRead   Permission = "quizzes:read"
+	PermQuizzesWrite  Permission = "quizzes:write"
+	PermRoomsControl  Permission = "rooms:control"
+	PermAnalyticsRead Permission = "analytics:read"
+	PermSettings      Permission = "settings:manage"
+	PermMembers       Permission = "members:manage"
+)
+
+var rolePermissions = map[string][]Permission{
+	models.RoleOwner:     {PermQuizzesRead, PermQuizzesWrite, PermRoomsControl, PermAnalyticsRead, PermSettings, PermMembers},
+	models.RoleEditor:    {PermQuizzesRead, PermQuizzesWrite, PermRoomsControl, PermAnalyticsRead},
+	models.RolePresenter: {PermQuizzesRead, PermRoomsControl, PermAnalyticsRead},
+	models.RoleViewer:    {PermQuizzesRead, PermAnalyticsRead},
+}
+
+var ErrNoWorkspaceAccess = errors.New("no access to this workspace")
+
+// ValidRole reports whether role is one of the workspace roles.
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// RoleCan reports whether a role grants a permission.
+func RoleCan(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// findInWorkspace loads a record by ID if it belongs to the workspace. It is
+// the one place content is matched to its owner; callers pick the error.
+func findInWorkspace(db *gorm.DB, dest interface{}, id, hostID uint) error {
+	return db.Where("id = ? AND host_id = ?", id, hostID).First(dest).Error
+}
+
+type WorkspaceInfo struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Role        string `json:"role"`
+	SSORequired bool   `json:"sso_required"`
+}
+
+type WorkspaceMemberInfo struct {
+	ID        uint      `json:"id"`
+	HostID    uint      `json:"host_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AccessService resolves what an account may do in a workspace and manages
+// workspace members.
+type AccessService struct {
+	db *gorm.DB
+}
+
+func NewAccessService(db *gorm.DB) *AccessService {
+	return &AccessService{db: db}
+}
+
+// Role returns the account's role in the workspace. An account owns its own
+// workspace.
+func (s *AccessService) Role(userID, workspaceID uint) (string, error) {
+	if userID == workspaceID {
+		return models.RoleOwner, nil
+	}
+	var m models.WorkspaceMember
+	if err := s.db.Where("workspace_id = ? AND host_id = ?", workspaceID, userID).First(&m).Error; err != nil {
+		return "", ErrNoWorkspaceAccess
+	}
+	return m.Role, nil
+}
+
+// SSORequired reports whether the workspace admits single sign-on logins only.
+func (s *AccessService) SSORequired(workspaceID uint) bool {
+	var host models.Host
+	s.db.Select("id", "sso_required").Limit(1).Find(&host, workspaceID)
+	return host.SSORequired
+}
+
+// Workspaces lists the workspaces the account can open, its own first.
+func (s *AccessService) Workspaces(userID uint) ([]WorkspaceInfo, error) {
+	var self models.Host
+	if err := s.db.First(&self, userID).Error; err != nil {
+		return nil, errors.New("host not found")
+	}
+	result := []WorkspaceInfo{{ID: self.ID, Name: self.Username, Role: models.RoleOwner, SSORequired: self.SSORequired}}
+
+	var rows []struct {
+		ID          uint
+		Username    string
+		Role        string
+		SSORequired bool
+	}
+	err := s.db.Table("workspace_members").
+		Select("hosts.id, hosts.username, workspace_members.role, hosts.sso_required").
+		Joins("JOIN hosts ON hosts.id = workspace_members.workspace_id").
+		Where("workspace_members.host_id = ?", userID).
+		Order("hosts.username ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		result = append(result, WorkspaceInfo{ID: r.ID, Name: r.Username, Role: r.Role, SSORequired: r.SSORequired})
+	}
+	return result, nil
+}
+
+func (s *AccessService) Members(workspaceID uint) ([]WorkspaceMemberInfo, error) {
+	var members []models.WorkspaceMember
+	if err := s.db.Preload("Host").Where("workspace_id = ?", workspaceID).Order("created_at ASC").Find(&members).Error; err != nil {
+		return nil, err
+	}
+	result := make([]WorkspaceMemberInfo, 0, len(members))
+	for _, m := range members {
+		result = append(result, memberInfo(m))
+	}
+	return result, nil
+}
+
+// AddMember gives an existing account a role in the workspace.
+func (s *AccessService) AddMember(workspaceID uint, username, role string) (*WorkspaceMemberInfo, error) {
+	if !ValidRole(role) {
+		return nil, errors.New("invalid role")
+	}
+	var host models.Host
+	if err := s.db.Where("username = ?", strings.TrimSpace(username)).First(&host).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+	if host.ID == workspaceID {
+		return nil, errors.New("the workspace owner is already a member")
+	}
+
+	var count int64
+	s.db.Model(&models.WorkspaceMember{}).Where("workspace_id = ? AND host_id = ?", workspaceID, host.ID).Count(&count)
+	if count > 0 {
+		return nil, errors.New("user is already a member")
+	}
+
+	m := models.WorkspaceMember{WorkspaceID: workspaceID, HostID: host.ID, Role: role}
+	if err := s.db.Create(&m).Error; err != nil {
+		return nil, err
+	}
+	m.Host = host
+	info := memberInfo(m)
+	return &info, nil
+}
+
+func (s *AccessService) UpdateMemberRole(workspaceID, memberID uint, role string) (*WorkspaceMemberInfo, error) {
+	if !ValidRole(role) {
+		return nil, errors.New("invalid role")
+	}
+	var m models.WorkspaceMember
+	if err := s.db.Preload("Host").Where("id = ? AND workspace_id = ?", memberID, workspaceID).First(&m).Error; err != nil {
+		return nil, errors.New("member not found")
+	}
+	m.Role = role
+	if err := s.db.Model(&m).Update("role", role).Error; err != nil {
+		return nil, err
+	}
+	info := memberInfo(m)
+	return &info, nil
+}
+
+// RemoveMember takes a member out of the workspace. Members may also remove
+// themselves; userID is the account asking.
+func (s *AccessService) RemoveMember(workspaceID, memberID, userID uint, canManage bool) error {
+	var m models.WorkspaceMember
+	if err := s.db.Where("id = ? AND workspace_id = ?", memberID, workspaceID).First(&m).Error; err != nil {
+		return errors.New("member not found")
+	}
+	if !canManage && m.HostID != userID {
+		return ErrNoWorkspaceAccess
+	}
+	return s.db.Delete(&m).Error
+}
+
+func memberInfo(m models.WorkspaceMember) WorkspaceMemberInfo {
+	return WorkspaceMemberInfo{
+		ID:        m.ID,
+		HostID:    m.HostID,
+		Username:  m.Host.Username,
+		Role:      m.Role,
+		CreatedAt: m.CreatedAt,
+	}
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"quiz-game-backend/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWrongPassword      = errors.New("current password is incorrect")
+	ErrInvalidResetToken  = errors.New("invalid or expired reset token")
+	ErrUsernameNotConfirm = errors.New("type your username to confirm")
+	ErrReauthRequired     = errors.New("sign in again with single sign-on to confirm")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUsernameTaken      = errors.New("username already taken")
+	ErrInvalidUsername    = errors.New("username must be 3-100 characters")
+)
+
+// uploadsPrefix starts the URLs of files stored by the upload endpoint.
+const uploadsPrefix = "/uploads/"
+
+// ssoReauthWindow is how recent a single sign-on login has to be to stand in
+// for the password of an account that has none.
+const ssoReauthWindow = 10 * time.Minute
+
+type AccountService struct {
+	db         *gorm.DB
+	auth       *AuthService
+	tokens     *BotTokenService
+	notifier   Notifier
+	resetTTL   time.Duration
+	appURL     string
+	uploadsDir string
+
+	deleteMu       sync.Mutex
+	deleteHandlers []func(hostID uint)
+}
+
+// NewAccountService manages host accounts. Reset links point to the web app
+// at appURL and are valid for resetTTL; uploadsDir is where uploaded question
+// media is stored, for removal with the account.
+func NewAccountService(db *gorm.DB, auth *AuthService, tokens *BotTokenService, notifier Notifier, resetTTL time.Duration, appURL, uploadsDir string) *AccountService {
+	return &AccountService{
+		db:         db,
+		auth:       auth,
+		tokens:     tokens,
+		notifier:   notifier,
+		resetTTL:   resetTTL,
+		appURL:     strings.TrimRight(appURL, "/"),
+		uploadsDir: uploadsDir,
+	}
+}
+
+// OnDelete registers a callback invoked after an account was deleted, so the
+// bot manager can stop the account's bot.
+func (s *AccountService) OnDelete(fn func(hostID uint)) {
+	s.deleteMu.Lock()
+	defer s.deleteMu.Unlock()
+	s.deleteHandlers = append(s.deleteHandlers, fn)
+}
+
+// confirmIdentity checks the current password before a sensitive change.
+// Accounts created by single sign-on have no password; for them the request
+// has to come from a login session started through single sign-on within
+// ssoReauthWindow.
+func (s *AccountService) confirmIdentity(host *models.Host, sessionID uint, password string) error {
+	if host.PasswordHash != "" {
+		if bcrypt.CompareHashAndPassword([]byte(host.PasswordHash), []byte(password)) != nil {
+			return ErrWrongPassword
+		}
+		return nil
+	}
+	var recent int64
+	s.db.Model(&models.AuthSession{}).
+		Where("id = ? AND host_id = ? AND method = ? AND revoked_at IS NULL AND created_at > ?",
+			sessionID, host.ID, models.AuthMethodSSO, time.Now().Add(-ssoReauthWindow)).
+		Count(&recent)
+	if recent == 0 {
+		return ErrReauthRequired
+	}
+	return nil
+}
+
+func (s *AccountService) setPassword(hostID uint, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return s.db.Model(&models.Host{}).Where("id = ?", hostID).Update("password_hash", string(hash)).Error
+}
+
+// ChangePassword sets a new password after checking the current one. Other
+// devices are signed out; sessionID is the login that stays.
+func (s *AccountService) ChangePassword(hostID, sessionID uint, current, password string) error {
+	var host models.Host
+	if err := s.db.First(&host, hostID).Error; err != nil {
+		return errors.New("host not found")
+	}
+	if err := s.confirmIdentity(&host, sessionID, current); err != nil {
+		return err
+	}
+	if err := s.setPassword(hostID, password); err != nil {
+		return err
+	}
+	return s.auth.RevokeOtherSessions(hostID, sessionID)
+}
+
+// ChangeUsername renames the account and returns the name as saved, without
+// surrounding spaces. The name is what the host logs in with.
+func (s *AccountService) ChangeUsername(hostID uint, username string) (string, error) {
+	username = strings.TrimSpace(username)
+	if n := len([]rune(username)); n < 3 || n > 100 {
+		return "", ErrInvalidUsername
+	}
+	var taken int64
+	if err := s.db.Model(&models.Host{}).Where("username = ? AND id <> ?", username, hostID).Count(&taken).Error; err != nil {
+		return "", err
+	}
+	if taken > 0 {
+		return "", ErrUsernameTaken
+	}
+	res := s.db.Model(&models.Host{}).Where("id = ?", hostID).Update("username", username)
+	if res.Error != nil {
+		return "", res.Error
+	}
+	if res.RowsAffected == 0 {
+		return "", ErrUserNotFound
+	}
+	return username, nil
+}
+
+// IssueReset creates a password reset token for an account and sends the
+// link through the notifier. A previous token of the account stops working.
+func (s *AccountService) IssueReset(username string) (time.Time, error) {
+	var host models.Host
+	if err := s.db.Where("username = ?", username).First(&host).Error; err != nil {
+		return time.Time{}, ErrUserNotFound
+	}
+
+	token, err := randomURLToken(32)
+	if err != nil {
+		return time.Time{}, err
+	}
+	reset := models.PasswordReset{
+		HostID:    host.ID,
+		TokenHash: sha256Hex(token),
+		ExpiresAt: time.Now().Add(s.resetTTL),
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("host_id = ?", host.ID).Delete(&models.PasswordReset{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&reset).Error
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	link := s.appURL + "/reset-password?token=" + token
+	body := fmt.Sprintf("A password reset was requested for %s. Open %s to set a new password. The link works once and expires at %s.",
+		host.Username, link, reset.ExpiresAt.UTC().Format(time.RFC1123))
+	if err := s.notifier.Notify(Notification{HostID: host.ID, Username: host.Username, Subject: "Password reset", Body: body}); err != nil {
+		s.db.Delete(&reset)
+		return time.Time{}, fmt.Errorf("failed to deliver reset link: %w", err)
+	}
+	return reset.ExpiresAt, nil
+}
+
+// ResetPassword redeems a reset token and signs the account out everywhere.
+func (s *AccountService) ResetPassword(token, password string) error {
+	var reset models.PasswordReset
+	if err := s.db.Where("token_hash = ? AND expires_at > ?", sha256Hex(token), time.Now()).First(&reset).Error; err != nil {
+		return ErrInvalidResetToken
+	}
+	// Deleting first makes a token redeemed twice at once work only once.
+	res := s.db.Where("id = ?", reset.ID).Delete(&models.PasswordReset{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrInvalidResetToken
+	}
+	if err := s.setPassword(reset.HostID, password); err != nil {
+		return err
+	}
+	_, err := s.auth.RevokeAllSessions(reset.HostID)
+	return err
+}
+
+// DeleteAccount removes an account with its workspace: quizzes, rooms,
+// sessions, the bot, team memberships and uploaded media. The username has
+// to be typed to confirm, along with the password or a fresh single sign-on
+// login from sessionID. Audit entries are kept.
+func (s *AccountService) DeleteAccount(hostID, sessionID uint, username, password string) error {
+	var host models.Host
+	if err := s.db.First(&host, hostID).Error; err != nil {
+		return errors.New("host not found")
+	}
+	if username != host.Username {
+		return ErrUsernameNotConfirm
+	}
+	if err := s.confirmIdentity(&host, sessionID, password); err != nil {
+		return err
+	}
+
+	quizIDs := s.db.Model(&models.Quiz{}).Select("id").Where("host_id = ?", hostID)
+	questionIDs := s.db.Model(&models.Question{}).Select("id").Where("quiz_id IN (?)", quizIDs)
+	imageIDs := s.db.Model(&models.QuestionImage{}).Select("id").Where("question_id IN (?)", questionIDs)
+	sessionIDs := s.db.Model(&models.Session{}).Select("id").Where("host_id = ?", hostID)
+	roomIDs := s.db.Model(&models.Room{}).Select("id").Where("host_id = ?", hostID)
+
+	var media []string
+	s.db.Model(&models.QuestionImage{}).Where("question_id IN (?)", questionIDs).Pluck("url", &media)
+
+	// Conversation states are kept per bot, not per host.
+	botToken, err := s.tokens.Get(hostID)
+	if err != nil {
+		log.Printf("account deletion: bot token of host %d: %v", hostID, err)
+	}
+
+	// Children go before their parents: the ID lists above are subqueries
+	// that are evaluated by each delete.
+	type deleteStep struct {
+		model interface{}
+		query string
+		arg   interface{}
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		steps := []deleteStep{
+			{&models.Answer{}, "session_id IN (?)", sessionIDs},
+			{&models.Participant{}, "session_id IN (?)", sessionIDs},
+			{&models.Session{}, "host_id = ?", hostID},
+			{&models.ChatMessage{}, "room_id IN (?)", roomIDs},
+			{&models.RoomMute{}, "room_id IN (?)", roomIDs},
+			{&models.RoomBan{}, "room_id IN (?)", roomIDs},
+			{&models.RoomMember{}, "room_id IN (?)", roomIDs},
+			{&models.Room{}, "host_id = ?", hostID},
+			{&models.TelegramFile{}, "image_id IN (?)", imageIDs},
+			{&models.QuestionImage{}, "question_id IN (?)", questionIDs},
+			{&models.Option{}, "question_id IN (?)", questionIDs},
+			{&models.Question{}, "quiz_id IN (?)", quizIDs},
+			{&models.Category{}, "quiz_id IN (?)", quizIDs},
+			{&models.Quiz{}, "host_id = ?", hostID},
+			{&models.TelegramUser{}, "host_id = ?", hostID},
+			{&models.RemoteLoginAttempt{}, "host_id = ?", hostID},
+			{&models.RemotePairingCode{}, "host_id = ?", hostID},
+			{&models.AuthSession{}, "host_id = ?", hostID},
+			{&models.OIDCLoginState{}, "link_host_id = ?", hostID},
+		}
+		if botToken != "" {
+			steps = append(steps, deleteStep{&models.TelegramState{}, "bot_key = ?", BotKey(botToken)})
+		}
+		for _, step := range steps {
+			if err := tx.Where(step.query, step.arg).Delete(step.model).Error; err != nil {
+				return err
+			}
+		}
+		// Identities, team memberships, API keys, remote access and reset
+		// tokens go with the host row.
+		return tx.Delete(&models.Host{}, hostID).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.removeMedia(media)
+
+	s.deleteMu.Lock()
+	handlers := append([]func(uint){}, s.deleteHandlers...)
+	s.deleteMu.Unlock()
+	for _, fn := range handlers {
+		fn(hostID)
+	}
+	return nil
+}
+
+// removeMedia deletes uploaded files that no remaining question uses.
+func (s *AccountService) removeMedia(urls []string) {
+	for _, url := range urls {
+		if !strings.HasPrefix(url, uploadsPrefix) {
+			continue
+		}
+		var used int64
+		s.db.Model(&models.QuestionImage{}).Where("url = ?", url).Count(&used)
+		if used > 0 {
+			continue
+		}
+		name := filepath.Base(strings.TrimPrefix(url, uploadsPrefix))
+		if err := os.Remove(filepath.Join(s.uploadsDir, name)); err != nil && !os.IsNotExist(err) {
+			log.Printf("account deletion: removing %s: %v", name, err)
+		}
+	}
+}
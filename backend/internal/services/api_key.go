@@ -0,0 +1,193 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"quiz-game-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyPrefix starts every API key, so middleware can tell keys from JWTs.
+const APIKeyPrefix = "qk_"
+
+// apiKeyTouchInterval limits how often key usage is written to the database.
+const apiKeyTouchInterval = time.Minute
+
+// APIKeyScopes are the permissions an API key can be given. Settings and
+// members stay with logged-in owners.
+var APIKeyScopes = []Permission{PermQuizzesRead, PermQuizzesWrite, PermRoomsControl, PermAnalyticsRead}
+
+type APIKeyInfo struct {
+	ID         uint         `json:"id"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	Scopes     []Permission `json:"scopes"`
+	LastUsedAt *time.Time   `json:"last_used_at"`
+	LastUsedIP string       `json:"last_used_ip,omitempty"`
+	ExpiresAt  *time.Time   `json:"expires_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// APIKeyAuth is the workspace and scopes an API key grants.
+type APIKeyAuth struct {
+	KeyID  uint
+	HostID uint
+	Scopes []Permission
+}
+
+// Can reports whether the key was given perm.
+func (a *APIKeyAuth) Can(perm Permission) bool {
+	for _, p := range a.Scopes {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+type APIKeyService struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyService(db *gorm.DB) *APIKeyService {
+	return &APIKeyService{db: db}
+}
+
+// Create issues a key for the workspace. The plain key is returned only
+// here. expiresIn of zero means the key doesn't expire.
+func (s *APIKeyService) Create(hostID, createdBy uint, name string, scopes []string, expiresIn time.Duration) (string, *APIKeyInfo, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return "", nil, errors.New("key name must be 1 to 100 characters")
+	}
+	perms, err := parseScopes(scopes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+	plain := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	key := models.APIKey{
+		HostID:    hostID,
+		CreatedBy: createdBy,
+		Name:      name,
+		Prefix:    plain[:len(APIKeyPrefix)+6],
+		KeyHash:   hashAPIKey(plain),
+		Scopes:    joinScopes(perms),
+	}
+	if expiresIn > 0 {
+		exp := time.Now().Add(expiresIn)
+		key.ExpiresAt = &exp
+	}
+	if err := s.db.Create(&key).Error; err != nil {
+		return "", nil, err
+	}
+	info := apiKeyInfo(key)
+	return plain, &info, nil
+}
+
+func (s *APIKeyService) List(hostID uint) ([]APIKeyInfo, error) {
+	var keys []models.APIKey
+	if err := s.db.Where("host_id = ?", hostID).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	result := make([]APIKeyInfo, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, apiKeyInfo(k))
+	}
+	return result, nil
+}
+
+func (s *APIKeyService) Delete(keyID, hostID uint) error {
+	res := s.db.Where("id = ? AND host_id = ?", keyID, hostID).Delete(&models.APIKey{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("API key not found")
+	}
+	return nil
+}
+
+// Authenticate checks a plain key and records its use.
+func (s *APIKeyService) Authenticate(plain, ip string) (*APIKeyAuth, error) {
+	var key models.APIKey
+	if err := s.db.Where("key_hash = ?", hashAPIKey(plain)).First(&key).Error; err != nil {
+		return nil, errors.New("invalid API key")
+	}
+	now := time.Now()
+	if key.ExpiresAt != nil && now.After(*key.ExpiresAt) {
+		return nil, errors.New("API key expired")
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval || key.LastUsedIP != ip {
+		s.db.Model(&key).Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": ip})
+	}
+
+	perms, _ := parseScopes(strings.Split(key.Scopes, ","))
+	return &APIKeyAuth{KeyID: key.ID, HostID: key.HostID, Scopes: perms}, nil
+}
+
+func parseScopes(scopes []string) ([]Permission, error) {
+	var perms []Permission
+	seen := make(map[Permission]bool)
+	for _, raw := range scopes {
+		p := Permission(strings.TrimSpace(raw))
+		valid := false
+		for _, allowed := range APIKeyScopes {
+			if p == allowed {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, errors.New("invalid scope: " + string(p))
+		}
+		if !seen[p] {
+			seen[p] = true
+			perms = append(perms, p)
+		}
+	}
+	if len(perms) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	return perms, nil
+}
+
+func joinScopes(perms []Permission) string {
+	parts := make([]string, len(perms))
+	for i, p := range perms {
+		parts[i] = string(p)
+	}
+	return strings.Join(parts, ",")
+}
+
+func hashAPIKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+func apiKeyInfo(k models.APIKey) APIKeyInfo {
+	perms, _ := parseScopes(strings.Split(k.Scopes, ","))
+	return APIKeyInfo{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     perms,
+		LastUsedAt: k.LastUsedAt,
+		LastUsedIP: k.LastUsedIP,
+		ExpiresAt:  k.ExpiresAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"quiz-game-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Audited actions. They are grouped by the part before the dot, which the
+// query endpoint accepts as a filter on its own.
+const (
+	AuditQuizCreate     = "quiz.create"
+	AuditQuizUpdate     = "quiz.update"
+	AuditQuizDelete     = "quiz.delete"
+	AuditQuizImport     = "quiz.import"
+	AuditRoomOpen       = "room.open"
+	AuditRoomClose      = "room.close"
+	AuditSessionCreate  = "session.create"
+	AuditSessionStart   = "session.start"
+	AuditSessionReveal  = "session.reveal"
+	AuditSessionNext    = "session.next"
+	AuditSessionFinish  = "session.finish"
+	AuditSettingsUpdate = "settings.update"
+)
+
+// What an audited action was done to.
+const (
+	AuditTargetQuiz     = "quiz"
+	AuditTargetRoom     = "room"
+	AuditTargetSession  = "session"
+	AuditTargetSettings = "settings"
+)
+
+const (
+	auditDefaultLimit = 50
+	auditMaxLimit     = 200
+)
+
+// AuditActor is who performed an action. Name is looked up from the ID when
+// left empty.
+type AuditActor struct {
+	Type string
+	ID   string
+	Name string
+	IP   string
+}
+
+func UserActor(userID uint, ip string) AuditActor {
+	return AuditActor{Type: models.AuditActorUser, ID: strconv.FormatUint(uint64(userID), 10), IP: ip}
+}
+
+func APIKeyActor(keyID uint, ip string) AuditActor {
+	return AuditActor{Type: models.AuditActorAPIKey, ID: strconv.FormatUint(uint64(keyID), 10), IP: ip}
+}
+
+// TelegramActor is a Telegram account using the host remote of the bot.
+func TelegramActor(telegramID int64) AuditActor {
+	return AuditActor{Type: models.AuditActorTelegram, ID: strconv.FormatInt(telegramID, 10)}
+}
+
+// SystemActor is the server acting on its own, such as auto-reveal.
+func SystemActor(name string) AuditActor {
+	return AuditActor{Type: models.AuditActorSystem, ID: name, Name: name}
+}
+
+// AuditEntryInfo is an audit entry with its summaries as JSON.
+type AuditEntryInfo struct {
+	models.AuditEntry
+	Before json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After  json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+}
+
+// AuditFilter narrows an audit query. Action is an exact action or a group
+// such as "session"; BeforeID pages back from an earlier result.
+type AuditFilter struct {
+	Action     string
+	ActorType  string
+	ActorID    string
+	TargetType string
+	TargetID   uint
+	From       *time.Time
+	To         *time.Time
+	BeforeID   uint
+	Limit      int
+}
+
+// QuizAudit, RoomAudit and SessionAudit are what the audit log keeps of a
+// target before and after an action.
+type QuizAudit struct {
+	Title     string `json:"title"`
+	Mode      string `json:"mode"`
+	Questions int64  `json:"questions"`
+}
+
+type RoomAudit struct {
+	Code   string `json:"code"`
+	Mode   string `json:"mode"`
+	Status string `json:"status"`
+}
+
+type SessionAudit struct {
+	RoomID          uint   `json:"room_id,omitempty"`
+	QuizID          uint   `json:"quiz_id"`
+	Status          string `json:"status"`
+	CurrentQuestion int    `json:"current_question"`
+}
+
+type AuditService struct {
+	db *gorm.DB
+}
+
+func NewAuditService(db *gorm.DB) *AuditService {
+	return &AuditService{db: db}
+}
+
+// Record appends an entry to the audit log of a workspace. before and after
+// are stored as JSON; nil leaves them out. A failure to write is logged and
+// doesn't undo the action.
+func (s *AuditService) Record(workspaceID uint, actor AuditActor, action, targetType string, targetID uint, before, after interface{}) {
+	if actor.Name == "" {
+		actor.Name = s.actorName(workspaceID, actor)
+	}
+	entry := models.AuditEntry{
+		WorkspaceID: workspaceID,
+		ActorType:   actor.Type,
+		ActorID:     actor.ID,
+		ActorName:   actor.Name,
+		Action:      action,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Before:      auditJSON(before),
+		After:       auditJSON(after),
+		IP:          actor.IP,
+	}
+	if err := s.db.Create(&entry).Error; err != nil {
+		log.Printf("audit %s in workspace %d: %v", action, workspaceID, err)
+	}
+}
+
+func auditJSON(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" {
+		return ""
+	}
+	return string(b)
+}
+
+// actorName keeps a readable name with the entry, so it stays meaningful
+// after the account, key or remote login is gone.
+func (s *AuditService) actorName(workspaceID uint, actor AuditActor) string {
+	id, err := strconv.ParseInt(actor.ID, 10, 64)
+	if err != nil {
+		return ""
+	}
+	var names []string
+	switch actor.Type {
+	case models.AuditActorUser:
+		s.db.Model(&models.Host{}).Where("id = ?", id).Pluck("username", &names)
+	case models.AuditActorAPIKey:
+		s.db.Model(&models.APIKey{}).Where("id = ? AND host_id = ?", id, workspaceID).Pluck("name", &names)
+	case models.AuditActorTelegram:
+		s.db.Model(&models.RemoteAuthorization{}).Where("telegram_id = ? AND host_id = ?", id, workspaceID).Pluck("name", &names)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+// SessionChange runs a change of a session and records it with the session
+// before and after. Moving on from the lobby is recorded as the start.
+func (s *AuditService) SessionChange(workspaceID uint, actor AuditActor, action string, sessionID uint, change func() (*SessionState, error)) (*SessionState, error) {
+	before := s.SessionSummary(sessionID)
+	state, err := change()
+	if err != nil {
+		return state, err
+	}
+	if action == AuditSessionNext && before != nil && before.Status == models.SessionStatusWaiting {
+		action = AuditSessionStart
+	}
+	s.Record(workspaceID, actor, action, AuditTargetSession, sessionID, before, s.SessionSummary(sessionID))
+	return state, nil
+}
+
+// QuizSummary describes a quiz for the audit log, or returns nil if it
+// doesn't exist.
+func (s *AuditService) QuizSummary(quizID uint) *QuizAudit {
+	var quiz models.Quiz
+	if err := s.db.First(&quiz, quizID).Error; err != nil {
+		return nil
+	}
+	summary := &QuizAudit{Title: quiz.Title, Mode: quiz.Mode}
+	s.db.Model(&models.Question{}).Where("quiz_id = ?", quizID).Count(&summary.Questions)
+	return summary
+}
+
+// RoomSummary describes a room for the audit log, or returns nil if it
+// doesn't exist.
+func (s *AuditService) RoomSummary(roomID uint) *RoomAudit {
+	var room models.Room
+	if err := s.db.First(&room, roomID).Error; err != nil {
+		return nil
+	}
+	return &RoomAudit{Code: room.Code, Mode: room.Mode, Status: room.Status}
+}
+
+// SessionSummary describes a session for the audit log, or returns nil if it
+// doesn't exist.
+func (s *AuditService) SessionSummary(sessionID uint) *SessionAudit {
+	var session models.Session
+	if err := s.db.First(&session, sessionID).Error; err != nil {
+		return nil
+	}
+	return &SessionAudit{
+		RoomID:          session.RoomID,
+		QuizID:          session.QuizID,
+		Status:          session.Status,
+		CurrentQuestion: session.CurrentQuestion,
+	}
+}
+
+// List returns audit entries of a workspace matching the filter, newest first.
+func (s *AuditService) List(workspaceID uint, f AuditFilter) ([]AuditEntryInfo, error) {
+	q := s.db.Where("workspace_id = ?", workspaceID)
+	if action := strings.TrimSpace(f.Action); action != "" {
+		if strings.Contains(action, ".") {
+			q = q.Where("action = ?", action)
+		} else {
+			q = q.Where("action LIKE ?", action+".%")
+		}
+	}
+	if f.ActorType != "" {
+		q = q.Where("actor_type = ?", f.ActorType)
+	}
+	if f.ActorID != "" {
+		q = q.Where("actor_id = ?", f.ActorID)
+	}
+	if f.TargetType != "" {
+		q = q.Where("target_type = ?", f.TargetType)
+	}
+	if f.TargetID > 0 {
+		q = q.Where("target_id = ?", f.TargetID)
+	}
+	if f.From != nil {
+		q = q.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("created_at < ?", *f.To)
+	}
+	if f.BeforeID > 0 {
+		q = q.Where("id < ?", f.BeforeID)
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = auditDefaultLimit
+	}
+	if limit > auditMaxLimit {
+		limit = auditMaxLimit
+	}
+
+	var entries []models.AuditEntry
+	if err := q.Order("id DESC").Limit(limit).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	result := make([]AuditEntryInfo, 0, len(entries))
+	for _, e := range entries {
+		info := AuditEntryInfo{AuditEntry: e}
+		if e.Before != "" {
+			info.Before = json.RawMessage(e.Before)
+		}
+		if e.After != "" {
+			info.After = json.RawMessage(e.After)
+		}
+		result = append(result, info)
+	}
+	return result, nil
+}
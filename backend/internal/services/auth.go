@@ -1,6 +1,10 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -11,24 +15,61 @@ import (
 	"gorm.io/gorm"
 )
 
+// refreshReuseGrace is how long the previous refresh token of a session is
+// answered with a plain error instead of revoking the session, so two tabs
+// refreshing at the same moment don't log the host out.
+const refreshReuseGrace = 30 * time.Second
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenRotated = errors.New("refresh token already rotated")
+	ErrSSORequired         = errors.New("this account must sign in with single sign-on")
+)
+
 type AuthService struct {
-	db        *gorm.DB
-	jwtSecret []byte
+	db         *gorm.DB
+	jwtSecret  []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewAuthService creates the host auth service. Access tokens live for
+// accessTTL; refresh tokens, and with them login sessions, for refreshTTL
+// since the last refresh.
+func NewAuthService(db *gorm.DB, jwtSecret string, accessTTL, refreshTTL time.Duration) *AuthService {
+	return &AuthService{db: db, jwtSecret: []byte(jwtSecret), accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+// TokenPair is a short-lived access token and the refresh token that renews it.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int // access token lifetime, seconds
+}
+
+// ClientInfo describes the device a login comes from.
+type ClientInfo struct {
+	UserAgent string
+	IP        string
 }
 
-func NewAuthService(db *gorm.DB, jwtSecret string) *AuthService {
-	return &AuthService{db: db, jwtSecret: []byte(jwtSecret)}
+// TokenInfo is what a valid access token identifies.
+type TokenInfo struct {
+	HostID    uint
+	SessionID uint
+	// Method is how the login session was started, see models.AuthMethodSSO.
+	Method string
 }
 
-func (s *AuthService) Register(username, password string) (string, error) {
+func (s *AuthService) Register(username, password string, client ClientInfo) (*TokenPair, error) {
 	var existing models.Host
 	if err := s.db.Where("username = ?", username).First(&existing).Error; err == nil {
-		return "", errors.New("username already taken")
+		return nil, ErrUsernameTaken
 	}
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	host := models.Host{
@@ -36,36 +77,215 @@ func (s *AuthService) Register(username, password string) (string, error) {
 		PasswordHash: string(hash),
 	}
 	if err := s.db.Create(&host).Error; err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return s.GenerateToken(host.ID)
+	return s.StartSession(host.ID, client)
 }
 
-func (s *AuthService) Login(username, password string) (string, error) {
+func (s *AuthService) Login(username, password string, client ClientInfo) (*TokenPair, error) {
 	var host models.Host
 	if err := s.db.Where("username = ?", username).First(&host).Error; err != nil {
-		return "", errors.New("invalid credentials")
+		return nil, errors.New("invalid credentials")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(host.PasswordHash), []byte(password)); err != nil {
-		return "", errors.New("invalid credentials")
+		return nil, errors.New("invalid credentials")
+	}
+	if host.SSORequired {
+		return nil, ErrSSORequired
+	}
+
+	return s.StartSession(host.ID, client)
+}
+
+// StartSession opens a password login session for the host and issues its
+// first token pair.
+func (s *AuthService) StartSession(hostID uint, client ClientInfo) (*TokenPair, error) {
+	return s.startSession(hostID, models.AuthMethodPassword, client)
+}
+
+// StartSSOSession opens a login session for a host who signed in through
+// the identity provider.
+func (s *AuthService) StartSSOSession(hostID uint, client ClientInfo) (*TokenPair, error) {
+	return s.startSession(hostID, models.AuthMethodSSO, client)
+}
+
+// startSession opens a login session. Dead sessions of the host are cleaned
+// up on the way.
+func (s *AuthService) startSession(hostID uint, method string, client ClientInfo) (*TokenPair, error) {
+	s.db.Where("host_id = ? AND (expires_at < ? OR revoked_at IS NOT NULL)", hostID, time.Now()).
+		Delete(&models.AuthSession{})
+
+	refresh, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	session := models.AuthSession{
+		HostID:      hostID,
+		RefreshHash: hash,
+		RotatedAt:   now,
+		Method:      method,
+		UserAgent:   truncate(client.UserAgent, 255),
+		IP:          truncate(client.IP, 64),
+		LastUsedAt:  now,
+		ExpiresAt:   now.Add(s.refreshTTL),
+	}
+	if err := s.db.Create(&session).Error; err != nil {
+		return nil, err
+	}
+	return s.issue(&session, refresh)
+}
+
+// Refresh exchanges a refresh token for a new pair. The old refresh token
+// stops working; presenting it again after the grace period is treated as
+// theft and ends the session.
+func (s *AuthService) Refresh(refreshToken string, client ClientInfo) (*TokenPair, error) {
+	hash := hashRefreshToken(refreshToken)
+	now := time.Now()
+
+	var session models.AuthSession
+	if err := s.db.Where("refresh_hash = ?", hash).First(&session).Error; err != nil {
+		if err := s.db.Where("prev_refresh_hash = ?", hash).First(&session).Error; err != nil {
+			return nil, ErrInvalidRefreshToken
+		}
+		if session.RevokedAt == nil && now.Sub(session.RotatedAt) < refreshReuseGrace {
+			return nil, ErrRefreshTokenRotated
+		}
+		s.db.Model(&session).Update("revoked_at", now)
+		return nil, ErrInvalidRefreshToken
+	}
+	if session.RevokedAt != nil || now.After(session.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	refresh, newHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
 	}
+	updates := map[string]interface{}{
+		"refresh_hash":      newHash,
+		"prev_refresh_hash": hash,
+		"rotated_at":        now,
+		"last_used_at":      now,
+		"expires_at":        now.Add(s.refreshTTL),
+	}
+	if client.UserAgent != "" {
+		updates["user_agent"] = truncate(client.UserAgent, 255)
+	}
+	if client.IP != "" {
+		updates["ip"] = truncate(client.IP, 64)
+	}
+	// The hash condition makes a concurrent refresh with the same token lose.
+	res := s.db.Model(&models.AuthSession{}).
+		Where("id = ? AND refresh_hash = ?", session.ID, hash).
+		Updates(updates)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrRefreshTokenRotated
+	}
+	return s.issue(&session, refresh)
+}
+
+// Logout ends the session the refresh token belongs to.
+func (s *AuthService) Logout(refreshToken string) error {
+	hash := hashRefreshToken(refreshToken)
+	return s.db.Model(&models.AuthSession{}).
+		Where("(refresh_hash = ? OR prev_refresh_hash = ?) AND revoked_at IS NULL", hash, hash).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeSession ends one of the host's sessions.
+func (s *AuthService) RevokeSession(sessionID, hostID uint) error {
+	res := s.db.Model(&models.AuthSession{}).
+		Where("id = ? AND host_id = ? AND revoked_at IS NULL", sessionID, hostID).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("login session not found")
+	}
+	return nil
+}
+
+// RevokeAllSessions signs the host out on every device and returns how many
+// sessions were ended.
+func (s *AuthService) RevokeAllSessions(hostID uint) (int64, error) {
+	res := s.db.Model(&models.AuthSession{}).
+		Where("host_id = ? AND revoked_at IS NULL", hostID).
+		Update("revoked_at", time.Now())
+	return res.RowsAffected, res.Error
+}
+
+// RevokeOtherSessions signs the host out everywhere except the session
+// keepID.
+func (s *AuthService) RevokeOtherSessions(hostID, keepID uint) error {
+	return s.db.Model(&models.AuthSession{}).
+		Where("host_id = ? AND id <> ? AND revoked_at IS NULL", hostID, keepID).
+		Update("revoked_at", time.Now()).Error
+}
 
-	return s.GenerateToken(host.ID)
+// ListSessions returns the host's live login sessions, most recently used first.
+func (s *AuthService) ListSessions(hostID uint) ([]models.AuthSession, error) {
+	var sessions []models.AuthSession
+	err := s.db.Where("host_id = ? AND revoked_at IS NULL AND expires_at > ?", hostID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
 }
 
-func (s *AuthService) GenerateToken(hostID uint) (string, error) {
+func (s *AuthService) issue(session *models.AuthSession, refresh string) (*TokenPair, error) {
+	access, err := s.GenerateToken(session.HostID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    int(s.accessTTL / time.Second),
+	}, nil
+}
+
+// GenerateToken signs an access token for a login session.
+func (s *AuthService) GenerateToken(hostID, sessionID uint) (string, error) {
 	claims := jwt.MapClaims{
 		"host_id": hostID,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
+		"sid":     sessionID,
+		"exp":     time.Now().Add(s.accessTTL).Unix(),
 		"iat":     time.Now().Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(s.jwtSecret)
 }
 
-func (s *AuthService) ValidateToken(tokenString string) (uint, error) {
+func newRefreshToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+// ValidateToken checks an access token and that its login session is still
+// active.
+func (s *AuthService) ValidateToken(tokenString string) (*TokenInfo, error) {
 	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
@@ -73,18 +293,31 @@ func (s *AuthService) ValidateToken(tokenString string) (uint, error) {
 		return s.jwtSecret, nil
 	})
 	if err != nil || !token.Valid {
-		return 0, errors.New("invalid token")
+		return nil, errors.New("invalid token")
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return 0, errors.New("invalid claims")
+		return nil, errors.New("invalid claims")
 	}
 
 	hostIDFloat, ok := claims["host_id"].(float64)
 	if !ok {
-		return 0, errors.New("invalid host_id in token")
+		return nil, errors.New("invalid host_id in token")
+	}
+	sidFloat, ok := claims["sid"].(float64)
+	if !ok {
+		return nil, errors.New("invalid sid in token")
+	}
+	hostID, sessionID := uint(hostIDFloat), uint(sidFloat)
+
+	var session models.AuthSession
+	s.db.Select("id", "method").
+		Where("id = ? AND host_id = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, hostID, time.Now()).
+		Limit(1).Find(&session)
+	if session.ID == 0 {
+		return nil, errors.New("session revoked")
 	}
 
-	return uint(hostIDFloat), nil
+	return &TokenInfo{HostID: hostID, SessionID: sessionID, Method: session.Method}, nil
 }
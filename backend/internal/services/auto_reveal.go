@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"quiz-game-backend/internal/models"
+)
+
+const maxAutoRevealDelay = 60
+
+func (s *SessionService) SetAutoReveal(sessionID, hostID uint, enabled bool, delaySec int) (*SessionState, error) {
+	var session models.Session
+	if err := findInWorkspace(s.db, &session, sessionID, hostID); err != nil {
+		return nil, errors.New("session not found")
+	}
+	if delaySec < 0 || delaySec > maxAutoRevealDelay {
+		return nil, errors.New("auto_reveal_delay must be between 0 and 60 seconds")
+	}
+
+	if err := s.db.Model(&session).Updates(map[string]interface{}{
+		"auto_reveal":       enabled,
+		"auto_reveal_delay": delaySec,
+	}).Error; err != nil {
+		return nil, err
+	}
+	if !enabled {
+		s.cancelAutoReveal(sessionID)
+	}
+
+	state, err := s.GetSession(sessionID)
+	s.publishState(EventSettingsUpdated, state)
+	return state, err
+}
+
+// checkAutoReveal schedules a reveal once every active participant has answered
+// the current question. Participants who are away or offline are not waited for.
+func (s *SessionService) checkAutoReveal(session *models.Session, questionID uint) {
+	if !session.AutoReveal {
+		return
+	}
+
+	var participants []models.Participant
+	s.db.Where("session_id = ?", session.ID).Find(&participants)
+
+	var answered []uint
+	s.db.Model(&models.Answer{}).
+		Where("session_id = ? AND question_id = ?", session.ID, questionID).
+		Pluck("participant_id", &answered)
+	answeredSet := make(map[uint]bool, len(answered))
+	for _, id := range answered {
+		answeredSet[id] = true
+	}
+
+	active := 0
+	for _, p := range participants {
+		if s.presence != nil && !s.presence.IsParticipantActive(p) && !answeredSet[p.ID] {
+			continue
+		}
+		active++
+		if !answeredSet[p.ID] {
+			return
+		}
+	}
+	if active == 0 {
+		return
+	}
+
+	s.autoMu.Lock()
+	defer s.autoMu.Unlock()
+	if _, pending := s.pendingReveals[session.ID]; pending {
+		return
+	}
+
+	sessionID, hostID, questionNum := session.ID, session.HostID, session.CurrentQuestion
+	delay := time.Duration(session.AutoRevealDelay) * time.Second
+	s.pendingReveals[session.ID] = time.AfterFunc(delay, func() {
+		s.autoMu.Lock()
+		delete(s.pendingReveals, sessionID)
+		s.autoMu.Unlock()
+		s.runAutoReveal(sessionID, hostID, questionNum)
+	})
+}
+
+// RecheckAutoReveal re-runs the auto-reveal check for the running question of
+// a room after a player dropped out of it, since the rest may all have
+// answered already.
+func (s *SessionService) RecheckAutoReveal(roomID uint) {
+	var sessions []models.Session
+	s.db.Where("room_id = ? AND status = ? AND auto_reveal = ?", roomID, models.SessionStatusQuestion, true).
+		Find(&sessions)
+	for i := range sessions {
+		questions := s.getOrderedQuestions(sessions[i].QuizID)
+		if sessions[i].CurrentQuestion < 1 || sessions[i].CurrentQuestion > len(questions) {
+			continue
+		}
+		s.checkAutoReveal(&sessions[i], questions[sessions[i].CurrentQuestion-1].Question.ID)
+	}
+}
+
+// onPresenceChange rechecks auto-reveal when a player goes away or offline.
+func (s *SessionService) onPresenceChange(ev PresenceEvent) {
+	if ev.RoomID == 0 || ev.Status == PresenceOnline {
+		return
+	}
+	s.RecheckAutoReveal(ev.RoomID)
+}
+
+func (s *SessionService) runAutoReveal(sessionID, hostID uint, questionNum int) {
+	var current models.Session
+	if err := s.db.First(&current, sessionID).Error; err != nil {
+		return
+	}
+	if current.Status != models.SessionStatusQuestion || current.CurrentQuestion != questionNum || !current.AutoReveal {
+		return
+	}
+
+	// RevealAnswer publishes the revealed event for subscribers.
+	_, err := s.audit.SessionChange(hostID, SystemActor("auto_reveal"), AuditSessionReveal, sessionID, func() (*SessionState, error) {
+		return s.RevealAnswer(sessionID, hostID)
+	})
+	if err != nil {
+		log.Printf("auto-reveal session %d: %v", sessionID, err)
+	}
+}
+
+func (s *SessionService) cancelAutoReveal(sessionID uint) {
+	s.autoMu.Lock()
+	defer s.autoMu.Unlock()
+	if t, ok := s.pendingReveals[sessionID]; ok {
+		t.Stop()
+		delete(s.pendingReveals, sessionID)
+	}
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"quiz-game-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// encryptedTokenPrefix marks bot tokens stored as
+// "enc:v1:<key id>:<wrapped data key>:<ciphertext>".
+const encryptedTokenPrefix = "enc:v1:"
+
+// fallbackKeyID names the key derived from the JWT secret, used while no
+// BOT_TOKEN_KEYS are configured.
+const fallbackKeyID = "jwt"
+
+// TokenKeyring holds the key-encryption keys for bot tokens. The first key
+// encrypts; all of them decrypt, so old keys can stay during rotation.
+type TokenKeyring struct {
+	ids  []string
+	keys map[string][]byte
+}
+
+// ParseTokenKeys reads keys given as "id:base64key,id:base64key", newest
+// first. Keys are 32 bytes. The key derived from jwtSecret is appended for
+// reading tokens saved before any key was configured.
+func ParseTokenKeys(spec, jwtSecret string) (*TokenKeyring, error) {
+	kr := &TokenKeyring{keys: make(map[string][]byte)}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(part, ":")
+		if !ok || id == "" || id == fallbackKeyID {
+			return nil, fmt.Errorf("bot token key %q: want id:base64key", part)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("bot token key %q: must be 32 bytes in base64", id)
+		}
+		if _, dup := kr.keys[id]; dup {
+			return nil, fmt.Errorf("bot token key %q given twice", id)
+		}
+		kr.ids = append(kr.ids, id)
+		kr.keys[id] = key
+	}
+
+	derived := sha256.Sum256([]byte("bot-token-key:" + jwtSecret))
+	kr.ids = append(kr.ids, fallbackKeyID)
+	kr.keys[fallbackKeyID] = derived[:]
+	return kr, nil
+}
+
+// Configured reports whether a key other than the JWT-derived one is set.
+func (kr *TokenKeyring) Configured() bool {
+	return kr.ids[0] != fallbackKeyID
+}
+
+func (kr *TokenKeyring) primary() string {
+	return kr.ids[0]
+}
+
+// BotCredentials is a host with a bot and its decrypted token.
+type BotCredentials struct {
+	Host  models.Host
+	Token string
+}
+
+type BotTokenService struct {
+	db   *gorm.DB
+	keys *TokenKeyring
+}
+
+func NewBotTokenService(db *gorm.DB, keys *TokenKeyring) *BotTokenService {
+	return &BotTokenService{db: db, keys: keys}
+}
+
+// Encrypt seals a token with a fresh data key, which is itself sealed with
+// the primary key.
+func (s *BotTokenService) Encrypt(token string) (string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	id := s.keys.primary()
+	wrapped, err := sealGCM(s.keys.keys[id], dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := sealGCM(dataKey, []byte(token))
+	if err != nil {
+		return "", err
+	}
+	return encryptedTokenPrefix + id + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a stored token. Tokens saved before encryption are returned
+// as they are.
+func (s *BotTokenService) Decrypt(stored string) (string, error) {
+	if !strings.HasPrefix(stored, encryptedTokenPrefix) {
+		return stored, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(stored, encryptedTokenPrefix), ":")
+	if len(parts) != 3 {
+		return "", errors.New("malformed encrypted bot token")
+	}
+	kek, ok := s.keys.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("bot token key %q is not configured", parts[0])
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("malformed encrypted bot token")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("malformed encrypted bot token")
+	}
+	dataKey, err := openGCM(kek, wrapped)
+	if err != nil {
+		return "", err
+	}
+	token, err := openGCM(dataKey, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+// BotKey identifies a bot by its token without revealing it. Webhook paths and
+// rows kept per bot, such as conversation states, use it.
+func BotKey(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("%x", h[:16])
+}
+
+// Get returns the decrypted bot token of a host, or "" if it has none.
+func (s *BotTokenService) Get(hostID uint) (string, error) {
+	var host models.Host
+	if err := s.db.Select("bot_token").First(&host, hostID).Error; err != nil {
+		return "", err
+	}
+	if host.BotToken == "" {
+		return "", nil
+	}
+	return s.Decrypt(host.BotToken)
+}
+
+// Columns returns the host columns that store a bot token and link, with the
+// token encrypted; an empty token removes the bot. Callers write them along
+// with the rest of a settings change.
+func (s *BotTokenService) Columns(token, botLink string) (map[string]interface{}, error) {
+	stored := ""
+	if token != "" {
+		var err error
+		if stored, err = s.Encrypt(token); err != nil {
+			return nil, err
+		}
+	}
+	return map[string]interface{}{
+		"bot_token": stored,
+		"bot_link":  botLink,
+	}, nil
+}
+
+// Active returns every host with a bot. Tokens that can't be decrypted are
+// skipped and reported in the error.
+func (s *BotTokenService) Active() ([]BotCredentials, error) {
+	var hosts []models.Host
+	if err := s.db.Where("bot_token != '' AND bot_token IS NOT NULL").Find(&hosts).Error; err != nil {
+		return nil, err
+	}
+	var errs []error
+	result := make([]BotCredentials, 0, len(hosts))
+	for _, h := range hosts {
+		token, err := s.Decrypt(h.BotToken)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("host %d: %w", h.ID, err))
+			continue
+		}
+		result = append(result, BotCredentials{Host: h, Token: token})
+	}
+	return result, errors.Join(errs...)
+}
+
+// Reencrypt seals plaintext tokens and tokens under an older key with the
+// primary key. It runs at startup, which completes a key rotation.
+func (s *BotTokenService) Reencrypt() (int, error) {
+	var hosts []models.Host
+	if err := s.db.Select("id", "bot_token").
+		Where("bot_token != '' AND bot_token IS NOT NULL AND bot_token NOT LIKE ?", encryptedTokenPrefix+s.keys.primary()+":%").
+		Find(&hosts).Error; err != nil {
+		return 0, err
+	}
+	n := 0
+	var errs []error
+	for _, h := range hosts {
+		token, err := s.Decrypt(h.BotToken)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("host %d: %w", h.ID, err))
+			continue
+		}
+		stored, err := s.Encrypt(token)
+		if err != nil {
+			return n, err
+		}
+		if err := s.db.Model(&models.Host{}).Where("id = ?", h.ID).Update("bot_token", stored).Error; err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, errors.Join(errs...)
+}
+
+// MaskBotToken keeps the bot ID and the last characters of a token, enough to
+// tell bots apart without revealing the secret.
+func MaskBotToken(token string) string {
+	id, secret, ok := strings.Cut(token, ":")
+	if !ok || len(secret) <= 4 {
+		return "••••"
+	}
+	return id + ":••••" + secret[len(secret)-4:]
+}
+
+func sealGCM(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func openGCM(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("malformed encrypted bot token")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("bot token can't be decrypted with the configured key")
+	}
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
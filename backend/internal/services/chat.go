@@ -0,0 +1,276 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"quiz-game-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxChatMessageLength = 200
+	chatHistoryLimit     = 100
+)
+
+// Reactions is the fixed set of emoji players can send. Telegram callbacks
+// refer to them by index to stay within the callback data limit.
+var Reactions = []string{"👍", "😂", "😮", "🔥", "👏", "❤️"}
+
+// ChatSender identifies who is posting: a web member or a Telegram player.
+type ChatSender struct {
+	MemberID   uint
+	TelegramID int64
+	Nickname   string
+}
+
+type ReactionEvent struct {
+	RoomID     uint   `json:"room_id"`
+	MemberID   uint   `json:"member_id,omitempty"`
+	TelegramID int64  `json:"telegram_id,omitempty"`
+	Nickname   string `json:"nickname"`
+	Emoji      string `json:"emoji"`
+}
+
+type ChatSettings struct {
+	ChatDisabled bool `json:"chat_disabled"`
+	ChatQuiet    bool `json:"chat_quiet_during_questions"`
+}
+
+type ChatService struct {
+	db        *gorm.DB
+	reactions *senderLimiter
+	messages  *senderLimiter
+}
+
+func NewChatService(db *gorm.DB) *ChatService {
+	return &ChatService{
+		db:        db,
+		reactions: newSenderLimiter(5, 5*time.Second),
+		messages:  newSenderLimiter(3, 10*time.Second),
+	}
+}
+
+func (s *ChatService) SendReaction(roomID uint, sender ChatSender, emoji string) (*ReactionEvent, error) {
+	if !isReaction(emoji) {
+		return nil, errors.New("unsupported reaction")
+	}
+	var room models.Room
+	if err := s.db.First(&room, roomID).Error; err != nil {
+		return nil, errors.New("room not found")
+	}
+	if s.isMuted(roomID, sender) {
+		return nil, errors.New("you are muted in this room")
+	}
+	if !s.reactions.allow(roomID, sender) {
+		return nil, errors.New("too many reactions, slow down")
+	}
+
+	return &ReactionEvent{
+		RoomID:     roomID,
+		MemberID:   sender.MemberID,
+		TelegramID: sender.TelegramID,
+		Nickname:   sender.Nickname,
+		Emoji:      emoji,
+	}, nil
+}
+
+func (s *ChatService) SendMessage(roomID uint, sender ChatSender, text string) (*models.ChatMessage, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("message is empty")
+	}
+	if utf8.RuneCountInString(text) > maxChatMessageLength {
+		return nil, errors.New("message is too long")
+	}
+
+	var room models.Room
+	if err := s.db.First(&room, roomID).Error; err != nil {
+		return nil, errors.New("room not found")
+	}
+	if room.ChatDisabled {
+		return nil, errors.New("chat is disabled in this room")
+	}
+	if room.ChatQuiet && s.questionInProgress(roomID) {
+		return nil, errors.New("chat is paused while a question is open")
+	}
+	if s.isMuted(roomID, sender) {
+		return nil, errors.New("you are muted in this room")
+	}
+	if !s.messages.allow(roomID, sender) {
+		return nil, errors.New("too many messages, slow down")
+	}
+
+	msg := models.ChatMessage{
+		RoomID:     roomID,
+		MemberID:   sender.MemberID,
+		TelegramID: sender.TelegramID,
+		Nickname:   sender.Nickname,
+		Text:       text,
+	}
+	if err := s.db.Create(&msg).Error; err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// ListMessages returns the most recent messages of a room in chronological order.
+func (s *ChatService) ListMessages(roomID uint) ([]models.ChatMessage, error) {
+	var messages []models.ChatMessage
+	if err := s.db.Where("room_id = ?", roomID).
+		Order("created_at DESC").
+		Limit(chatHistoryLimit).
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+func (s *ChatService) ClearChat(roomID, hostID uint) error {
+	if _, err := s.hostRoom(roomID, hostID); err != nil {
+		return err
+	}
+	return s.db.Where("room_id = ?", roomID).Delete(&models.ChatMessage{}).Error
+}
+
+func (s *ChatService) UpdateSettings(roomID, hostID uint, settings ChatSettings) (*ChatSettings, error) {
+	room, err := s.hostRoom(roomID, hostID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(room).Updates(map[string]interface{}{
+		"chat_disabled": settings.ChatDisabled,
+		"chat_quiet":    settings.ChatQuiet,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SetMuted mutes or unmutes a web member (memberID) or a Telegram player (telegramID).
+func (s *ChatService) SetMuted(roomID, hostID, memberID uint, telegramID int64, muted bool) error {
+	if _, err := s.hostRoom(roomID, hostID); err != nil {
+		return err
+	}
+	if memberID == 0 && telegramID == 0 {
+		return errors.New("member_id or telegram_id is required")
+	}
+
+	q := s.db.Where("room_id = ?", roomID)
+	if memberID > 0 {
+		q = q.Where("member_id = ?", memberID)
+	} else {
+		q = q.Where("telegram_id = ?", telegramID)
+	}
+	if !muted {
+		return q.Delete(&models.RoomMute{}).Error
+	}
+
+	var existing models.RoomMute
+	if q.First(&existing).Error == nil {
+		return nil
+	}
+	return s.db.Create(&models.RoomMute{RoomID: roomID, MemberID: memberID, TelegramID: telegramID}).Error
+}
+
+func (s *ChatService) ListMutes(roomID, hostID uint) ([]models.RoomMute, error) {
+	if _, err := s.hostRoom(roomID, hostID); err != nil {
+		return nil, err
+	}
+	var mutes []models.RoomMute
+	s.db.Where("room_id = ?", roomID).Find(&mutes)
+	return mutes, nil
+}
+
+func (s *ChatService) hostRoom(roomID, hostID uint) (*models.Room, error) {
+	var room models.Room
+	if err := findInWorkspace(s.db, &room, roomID, hostID); err != nil {
+		return nil, errors.New("room not found")
+	}
+	return &room, nil
+}
+
+func (s *ChatService) isMuted(roomID uint, sender ChatSender) bool {
+	var count int64
+	q := s.db.Model(&models.RoomMute{}).Where("room_id = ?", roomID)
+	if sender.MemberID > 0 {
+		q = q.Where("member_id = ?", sender.MemberID)
+	} else {
+		q = q.Where("telegram_id = ?", sender.TelegramID)
+	}
+	q.Count(&count)
+	return count > 0
+}
+
+func (s *ChatService) questionInProgress(roomID uint) bool {
+	var count int64
+	s.db.Model(&models.Session{}).
+		Where("room_id = ? AND status = ?", roomID, models.SessionStatusQuestion).
+		Count(&count)
+	return count > 0
+}
+
+func isReaction(emoji string) bool {
+	for _, r := range Reactions {
+		if r == emoji {
+			return true
+		}
+	}
+	return false
+}
+
+// senderLimiter allows at most limit events per sender within a sliding window.
+type senderLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu   sync.Mutex
+	hits map[senderKey][]time.Time
+}
+
+type senderKey struct {
+	roomID     uint
+	memberID   uint
+	telegramID int64
+}
+
+func newSenderLimiter(limit int, window time.Duration) *senderLimiter {
+	return &senderLimiter{limit: limit, window: window, hits: make(map[senderKey][]time.Time)}
+}
+
+func (l *senderLimiter) allow(roomID uint, sender ChatSender) bool {
+	key := senderKey{roomID: roomID, memberID: sender.MemberID, telegramID: sender.TelegramID}
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := l.hits[key][:0]
+	for _, t := range l.hits[key] {
+		if now.Sub(t) < l.window {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= l.limit {
+		l.hits[key] = recent
+		return false
+	}
+	l.hits[key] = append(recent, now)
+
+	// Drop idle senders so the map doesn't grow with every player ever seen.
+	if len(l.hits) > 1000 {
+		for k, v := range l.hits {
+			if len(v) == 0 || now.Sub(v[len(v)-1]) >= l.window {
+				delete(l.hits, k)
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"quiz-game-backend/internal/models"
+)
+
+type AnswerDistribution struct {
+	QuestionID uint          `json:"question_id"`
+	Total      int           `json:"total"`
+	Correct    int           `json:"correct"`
+	Incorrect  int           `json:"incorrect"`
+	Options    []OptionCount `json:"options,omitempty"`
+}
+
+type OptionCount struct {
+	OptionID uint `json:"option_id"`
+	Count    int  `json:"count"`
+}
+
+type LeaderboardDeltaEntry struct {
+	LeaderboardEntry
+	PreviousPosition int `json:"previous_position"`
+	ScoreDelta       int `json:"score_delta"`
+}
+
+// GetAnswerDistribution returns how answers to the current question were spread.
+// It is only available once the answer is revealed so it cannot leak the result.
+func (s *SessionService) GetAnswerDistribution(sessionID uint) (*AnswerDistribution, error) {
+	var session models.Session
+	if err := s.db.First(&session, sessionID).Error; err != nil {
+		return nil, errors.New("session not found")
+	}
+	if session.Status != models.SessionStatusRevealed && session.Status != models.SessionStatusFinished {
+		return nil, errors.New("answer not revealed yet")
+	}
+
+	questions := s.getOrderedQuestions(session.QuizID)
+	if session.CurrentQuestion < 1 || session.CurrentQuestion > len(questions) {
+		return nil, errors.New("invalid question state")
+	}
+	currentQ := questions[session.CurrentQuestion-1].Question
+
+	var answers []models.Answer
+	s.db.Where("session_id = ? AND question_id = ?", sessionID, currentQ.ID).Find(&answers)
+
+	counts := make(map[uint]int)
+	dist := &AnswerDistribution{QuestionID: currentQ.ID, Total: len(answers)}
+	for _, a := range answers {
+		if a.IsCorrect {
+			dist.Correct++
+		} else {
+			dist.Incorrect++
+		}
+		if a.OptionID > 0 {
+			counts[a.OptionID]++
+			continue
+		}
+		if currentQ.Type == models.QuestionTypeMultipleChoice && a.AnswerData != "" {
+			var data ComplexAnswerData
+			if json.Unmarshal([]byte(a.AnswerData), &data) == nil {
+				for _, id := range data.OptionIDs {
+					counts[id]++
+				}
+			}
+		}
+	}
+
+	if currentQ.Type == "" || currentQ.Type == models.QuestionTypeSingleChoice || currentQ.Type == models.QuestionTypeMultipleChoice {
+		for _, o := range currentQ.Options {
+			dist.Options = append(dist.Options, OptionCount{OptionID: o.ID, Count: counts[o.ID]})
+		}
+	}
+	return dist, nil
+}
+
+// GetLeaderboardWithDelta annotates the leaderboard with each player's position and
+// score before the current question was scored, so displays can animate the change.
+func (s *SessionService) GetLeaderboardWithDelta(sessionID uint) ([]LeaderboardDeltaEntry, error) {
+	var session models.Session
+	if err := s.db.First(&session, sessionID).Error; err != nil {
+		return nil, errors.New("session not found")
+	}
+
+	var participants []models.Participant
+	if err := s.db.Where("session_id = ?", sessionID).
+		Order("total_score DESC").
+		Find(&participants).Error; err != nil {
+		return nil, err
+	}
+
+	deltas := make(map[uint]int)
+	if session.Status == models.SessionStatusRevealed || session.Status == models.SessionStatusFinished {
+		questions := s.getOrderedQuestions(session.QuizID)
+		if session.CurrentQuestion >= 1 && session.CurrentQuestion <= len(questions) {
+			var answers []models.Answer
+			s.db.Where("session_id = ? AND question_id = ?", sessionID, questions[session.CurrentQuestion-1].Question.ID).
+				Find(&answers)
+			for _, a := range answers {
+				deltas[a.ParticipantID] = a.Score
+			}
+		}
+	}
+
+	type prev struct {
+		idx   int
+		score int
+	}
+	previous := make([]prev, len(participants))
+	for i, p := range participants {
+		previous[i] = prev{idx: i, score: p.TotalScore - deltas[p.ID]}
+	}
+	sort.SliceStable(previous, func(a, b int) bool { return previous[a].score > previous[b].score })
+	prevPos := make(map[int]int, len(previous))
+	for pos, p := range previous {
+		prevPos[p.idx] = pos + 1
+	}
+
+	result := make([]LeaderboardDeltaEntry, len(participants))
+	for i, p := range participants {
+		result[i] = LeaderboardDeltaEntry{
+			LeaderboardEntry: LeaderboardEntry{
+				Position:   i + 1,
+				Nickname:   p.Nickname,
+				TotalScore: p.TotalScore,
+				MemberID:   p.MemberID,
+				TelegramID: p.TelegramID,
+			},
+			PreviousPosition: prevPos[i],
+			ScoreDelta:       deltas[p.ID],
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"log"
+	"sync"
+
+	"quiz-game-backend/internal/models"
+)
+
+// Session event types. They double as the WebSocket message types sent to clients.
+const (
+	EventQuizStarted     = "quiz_started"
+	EventQuestion        = "question"
+	EventRevealed        = "revealed"
+	EventFinished        = "finished"
+	EventAnswerReceived  = "answer_received"
+	EventSettingsUpdated = "settings_updated"
+)
+
+const eventBufferSize = 256
+
+// SessionEvent describes a change of a session. State is the session snapshot
+// after the change; it is nil for answer_received to keep answers cheap, which
+// carries the question and its answer count instead.
+type SessionEvent struct {
+	Type        string
+	SessionID   uint
+	RoomID      uint
+	State       *SessionState
+	QuestionID  uint
+	AnswerCount int
+}
+
+type eventSubscriber struct {
+	ch   chan SessionEvent
+	done chan struct{}
+}
+
+// EventBus fans session events out to in-process subscribers. Every subscriber
+// gets its own buffered queue and goroutine, so events arrive in publish order
+// and a slow subscriber never blocks the publisher or the others.
+type EventBus struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   map[int]*eventSubscriber
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{subs: make(map[int]*eventSubscriber)}
+}
+
+// Subscribe registers fn for all future events and returns a function that
+// removes the subscription.
+func (b *EventBus) Subscribe(fn func(SessionEvent)) func() {
+	sub := &eventSubscriber{
+		ch:   make(chan SessionEvent, eventBufferSize),
+		done: make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = sub
+	b.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case ev := <-sub.ch:
+				fn(ev)
+			case <-sub.done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, id)
+			b.mu.Unlock()
+			close(sub.done)
+		})
+	}
+}
+
+// Publish delivers ev to every subscriber. A subscriber whose queue is full
+// misses the event; consumers that must not miss state run a reconciliation loop.
+func (b *EventBus) Publish(ev SessionEvent) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sub := range b.subs {
+		select {
+		case sub.ch <- ev:
+		default:
+			log.Printf("events: subscriber queue full, dropping %s for session %d", ev.Type, ev.SessionID)
+		}
+	}
+}
+
+// Subscribe registers fn for the session events published by this service.
+func (s *SessionService) Subscribe(fn func(SessionEvent)) func() {
+	return s.events.Subscribe(fn)
+}
+
+// publishState publishes an event carrying the given session snapshot.
+func (s *SessionService) publishState(eventType string, state *SessionState) {
+	if state == nil {
+		return
+	}
+	s.events.Publish(SessionEvent{
+		Type:      eventType,
+		SessionID: state.ID,
+		RoomID:    state.RoomID,
+		State:     state,
+	})
+}
+
+// answerRecorded runs after any answer was stored: it announces the answer and
+// checks whether the question can be revealed automatically.
+func (s *SessionService) answerRecorded(session *models.Session, questionID uint) {
+	var count int64
+	s.db.Model(&models.Answer{}).
+		Where("session_id = ? AND question_id = ?", session.ID, questionID).
+		Count(&count)
+	s.events.Publish(SessionEvent{
+		Type:        EventAnswerReceived,
+		SessionID:   session.ID,
+		RoomID:      session.RoomID,
+		QuestionID:  questionID,
+		AnswerCount: int(count),
+	})
+	s.checkAutoReveal(session, questionID)
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"quiz-game-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrBannedFromRoom = errors.New("you are banned from this room")
+
+// PlayerRef points at a player in a room: a web member by MemberID or a
+// Telegram player by TelegramID.
+type PlayerRef struct {
+	MemberID   uint  `json:"member_id"`
+	TelegramID int64 `json:"telegram_id"`
+}
+
+type KickResult struct {
+	RoomID     uint   `json:"room_id"`
+	MemberID   uint   `json:"member_id,omitempty"`
+	TelegramID int64  `json:"telegram_id,omitempty"`
+	Nickname   string `json:"nickname"`
+	Banned     bool   `json:"banned"`
+}
+
+type RenameResult struct {
+	RoomID     uint   `json:"room_id"`
+	MemberID   uint   `json:"member_id,omitempty"`
+	TelegramID int64  `json:"telegram_id,omitempty"`
+	Nickname   string `json:"nickname"`
+}
+
+// OnKick registers a callback invoked after a host removed a player, so the
+// transport layers can close the player's connections.
+func (s *RoomService) OnKick(fn func(KickResult)) {
+	s.kickMu.Lock()
+	defer s.kickMu.Unlock()
+	s.kickHandlers = append(s.kickHandlers, fn)
+}
+
+func (s *RoomService) IsBanned(roomID uint, webToken string, telegramID int64) bool {
+	if webToken == "" && telegramID == 0 {
+		return false
+	}
+	q := s.db.Model(&models.RoomBan{}).Where("room_id = ?", roomID)
+	switch {
+	case webToken != "" && telegramID > 0:
+		q = q.Where("web_token = ? OR telegram_id = ?", webToken, telegramID)
+	case webToken != "":
+		q = q.Where("web_token = ?", webToken)
+	default:
+		q = q.Where("telegram_id = ?", telegramID)
+	}
+	var count int64
+	q.Count(&count)
+	return count > 0
+}
+
+// KickPlayer removes a player from the room and from any unfinished session in
+// it. With ban set, the player's web token or Telegram ID can no longer join.
+func (s *RoomService) KickPlayer(roomID, hostID uint, ref PlayerRef, ban bool) (*KickResult, error) {
+	if err := s.checkHost(roomID, hostID); err != nil {
+		return nil, err
+	}
+
+	result := KickResult{RoomID: roomID, Banned: ban}
+	var webToken string
+	switch {
+	case ref.MemberID > 0:
+		var member models.RoomMember
+		if err := s.db.Where("id = ? AND room_id = ?", ref.MemberID, roomID).First(&member).Error; err != nil {
+			return nil, errors.New("member not found")
+		}
+		result.MemberID = member.ID
+		result.TelegramID = member.TelegramID
+		result.Nickname = member.Nickname
+		webToken = member.WebToken
+	case ref.TelegramID > 0:
+		result.TelegramID = ref.TelegramID
+		var p models.Participant
+		if err := s.db.Joins("JOIN sessions ON sessions.id = participants.session_id").
+			Where("sessions.room_id = ? AND participants.telegram_id = ?", roomID, ref.TelegramID).
+			Order("participants.id DESC").
+			First(&p).Error; err != nil {
+			return nil, errors.New("player not found")
+		}
+		result.Nickname = p.Nickname
+	default:
+		return nil, errors.New("member_id or telegram_id is required")
+	}
+
+	var participantIDs []uint
+	s.activeParticipants(roomID, result.MemberID, result.TelegramID).Pluck("participants.id", &participantIDs)
+	if len(participantIDs) > 0 {
+		s.db.Where("participant_id IN ?", participantIDs).Delete(&models.Answer{})
+		s.db.Where("id IN ?", participantIDs).Delete(&models.Participant{})
+	}
+
+	if result.MemberID > 0 {
+		if err := s.RemoveMember(result.MemberID); err != nil {
+			return nil, err
+		}
+	}
+
+	if ban {
+		if err := s.db.Create(&models.RoomBan{
+			RoomID:     roomID,
+			WebToken:   webToken,
+			TelegramID: result.TelegramID,
+			Nickname:   result.Nickname,
+		}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	s.kickMu.Lock()
+	handlers := append([]func(KickResult){}, s.kickHandlers...)
+	s.kickMu.Unlock()
+	for _, fn := range handlers {
+		fn(result)
+	}
+	return &result, nil
+}
+
+// RenameMember force-renames a player in the room and its unfinished sessions.
+// The new name is still checked against the blocklist.
+func (s *RoomService) RenameMember(roomID, hostID uint, ref PlayerRef, nickname string) (*RenameResult, error) {
+	if err := s.checkHost(roomID, hostID); err != nil {
+		return nil, err
+	}
+	nickname = strings.TrimSpace(nickname)
+	if nickname == "" || len([]rune(nickname)) > 100 {
+		return nil, errors.New("nickname must be 1-100 characters")
+	}
+	if err := s.nicknames.Validate(nickname); err != nil {
+		return nil, err
+	}
+
+	result := RenameResult{RoomID: roomID, Nickname: nickname}
+	switch {
+	case ref.MemberID > 0:
+		var member models.RoomMember
+		if err := s.db.Where("id = ? AND room_id = ?", ref.MemberID, roomID).First(&member).Error; err != nil {
+			return nil, errors.New("member not found")
+		}
+		s.db.Model(&member).Update("nickname", nickname)
+		result.MemberID = member.ID
+		result.TelegramID = member.TelegramID
+	case ref.TelegramID > 0:
+		result.TelegramID = ref.TelegramID
+		var room models.Room
+		s.db.First(&room, roomID)
+		s.db.Model(&models.TelegramUser{}).
+			Where("telegram_id = ? AND host_id = ?", ref.TelegramID, room.HostID).
+			Update("nickname", nickname)
+	default:
+		return nil, errors.New("member_id or telegram_id is required")
+	}
+
+	var participantIDs []uint
+	s.activeParticipants(roomID, result.MemberID, result.TelegramID).Pluck("participants.id", &participantIDs)
+	if len(participantIDs) > 0 {
+		s.db.Model(&models.Participant{}).Where("id IN ?", participantIDs).Update("nickname", nickname)
+	}
+	return &result, nil
+}
+
+func (s *RoomService) ListBans(roomID, hostID uint) ([]models.RoomBan, error) {
+	if err := s.checkHost(roomID, hostID); err != nil {
+		return nil, err
+	}
+	var bans []models.RoomBan
+	s.db.Where("room_id = ?", roomID).Order("created_at DESC").Find(&bans)
+	return bans, nil
+}
+
+func (s *RoomService) Unban(roomID, hostID, banID uint) error {
+	if err := s.checkHost(roomID, hostID); err != nil {
+		return err
+	}
+	res := s.db.Where("id = ? AND room_id = ?", banID, roomID).Delete(&models.RoomBan{})
+	if res.RowsAffected == 0 {
+		return errors.New("ban not found")
+	}
+	return res.Error
+}
+
+func (s *RoomService) checkHost(roomID, hostID uint) error {
+	var room models.Room
+	if err := findInWorkspace(s.db, &room, roomID, hostID); err != nil {
+		return errors.New("room not found")
+	}
+	return nil
+}
+
+// activeParticipants selects the player's participant rows in unfinished sessions of the room.
+func (s *RoomService) activeParticipants(roomID, memberID uint, telegramID int64) *gorm.DB {
+	q := s.db.Model(&models.Participant{}).
+		Joins("JOIN sessions ON sessions.id = participants.session_id").
+		Where("sessions.room_id = ? AND sessions.status != ?", roomID, models.SessionStatusFinished)
+	if memberID > 0 {
+		return q.Where("participants.member_id = ?", memberID)
+	}
+	return q.Where("participants.telegram_id = ?", telegramID)
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrNicknameBlocked = errors.New("nickname is not allowed")
+
+// NicknameFilter rejects nicknames containing any blocklisted word.
+// Matching is case-insensitive and ignores spaces and common separators,
+// so "B a d" and "b_a-d" are caught as well.
+type NicknameFilter struct {
+	words []string
+}
+
+func NewNicknameFilter(words []string) *NicknameFilter {
+	f := &NicknameFilter{}
+	for _, w := range words {
+		if w = normalizeNickname(w); w != "" {
+			f.words = append(f.words, w)
+		}
+	}
+	return f
+}
+
+func (f *NicknameFilter) Validate(nickname string) error {
+	if f == nil {
+		return nil
+	}
+	n := normalizeNickname(nickname)
+	for _, w := range f.words {
+		if strings.Contains(n, w) {
+			return ErrNicknameBlocked
+		}
+	}
+	return nil
+}
+
+func normalizeNickname(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-', '.', '*':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(s)))
+}
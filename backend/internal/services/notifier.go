@@ -0,0 +1,66 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Notification is a message for a host account, such as a password reset
+// link.
+type Notification struct {
+	HostID   uint   `json:"host_id"`
+	Username string `json:"username"`
+	Subject  string `json:"subject"`
+	Body     string `json:"body"`
+}
+
+// Notifier delivers notifications to hosts. Accounts have no email or other
+// contact yet, so the built-in notifiers hand messages to the operator, who
+// passes them on; a mail or chat delivery only has to implement Notify.
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// LogNotifier writes notifications to the server log.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(n Notification) error {
+	log.Printf("notification for %s (host %d): %s\n%s", n.Username, n.HostID, n.Subject, n.Body)
+	return nil
+}
+
+// FileNotifier appends notifications to a file, one JSON object per line.
+// The file is readable by its owner only since it holds reset links.
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (f *FileNotifier) Notify(n Notification) error {
+	line, err := json.Marshal(struct {
+		Time time.Time `json:"time"`
+		Notification
+	}{time.Now(), n})
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
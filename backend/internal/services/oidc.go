@@ -0,0 +1,590 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"quiz-game-backend/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// OIDCStateTTL is how long a sign-in may take at the provider.
+	OIDCStateTTL = 10 * time.Minute
+	// oidcDiscoveryTTL is how long the provider's metadata is cached.
+	oidcDiscoveryTTL = time.Hour
+	// oidcKeysRefresh is the least time between fetches of the provider's
+	// signing keys, which are fetched again when a token names an unknown key.
+	oidcKeysRefresh = time.Minute
+	oidcClockSkew   = time.Minute
+	oidcMaxResponse = 1 << 20
+)
+
+var (
+	ErrOIDCDisabled      = errors.New("single sign-on is not configured")
+	ErrOIDCInvalidState  = errors.New("sign-in expired or was already used, try again")
+	ErrOIDCProvider      = errors.New("identity provider error")
+	ErrIdentityNotLinked = errors.New("no account is linked to this identity")
+	ErrIdentityLinked    = errors.New("this identity is linked to another account")
+)
+
+var usernameUnsafe = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// OIDCConfig describes the OpenID Connect provider hosts can sign in with.
+// Any provider with discovery works, including a local mock issuer.
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the frontend page the provider sends the browser back to.
+	RedirectURL string
+	Scopes      []string
+	// Name is shown on the login button.
+	Name string
+	// AutoRegister creates an account on the first sign-in of an identity
+	// that no account is linked to.
+	AutoRegister bool
+}
+
+// OIDCIdentity is the user an ID token was issued for.
+type OIDCIdentity struct {
+	Issuer   string
+	Subject  string
+	Email    string
+	Username string
+}
+
+type oidcProvider struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	TokenAuthMethods      []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// OIDCService signs hosts in through an OpenID Connect provider with the
+// authorization code flow and PKCE, and links provider identities to
+// accounts.
+type OIDCService struct {
+	db     *gorm.DB
+	auth   *AuthService
+	cfg    OIDCConfig
+	client *http.Client
+
+	mu         sync.Mutex
+	provider   *oidcProvider
+	providerAt time.Time
+	keys       map[string]interface{}
+	keysAt     time.Time
+}
+
+func NewOIDCService(db *gorm.DB, auth *AuthService, cfg OIDCConfig) *OIDCService {
+	hasOpenID := false
+	for _, scope := range cfg.Scopes {
+		hasOpenID = hasOpenID || scope == "openid"
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	} else if !hasOpenID {
+		cfg.Scopes = append([]string{"openid"}, cfg.Scopes...)
+	}
+	return &OIDCService{db: db, auth: auth, cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Enabled reports whether a provider is configured.
+func (s *OIDCService) Enabled() bool {
+	return s.cfg.Issuer != "" && s.cfg.ClientID != "" && s.cfg.RedirectURL != ""
+}
+
+// Name is the provider name for the login button.
+func (s *OIDCService) Name() string {
+	if s.cfg.Name == "" {
+		return "SSO"
+	}
+	return s.cfg.Name
+}
+
+// AuthURL starts a sign-in and returns the provider URL to send the browser
+// to, along with the state the browser has to bring back. With linkHostID
+// set, finishing it links the identity to that host instead of signing in.
+func (s *OIDCService) AuthURL(linkHostID uint) (authURL, state string, err error) {
+	if !s.Enabled() {
+		return "", "", ErrOIDCDisabled
+	}
+	p, err := s.discover()
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err = randomURLToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomURLToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := randomURLToken(48)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	s.db.Where("expires_at < ?", now).Delete(&models.OIDCLoginState{})
+	if err := s.db.Create(&models.OIDCLoginState{
+		StateHash:  sha256Hex(state),
+		Nonce:      nonce,
+		Verifier:   verifier,
+		LinkHostID: linkHostID,
+		ExpiresAt:  now.Add(OIDCStateTTL),
+	}).Error; err != nil {
+		return "", "", err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {s.cfg.ClientID},
+		"redirect_uri":          {s.cfg.RedirectURL},
+		"scope":                 {strings.Join(s.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(p.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return p.AuthorizationEndpoint + sep + q.Encode(), state, nil
+}
+
+// Login finishes a sign-in and opens a login session for the account the
+// identity is linked to, creating the account if AutoRegister is on.
+func (s *OIDCService) Login(code, state string, client ClientInfo) (*TokenPair, *models.Host, error) {
+	id, linkHostID, err := s.finish(code, state)
+	if err != nil {
+		return nil, nil, err
+	}
+	if linkHostID != 0 {
+		return nil, nil, ErrOIDCInvalidState
+	}
+
+	var identity models.HostIdentity
+	if err := s.db.Where("issuer = ? AND subject = ?", id.Issuer, id.Subject).First(&identity).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, err
+		}
+		if !s.cfg.AutoRegister {
+			return nil, nil, ErrIdentityNotLinked
+		}
+		if identity, err = s.register(id); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	updates := map[string]interface{}{"last_login_at": time.Now()}
+	if id.Email != "" {
+		updates["email"] = truncate(id.Email, 255)
+	}
+	s.db.Model(&identity).Updates(updates)
+
+	var host models.Host
+	if err := s.db.First(&host, identity.HostID).Error; err != nil {
+		return nil, nil, errors.New("host not found")
+	}
+	pair, err := s.auth.StartSSOSession(host.ID, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, &host, nil
+}
+
+// Link finishes a sign-in started by hostID to link an identity.
+func (s *OIDCService) Link(code, state string, hostID uint) (*models.HostIdentity, error) {
+	id, linkHostID, err := s.finish(code, state)
+	if err != nil {
+		return nil, err
+	}
+	if linkHostID != hostID {
+		return nil, ErrOIDCInvalidState
+	}
+
+	var existing models.HostIdentity
+	if err := s.db.Where("issuer = ? AND subject = ?", id.Issuer, id.Subject).First(&existing).Error; err == nil {
+		if existing.HostID != hostID {
+			return nil, ErrIdentityLinked
+		}
+		return &existing, nil
+	}
+
+	identity := models.HostIdentity{
+		HostID:  hostID,
+		Issuer:  id.Issuer,
+		Subject: id.Subject,
+		Email:   truncate(id.Email, 255),
+	}
+	if err := s.db.Create(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// Identities lists the identities linked to a host.
+func (s *OIDCService) Identities(hostID uint) ([]models.HostIdentity, error) {
+	var identities []models.HostIdentity
+	err := s.db.Where("host_id = ?", hostID).Order("created_at ASC").Find(&identities).Error
+	return identities, err
+}
+
+// Unlink removes an identity from a host. The last one stays while it is
+// the only way to sign in.
+func (s *OIDCService) Unlink(identityID, hostID uint) error {
+	var identity models.HostIdentity
+	if err := s.db.Where("id = ? AND host_id = ?", identityID, hostID).First(&identity).Error; err != nil {
+		return errors.New("identity not found")
+	}
+	var host models.Host
+	if err := s.db.First(&host, hostID).Error; err != nil {
+		return errors.New("host not found")
+	}
+	var count int64
+	s.db.Model(&models.HostIdentity{}).Where("host_id = ?", hostID).Count(&count)
+	if count == 1 {
+		if host.SSORequired {
+			return errors.New("the last identity can't be unlinked while single sign-on is required")
+		}
+		if host.PasswordHash == "" {
+			return errors.New("set a password before unlinking the last identity")
+		}
+	}
+	return s.db.Delete(&identity).Error
+}
+
+// register creates an account for an identity, named after the provider's
+// username or email.
+func (s *OIDCService) register(id *OIDCIdentity) (models.HostIdentity, error) {
+	base := id.Username
+	if base == "" {
+		base, _, _ = strings.Cut(id.Email, "@")
+	}
+	base = strings.Trim(usernameUnsafe.ReplaceAllString(base, "-"), "-.")
+	if len(base) < 3 {
+		base = "sso-" + base
+	}
+	base = truncate(base, 90)
+
+	var identity models.HostIdentity
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		username := base
+		for i := 2; ; i++ {
+			var count int64
+			tx.Model(&models.Host{}).Where("username = ?", username).Count(&count)
+			if count == 0 {
+				break
+			}
+			username = fmt.Sprintf("%s-%d", base, i)
+		}
+		host := models.Host{Username: username}
+		if err := tx.Create(&host).Error; err != nil {
+			return err
+		}
+		identity = models.HostIdentity{
+			HostID:  host.ID,
+			Issuer:  id.Issuer,
+			Subject: id.Subject,
+			Email:   truncate(id.Email, 255),
+		}
+		return tx.Create(&identity).Error
+	})
+	return identity, err
+}
+
+// finish uses up a sign-in state, redeems the code and verifies the ID token.
+func (s *OIDCService) finish(code, state string) (*OIDCIdentity, uint, error) {
+	if !s.Enabled() {
+		return nil, 0, ErrOIDCDisabled
+	}
+	var st models.OIDCLoginState
+	res := s.db.Clauses(clause.Returning{}).Where("state_hash = ?", sha256Hex(state)).Delete(&st)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+	if res.RowsAffected == 0 || time.Now().After(st.ExpiresAt) {
+		return nil, 0, ErrOIDCInvalidState
+	}
+
+	p, err := s.discover()
+	if err != nil {
+		return nil, 0, err
+	}
+	rawIDToken, err := s.exchange(p, code, st.Verifier)
+	if err != nil {
+		return nil, 0, err
+	}
+	id, err := s.verifyIDToken(rawIDToken, st.Nonce)
+	if err != nil {
+		return nil, 0, err
+	}
+	return id, st.LinkHostID, nil
+}
+
+// exchange redeems an authorization code at the token endpoint and returns
+// the ID token.
+func (s *OIDCService) exchange(p *oidcProvider, code, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {s.cfg.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	basic := s.cfg.ClientSecret != "" && !onlyPostAuth(p.TokenAuthMethods)
+	if !basic {
+		form.Set("client_id", s.cfg.ClientID)
+		if s.cfg.ClientSecret != "" {
+			form.Set("client_secret", s.cfg.ClientSecret)
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if basic {
+		req.SetBasicAuth(url.QueryEscape(s.cfg.ClientID), url.QueryEscape(s.cfg.ClientSecret))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrOIDCProvider, err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponse)).Decode(&body); err != nil {
+		return "", fmt.Errorf("%w: token response: %v", ErrOIDCProvider, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: %s %s", ErrOIDCProvider, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return "", fmt.Errorf("%w: no ID token in response", ErrOIDCProvider)
+	}
+	return body.IDToken, nil
+}
+
+// onlyPostAuth reports whether the provider takes the client secret in the
+// request body but not as HTTP basic auth, the default.
+func onlyPostAuth(methods []string) bool {
+	post, basic := false, false
+	for _, m := range methods {
+		post = post || m == "client_secret_post"
+		basic = basic || m == "client_secret_basic"
+	}
+	return post && !basic
+}
+
+func (s *OIDCService) verifyIDToken(raw, nonce string) (*OIDCIdentity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, s.keyFunc,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(s.cfg.Issuer),
+		jwt.WithAudience(s.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(oidcClockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid ID token: %v", ErrOIDCProvider, err)
+	}
+	if azp, ok := claims["azp"].(string); ok && azp != s.cfg.ClientID {
+		return nil, fmt.Errorf("%w: ID token was issued to another client", ErrOIDCProvider)
+	}
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return nil, fmt.Errorf("%w: ID token nonce does not match", ErrOIDCProvider)
+	}
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return nil, fmt.Errorf("%w: ID token has no subject", ErrOIDCProvider)
+	}
+
+	id := &OIDCIdentity{Issuer: s.cfg.Issuer, Subject: sub}
+	id.Email, _ = claims["email"].(string)
+	id.Username, _ = claims["preferred_username"].(string)
+	return id, nil
+}
+
+func (s *OIDCService) keyFunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	return s.key(kid)
+}
+
+// key returns the provider's signing key with the given ID, fetching the
+// key set again if it is not known yet.
+func (s *OIDCService) key(kid string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lookup := func() interface{} {
+		if kid == "" && len(s.keys) == 1 {
+			for _, k := range s.keys {
+				return k
+			}
+		}
+		return s.keys[kid]
+	}
+	if k := lookup(); k != nil {
+		return k, nil
+	}
+	if time.Since(s.keysAt) < oidcKeysRefresh {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	p, err := s.discoverLocked()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := s.fetchKeys(p.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	s.keys, s.keysAt = keys, time.Now()
+	if k := lookup(); k != nil {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (s *OIDCService) fetchKeys(jwksURI string) (map[string]interface{}, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := s.getJSON(jwksURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{})
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch k.Kty {
+		case "RSA":
+			n, errN := base64.RawURLEncoding.DecodeString(k.N)
+			e, errE := base64.RawURLEncoding.DecodeString(k.E)
+			if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+				continue
+			}
+			keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch k.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				continue
+			}
+			x, errX := base64.RawURLEncoding.DecodeString(k.X)
+			y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+			if errX != nil || errY != nil {
+				continue
+			}
+			keys[k.Kid] = &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: no usable signing keys", ErrOIDCProvider)
+	}
+	return keys, nil
+}
+
+func (s *OIDCService) discover() (*oidcProvider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.discoverLocked()
+}
+
+// discoverLocked loads the provider metadata from the issuer's discovery
+// document. The caller holds s.mu.
+func (s *OIDCService) discoverLocked() (*oidcProvider, error) {
+	if s.provider != nil && time.Since(s.providerAt) < oidcDiscoveryTTL {
+		return s.provider, nil
+	}
+	var p oidcProvider
+	if err := s.getJSON(strings.TrimRight(s.cfg.Issuer, "/")+"/.well-known/openid-configuration", &p); err != nil {
+		return nil, err
+	}
+	if p.Issuer != s.cfg.Issuer {
+		return nil, fmt.Errorf("%w: discovery document is for issuer %q", ErrOIDCProvider, p.Issuer)
+	}
+	if p.AuthorizationEndpoint == "" || p.TokenEndpoint == "" || p.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: discovery document is incomplete", ErrOIDCProvider)
+	}
+	s.provider, s.providerAt = &p, time.Now()
+	return &p, nil
+}
+
+func (s *OIDCService) getJSON(u string, dest interface{}) error {
+	resp, err := s.client.Get(u)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOIDCProvider, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s returned %d", ErrOIDCProvider, u, resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponse)).Decode(dest); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrOIDCProvider, u, err)
+	}
+	return nil
+}
+
+func randomURLToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
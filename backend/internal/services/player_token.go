@@ -0,0 +1,195 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"quiz-game-backend/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidPlayerToken = errors.New("invalid or expired player token")
+	ErrPlayerTokenRotated = errors.New("player token was replaced, reconnect again")
+)
+
+// PlayerToken is a signed credential for a web player, issued on join.
+type PlayerToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"token_expires_at"`
+}
+
+// playerClaims is what a player token carries. DeviceID identifies the
+// browser across rooms, for rejoining and bans; MemberID and Version bind
+// the token to one room membership until it is rotated.
+type playerClaims struct {
+	DeviceID string
+	MemberID uint
+	RoomID   uint
+	Version  int
+	Expired  bool
+}
+
+type PlayerTokenService struct {
+	db            *gorm.DB
+	secret        []byte
+	ttl           time.Duration
+	refreshWindow time.Duration
+}
+
+// NewPlayerTokenService signs player tokens with a key derived from the JWT
+// secret, so they can't be passed off as host tokens. Expired tokens can be
+// rotated on reconnect for refreshWindow after they expire.
+func NewPlayerTokenService(db *gorm.DB, jwtSecret string, ttl, refreshWindow time.Duration) *PlayerTokenService {
+	key := sha256.Sum256([]byte("player-token:" + jwtSecret))
+	return &PlayerTokenService{db: db, secret: key[:], ttl: ttl, refreshWindow: refreshWindow}
+}
+
+// DeviceID returns the device a previous token was issued to, so a returning
+// browser keeps its identity. Tokens that were rotated away get a new one.
+func (s *PlayerTokenService) DeviceID(token string) (string, error) {
+	if token != "" {
+		if claims, err := s.parse(token); err == nil && s.versionMatches(claims) {
+			return claims.DeviceID, nil
+		}
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// versionMatches rejects tokens of a membership that still exists but has
+// since been given a newer token.
+func (s *PlayerTokenService) versionMatches(claims *playerClaims) bool {
+	var member models.RoomMember
+	if err := s.db.Select("id", "token_version").First(&member, claims.MemberID).Error; err != nil {
+		return true
+	}
+	return member.TokenVersion == claims.Version
+}
+
+// Issue signs a token for a room member.
+func (s *PlayerTokenService) Issue(member *models.RoomMember) (*PlayerToken, error) {
+	expiresAt := time.Now().Add(s.ttl)
+	claims := jwt.MapClaims{
+		"typ": "player",
+		"did": member.WebToken,
+		"mid": member.ID,
+		"rid": member.RoomID,
+		"ver": member.TokenVersion,
+		"exp": expiresAt.Unix(),
+		"iat": time.Now().Unix(),
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return nil, err
+	}
+	return &PlayerToken{Token: signed, ExpiresAt: expiresAt}, nil
+}
+
+// Reissue signs a token for a member who joined the room again. The token
+// version goes up first, so tokens issued for the membership before stop
+// working.
+func (s *PlayerTokenService) Reissue(member *models.RoomMember) (*PlayerToken, error) {
+	if err := s.db.Model(member).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "token_version"}}}).
+		Update("token_version", gorm.Expr("token_version + 1")).Error; err != nil {
+		return nil, err
+	}
+	return s.Issue(member)
+}
+
+// Member checks a token and returns the member it was issued for, which has
+// to be in roomID.
+func (s *PlayerTokenService) Member(token string, roomID uint) (*models.RoomMember, error) {
+	claims, err := s.parse(token)
+	if err != nil || claims.Expired || claims.RoomID != roomID {
+		return nil, ErrInvalidPlayerToken
+	}
+	var member models.RoomMember
+	if err := s.db.Where("id = ? AND room_id = ?", claims.MemberID, roomID).First(&member).Error; err != nil {
+		return nil, errors.New("member not found")
+	}
+	if member.TokenVersion != claims.Version {
+		return nil, ErrPlayerTokenRotated
+	}
+	return &member, nil
+}
+
+// Rotate replaces a token, which may have expired within the refresh window,
+// with a new one. The old token stops working.
+func (s *PlayerTokenService) Rotate(token string, roomID uint) (*models.RoomMember, *PlayerToken, error) {
+	claims, err := s.parse(token)
+	if err != nil || claims.RoomID != roomID {
+		return nil, nil, ErrInvalidPlayerToken
+	}
+
+	var member models.RoomMember
+	if err := s.db.Where("id = ? AND room_id = ?", claims.MemberID, roomID).First(&member).Error; err != nil {
+		return nil, nil, errors.New("member not found")
+	}
+	// Only the holder of the current token can rotate it; the version check in
+	// the update makes concurrent rotations of one token fail but one.
+	res := s.db.Model(&models.RoomMember{}).
+		Where("id = ? AND token_version = ?", member.ID, claims.Version).
+		Update("token_version", gorm.Expr("token_version + 1"))
+	if res.Error != nil {
+		return nil, nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil, ErrPlayerTokenRotated
+	}
+	member.TokenVersion = claims.Version + 1
+
+	issued, err := s.Issue(&member)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &member, issued, nil
+}
+
+// parse checks the signature. Tokens past their expiry are returned marked
+// Expired while still inside the refresh window.
+func (s *PlayerTokenService) parse(token string) (*playerClaims, error) {
+	parsed, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return s.secret, nil
+	}, jwt.WithoutClaimsValidation())
+	if err != nil || !parsed.Valid {
+		return nil, ErrInvalidPlayerToken
+	}
+	mc, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || mc["typ"] != "player" {
+		return nil, ErrInvalidPlayerToken
+	}
+
+	did, _ := mc["did"].(string)
+	mid, _ := mc["mid"].(float64)
+	rid, _ := mc["rid"].(float64)
+	ver, _ := mc["ver"].(float64)
+	exp, _ := mc["exp"].(float64)
+	if did == "" || mid == 0 || exp == 0 {
+		return nil, ErrInvalidPlayerToken
+	}
+	expiresAt := time.Unix(int64(exp), 0)
+	if time.Since(expiresAt) > s.refreshWindow {
+		return nil, ErrInvalidPlayerToken
+	}
+	return &playerClaims{
+		DeviceID: did,
+		MemberID: uint(mid),
+		RoomID:   uint(rid),
+		Version:  int(ver),
+		Expired:  time.Now().After(expiresAt),
+	}, nil
+}
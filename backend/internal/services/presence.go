@@ -0,0 +1,240 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"quiz-game-backend/internal/models"
+)
+
+const (
+	PresenceOnline  = "online"
+	PresenceAway    = "away"
+	PresenceOffline = "offline"
+)
+
+// PresenceEvent is emitted whenever a room member or Telegram player changes status.
+type PresenceEvent struct {
+	RoomID     uint      `json:"room_id"`
+	MemberID   uint      `json:"member_id,omitempty"`
+	TelegramID int64     `json:"telegram_id,omitempty"`
+	Status     string    `json:"status"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+type presenceEntry struct {
+	roomID      uint
+	connections int
+	lastSeen    time.Time
+	status      string
+}
+
+// PresenceService tracks who is actually there. Web members are online while
+// their room WebSocket is open and active; Telegram players are online while
+// they keep interacting with the bot. Both go away after idleTimeout.
+type PresenceService struct {
+	idleTimeout time.Duration
+
+	mu        sync.Mutex
+	members   map[uint]*presenceEntry
+	telegram  map[int64]*presenceEntry
+	listeners []func(PresenceEvent)
+
+	stopCh chan struct{}
+}
+
+func NewPresenceService(idleTimeout time.Duration) *PresenceService {
+	return &PresenceService{
+		idleTimeout: idleTimeout,
+		members:     make(map[uint]*presenceEntry),
+		telegram:    make(map[int64]*presenceEntry),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// OnChange registers a listener for presence transitions.
+func (p *PresenceService) OnChange(fn func(PresenceEvent)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.listeners = append(p.listeners, fn)
+}
+
+func (p *PresenceService) Start() {
+	go p.sweepLoop()
+}
+
+func (p *PresenceService) Stop() {
+	close(p.stopCh)
+}
+
+func (p *PresenceService) MemberConnected(roomID, memberID uint) {
+	p.mu.Lock()
+	e := p.memberEntry(roomID, memberID)
+	e.connections++
+	e.lastSeen = time.Now()
+	ev := p.refresh(e, memberID, 0)
+	p.mu.Unlock()
+	p.emit(ev)
+}
+
+func (p *PresenceService) MemberDisconnected(roomID, memberID uint) {
+	p.mu.Lock()
+	e := p.memberEntry(roomID, memberID)
+	if e.connections > 0 {
+		e.connections--
+	}
+	ev := p.refresh(e, memberID, 0)
+	p.mu.Unlock()
+	p.emit(ev)
+}
+
+// TouchMember records activity from a web member (heartbeat, answer, etc).
+func (p *PresenceService) TouchMember(roomID, memberID uint) {
+	p.mu.Lock()
+	e := p.memberEntry(roomID, memberID)
+	e.lastSeen = time.Now()
+	ev := p.refresh(e, memberID, 0)
+	p.mu.Unlock()
+	p.emit(ev)
+}
+
+// TouchTelegram records a bot interaction. roomID may be 0 when the player
+// is not in a room yet; such transitions are tracked but not broadcast.
+func (p *PresenceService) TouchTelegram(roomID uint, telegramID int64) {
+	p.mu.Lock()
+	e, ok := p.telegram[telegramID]
+	if !ok {
+		e = &presenceEntry{status: PresenceOffline}
+		p.telegram[telegramID] = e
+	}
+	if roomID > 0 {
+		e.roomID = roomID
+	}
+	e.lastSeen = time.Now()
+	ev := p.refresh(e, 0, telegramID)
+	p.mu.Unlock()
+	p.emit(ev)
+}
+
+// ForgetMember drops presence data for a member who left the room.
+func (p *PresenceService) ForgetMember(memberID uint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.members, memberID)
+}
+
+func (p *PresenceService) MemberStatus(memberID uint) (string, *time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e, ok := p.members[memberID]
+	if !ok {
+		return PresenceOffline, nil
+	}
+	seen := e.lastSeen
+	return e.status, &seen
+}
+
+func (p *PresenceService) TelegramStatus(telegramID int64) (string, *time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e, ok := p.telegram[telegramID]
+	if !ok {
+		return PresenceOffline, nil
+	}
+	seen := e.lastSeen
+	return e.status, &seen
+}
+
+// IsParticipantActive reports whether a session participant is currently online.
+func (p *PresenceService) IsParticipantActive(participant models.Participant) bool {
+	if participant.TelegramID > 0 {
+		status, _ := p.TelegramStatus(participant.TelegramID)
+		return status == PresenceOnline
+	}
+	status, _ := p.MemberStatus(participant.MemberID)
+	return status == PresenceOnline
+}
+
+func (p *PresenceService) memberEntry(roomID, memberID uint) *presenceEntry {
+	e, ok := p.members[memberID]
+	if !ok {
+		e = &presenceEntry{roomID: roomID, status: PresenceOffline}
+		p.members[memberID] = e
+	}
+	return e
+}
+
+func (p *PresenceService) statusOf(e *presenceEntry, isTelegram bool, now time.Time) string {
+	if !isTelegram && e.connections == 0 {
+		return PresenceOffline
+	}
+	if now.Sub(e.lastSeen) > p.idleTimeout {
+		return PresenceAway
+	}
+	return PresenceOnline
+}
+
+// refresh recomputes the status and returns an event if it changed. Caller holds p.mu.
+func (p *PresenceService) refresh(e *presenceEntry, memberID uint, telegramID int64) *PresenceEvent {
+	status := p.statusOf(e, telegramID > 0, time.Now())
+	if status == e.status {
+		return nil
+	}
+	e.status = status
+	return &PresenceEvent{
+		RoomID:     e.roomID,
+		MemberID:   memberID,
+		TelegramID: telegramID,
+		Status:     status,
+		LastSeenAt: e.lastSeen,
+	}
+}
+
+func (p *PresenceService) emit(ev *PresenceEvent) {
+	if ev == nil {
+		return
+	}
+	p.mu.Lock()
+	listeners := append([]func(PresenceEvent){}, p.listeners...)
+	p.mu.Unlock()
+	for _, fn := range listeners {
+		fn(*ev)
+	}
+}
+
+func (p *PresenceService) sweepLoop() {
+	interval := p.idleTimeout / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ticker.C:
+			p.sweep()
+		}
+	}
+}
+
+func (p *PresenceService) sweep() {
+	var events []*PresenceEvent
+	p.mu.Lock()
+	for id, e := range p.members {
+		if ev := p.refresh(e, id, 0); ev != nil {
+			events = append(events, ev)
+		}
+	}
+	for id, e := range p.telegram {
+		if ev := p.refresh(e, 0, id); ev != nil {
+			events = append(events, ev)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, ev := range events {
+		p.emit(ev)
+	}
+}
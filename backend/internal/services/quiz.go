@@ -63,7 +63,7 @@ func (s *QuizService) CreateQuiz(hostID uint, title string) (*models.Quiz, error
 
 func (s *QuizService) GetQuizByID(quizID, hostID uint) (*models.Quiz, error) {
 	var quiz models.Quiz
-	err := s.db.Where("id = ? AND host_id = ?", quizID, hostID).
+	withContent := s.db.
 		Preload("Categories", func(db *gorm.DB) *gorm.DB {
 			return db.Order("order_num ASC")
 		}).
@@ -73,9 +73,8 @@ func (s *QuizService) GetQuizByID(quizID, hostID uint) (*models.Quiz, error) {
 		Preload("Categories.Questions.Options").
 		Preload("Categories.Questions.Images", func(db *gorm.DB) *gorm.DB {
 			return db.Order("order_num ASC")
-		}).
-		First(&quiz).Error
-	if err != nil {
+		})
+	if err := findInWorkspace(withContent, &quiz, quizID, hostID); err != nil {
 		return nil, errors.New("quiz not found")
 	}
 
@@ -94,7 +93,7 @@ func (s *QuizService) GetQuizByID(quizID, hostID uint) (*models.Quiz, error) {
 
 func (s *QuizService) UpdateQuiz(quizID, hostID uint, title, mode string) (*models.Quiz, error) {
 	var quiz models.Quiz
-	if err := s.db.Where("id = ? AND host_id = ?", quizID, hostID).First(&quiz).Error; err != nil {
+	if err := findInWorkspace(s.db, &quiz, quizID, hostID); err != nil {
 		return nil, errors.New("quiz not found")
 	}
 
@@ -109,16 +108,16 @@ func (s *QuizService) UpdateQuiz(quizID, hostID uint, title, mode string) (*mode
 }
 
 func (s *QuizService) DeleteQuiz(quizID, hostID uint) error {
-	result := s.db.Where("id = ? AND host_id = ?", quizID, hostID).Delete(&models.Quiz{})
-	if result.RowsAffected == 0 {
+	var quiz models.Quiz
+	if err := findInWorkspace(s.db, &quiz, quizID, hostID); err != nil {
 		return errors.New("quiz not found")
 	}
-	return result.Error
+	return s.db.Delete(&quiz).Error
 }
 
 func (s *QuizService) CreateCategory(quizID, hostID uint, title string) (*models.Category, error) {
 	var quiz models.Quiz
-	if err := s.db.Where("id = ? AND host_id = ?", quizID, hostID).First(&quiz).Error; err != nil {
+	if err := findInWorkspace(s.db, &quiz, quizID, hostID); err != nil {
 		return nil, errors.New("quiz not found")
 	}
 
@@ -143,7 +142,7 @@ func (s *QuizService) UpdateCategory(categoryID, hostID uint, title string) (*mo
 	}
 
 	var quiz models.Quiz
-	if err := s.db.Where("id = ? AND host_id = ?", cat.QuizID, hostID).First(&quiz).Error; err != nil {
+	if err := findInWorkspace(s.db, &quiz, cat.QuizID, hostID); err != nil {
 		return nil, errors.New("access denied")
 	}
 
@@ -159,7 +158,7 @@ func (s *QuizService) DeleteCategory(categoryID, hostID uint) error {
 	}
 
 	var quiz models.Quiz
-	if err := s.db.Where("id = ? AND host_id = ?", cat.QuizID, hostID).First(&quiz).Error; err != nil {
+	if err := findInWorkspace(s.db, &quiz, cat.QuizID, hostID); err != nil {
 		return errors.New("access denied")
 	}
 
@@ -171,7 +170,7 @@ func (s *QuizService) DeleteCategory(categoryID, hostID uint) error {
 
 func (s *QuizService) ReorderQuiz(quizID, hostID uint, order ReorderInput) error {
 	var quiz models.Quiz
-	if err := s.db.Where("id = ? AND host_id = ?", quizID, hostID).First(&quiz).Error; err != nil {
+	if err := findInWorkspace(s.db, &quiz, quizID, hostID); err != nil {
 		return errors.New("quiz not found")
 	}
 
@@ -208,7 +207,7 @@ type QuestionInput struct {
 
 func (s *QuizService) CreateQuestion(quizID, hostID uint, input QuestionInput) (*models.Question, error) {
 	var quiz models.Quiz
-	if err := s.db.Where("id = ? AND host_id = ?", quizID, hostID).First(&quiz).Error; err != nil {
+	if err := findInWorkspace(s.db, &quiz, quizID, hostID); err != nil {
 		return nil, errors.New("quiz not found")
 	}
 
@@ -265,7 +264,7 @@ func (s *QuizService) UpdateQuestion(questionID, hostID uint, input QuestionInpu
 	}
 
 	var quiz models.Quiz
-	if err := s.db.Where("id = ? AND host_id = ?", question.QuizID, hostID).First(&quiz).Error; err != nil {
+	if err := findInWorkspace(s.db, &quiz, question.QuizID, hostID); err != nil {
 		return nil, errors.New("quiz not found or access denied")
 	}
 
@@ -324,7 +323,7 @@ func (s *QuizService) DeleteQuestion(questionID, hostID uint) error {
 	}
 
 	var quiz models.Quiz
-	if err := s.db.Where("id = ? AND host_id = ?", question.QuizID, hostID).First(&quiz).Error; err != nil {
+	if err := findInWorkspace(s.db, &quiz, question.QuizID, hostID); err != nil {
 		return errors.New("quiz not found or access denied")
 	}
 
@@ -339,7 +338,7 @@ func (s *QuizService) AddQuestionImage(questionID, hostID uint, url, mediaType s
 	}
 
 	var quiz models.Quiz
-	if err := s.db.Where("id = ? AND host_id = ?", question.QuizID, hostID).First(&quiz).Error; err != nil {
+	if err := findInWorkspace(s.db, &quiz, question.QuizID, hostID); err != nil {
 		return nil, errors.New("access denied")
 	}
 
@@ -374,7 +373,7 @@ func (s *QuizService) DeleteQuestionImage(imageID, hostID uint) error {
 	}
 
 	var quiz models.Quiz
-	if err := s.db.Where("id = ? AND host_id = ?", question.QuizID, hostID).First(&quiz).Error; err != nil {
+	if err := findInWorkspace(s.db, &quiz, question.QuizID, hostID); err != nil {
 		return errors.New("access denied")
 	}
 
@@ -401,7 +400,7 @@ type ImportQuestion struct {
 
 func (s *QuizService) ImportQuestions(quizID, hostID uint, input ImportInput) (int, error) {
 	var quiz models.Quiz
-	if err := s.db.Where("id = ? AND host_id = ?", quizID, hostID).First(&quiz).Error; err != nil {
+	if err := findInWorkspace(s.db, &quiz, quizID, hostID); err != nil {
 		return 0, errors.New("quiz not found")
 	}
 
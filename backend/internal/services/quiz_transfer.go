@@ -0,0 +1,163 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type ExportOption struct {
+	Text            string `json:"text"`
+	IsCorrect       bool   `json:"is_correct"`
+	Color           string `json:"color,omitempty"`
+	CorrectPosition *int   `json:"correct_position,omitempty"`
+	MatchText       string `json:"match_text,omitempty"`
+}
+
+type ExportQuestion struct {
+	Text          string         `json:"text"`
+	Type          string         `json:"type,omitempty"`
+	CorrectNumber *float64       `json:"correct_number,omitempty"`
+	Tolerance     *float64       `json:"tolerance,omitempty"`
+	Options       []ExportOption `json:"options"`
+}
+
+type ExportCategory struct {
+	Title     string           `json:"title"`
+	Questions []ExportQuestion `json:"questions"`
+}
+
+// ExportData is a quiz in the JSON export format, also accepted for import.
+type ExportData struct {
+	Title      string           `json:"title"`
+	Categories []ExportCategory `json:"categories,omitempty"`
+	Questions  []ExportQuestion `json:"questions,omitempty"`
+}
+
+// ParseQuizFile parses a quiz export. Files named *.csv are read as CSV,
+// anything else as JSON.
+func ParseQuizFile(filename string, body []byte) (ExportData, error) {
+	if strings.HasSuffix(strings.ToLower(filename), ".csv") {
+		return ParseQuizCSV(body)
+	}
+	var data ExportData
+	if err := json.Unmarshal(body, &data); err != nil {
+		return ExportData{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return data, nil
+}
+
+// ParseQuizCSV reads the CSV export format: one question per row with up to
+// four options and the 1-based index of the correct one.
+func ParseQuizCSV(data []byte) (ExportData, error) {
+	r := csv.NewReader(strings.NewReader(string(data)))
+	records, err := r.ReadAll()
+	if err != nil {
+		return ExportData{}, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	if len(records) < 2 {
+		return ExportData{}, fmt.Errorf("CSV must have header + at least 1 row")
+	}
+
+	catMap := make(map[string]*ExportCategory)
+	var catOrder []string
+	var orphans []ExportQuestion
+
+	for _, row := range records[1:] {
+		if len(row) < 8 {
+			if len(row) >= 7 {
+				// Legacy format without type column
+				row = append([]string{row[0], row[1], ""}, row[2:]...)
+			} else {
+				continue
+			}
+		}
+
+		catTitle := strings.TrimSpace(row[0])
+		questionText := strings.TrimSpace(row[1])
+		if questionText == "" {
+			continue
+		}
+		qType := strings.TrimSpace(row[2])
+
+		correctIdx, _ := strconv.Atoi(row[7])
+
+		var opts []ExportOption
+		for i := 0; i < 4; i++ {
+			text := ""
+			if i+3 < len(row) {
+				text = strings.TrimSpace(row[3+i])
+			}
+			if text == "" {
+				continue
+			}
+			color := ""
+			if 8+i < len(row) {
+				color = strings.TrimSpace(row[8+i])
+			}
+			opts = append(opts, ExportOption{
+				Text:      text,
+				IsCorrect: (i + 1) == correctIdx,
+				Color:     color,
+			})
+		}
+
+		eq := ExportQuestion{Text: questionText, Type: qType, Options: opts}
+
+		if catTitle == "" {
+			orphans = append(orphans, eq)
+		} else {
+			if _, ok := catMap[catTitle]; !ok {
+				catMap[catTitle] = &ExportCategory{Title: catTitle}
+				catOrder = append(catOrder, catTitle)
+			}
+			catMap[catTitle].Questions = append(catMap[catTitle].Questions, eq)
+		}
+	}
+
+	result := ExportData{Questions: orphans}
+	for _, title := range catOrder {
+		result.Categories = append(result.Categories, *catMap[title])
+	}
+	return result, nil
+}
+
+// ImportInput converts an exported quiz into questions for ImportQuestions.
+func (data ExportData) ImportInput() ImportInput {
+	mapOptions := func(opts []ExportOption) []OptionInput {
+		var result []OptionInput
+		for _, o := range opts {
+			result = append(result, OptionInput{
+				Text: o.Text, IsCorrect: o.IsCorrect, Color: o.Color,
+				CorrectPosition: o.CorrectPosition, MatchText: o.MatchText,
+			})
+		}
+		return result
+	}
+
+	input := ImportInput{}
+	for _, cat := range data.Categories {
+		ic := ImportCategory{Title: cat.Title}
+		for _, q := range cat.Questions {
+			iq := ImportQuestion{
+				Text: q.Text, Type: q.Type,
+				CorrectNumber: q.CorrectNumber, Tolerance: q.Tolerance,
+				Options: mapOptions(q.Options),
+			}
+			ic.Questions = append(ic.Questions, iq)
+		}
+		input.Categories = append(input.Categories, ic)
+	}
+	for _, q := range data.Questions {
+		iq := ImportQuestion{
+			Text: q.Text, Type: q.Type,
+			CorrectNumber: q.CorrectNumber, Tolerance: q.Tolerance,
+			Options: mapOptions(q.Options),
+		}
+		input.Questions = append(input.Questions, iq)
+	}
+	return input
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"quiz-game-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// rateSweepInterval is how often expired counters are dropped.
+const rateSweepInterval = time.Minute
+
+// RateStore keeps fixed-window request counters.
+type RateStore interface {
+	// Hit adds a request to key's current window and returns the count so
+	// far and when the window ends.
+	Hit(key string, window time.Duration) (int, time.Time, error)
+	// Count returns key's count in the current window without adding to it.
+	Count(key string) (int, time.Time, error)
+}
+
+type rateCounter struct {
+	count     int
+	expiresAt time.Time
+}
+
+// MemoryRateStore counts requests in process memory. Each instance of the
+// server counts on its own.
+type MemoryRateStore struct {
+	mu        sync.Mutex
+	counters  map[string]*rateCounter
+	lastSweep time.Time
+}
+
+func NewMemoryRateStore() *MemoryRateStore {
+	return &MemoryRateStore{counters: make(map[string]*rateCounter), lastSweep: time.Now()}
+}
+
+func (s *MemoryRateStore) Hit(key string, window time.Duration) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) > rateSweepInterval {
+		for k, c := range s.counters {
+			if !now.Before(c.expiresAt) {
+				delete(s.counters, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	c, ok := s.counters[key]
+	if !ok || !now.Before(c.expiresAt) {
+		c = &rateCounter{expiresAt: now.Add(window)}
+		s.counters[key] = c
+	}
+	c.count++
+	return c.count, c.expiresAt, nil
+}
+
+func (s *MemoryRateStore) Count(key string) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.counters[key]
+	if !ok || !time.Now().Before(c.expiresAt) {
+		return 0, time.Time{}, nil
+	}
+	return c.count, c.expiresAt, nil
+}
+
+// PostgresRateStore shares counters between server instances through the
+// database.
+type PostgresRateStore struct {
+	db *gorm.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresRateStore(db *gorm.DB) *PostgresRateStore {
+	return &PostgresRateStore{db: db, lastSweep: time.Now()}
+}
+
+func (s *PostgresRateStore) Hit(key string, window time.Duration) (int, time.Time, error) {
+	now := time.Now()
+	s.sweep(now)
+
+	var count int
+	var expiresAt time.Time
+	err := s.db.Raw(`INSERT INTO rate_limit_counters (key, count, expires_at) VALUES (?, 1, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			count = CASE WHEN rate_limit_counters.expires_at <= ? THEN 1 ELSE rate_limit_counters.count + 1 END,
+			expires_at = CASE WHEN rate_limit_counters.expires_at <= ? THEN EXCLUDED.expires_at ELSE rate_limit_counters.expires_at END
+		RETURNING count, expires_at`, key, now.Add(window), now, now).Row().Scan(&count, &expiresAt)
+	return count, expiresAt, err
+}
+
+func (s *PostgresRateStore) Count(key string) (int, time.Time, error) {
+	var c models.RateLimitCounter
+	err := s.db.Where("key = ? AND expires_at > ?", key, time.Now()).Limit(1).Find(&c).Error
+	return c.Count, c.ExpiresAt, err
+}
+
+func (s *PostgresRateStore) sweep(now time.Time) {
+	s.mu.Lock()
+	due := now.Sub(s.lastSweep) > rateSweepInterval
+	if due {
+		s.lastSweep = now
+	}
+	s.mu.Unlock()
+	if due {
+		s.db.Where("expires_at <= ?", now).Delete(&models.RateLimitCounter{})
+	}
+}
+
+// RateLimiter applies limits on top of a RateStore. If the store fails,
+// requests are let through rather than locking everyone out.
+type RateLimiter struct {
+	store RateStore
+}
+
+func NewRateLimiter(store RateStore) *RateLimiter {
+	return &RateLimiter{store: store}
+}
+
+// Allow counts a request against key and reports whether it is within limit
+// requests per window. If not, retryAfter is when the window ends.
+func (l *RateLimiter) Allow(key string, limit int, window time.Duration) (bool, time.Duration) {
+	count, resetAt, err := l.store.Hit(key, window)
+	if err != nil {
+		log.Printf("rate limit %s: %v", key, err)
+		return true, 0
+	}
+	if count > limit {
+		return false, time.Until(resetAt)
+	}
+	return true, 0
+}
+
+// Blocked reports whether key has already reached limit in its current
+// window, without counting a request.
+func (l *RateLimiter) Blocked(key string, limit int) (bool, time.Duration) {
+	count, resetAt, err := l.store.Count(key)
+	if err != nil {
+		log.Printf("rate limit %s: %v", key, err)
+		return false, 0
+	}
+	if count >= limit {
+		return true, time.Until(resetAt)
+	}
+	return false, 0
+}
+
+// Record counts an event, such as a failed lookup, against key.
+func (l *RateLimiter) Record(key string, window time.Duration) {
+	if _, _, err := l.store.Hit(key, window); err != nil {
+		log.Printf("rate limit %s: %v", key, err)
+	}
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"quiz-game-backend/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// remoteMaxFailures wrong secrets in a row lock a Telegram account out of
+	// the remote for remoteLockout.
+	remoteMaxFailures = 5
+	remoteLockout     = 15 * time.Minute
+
+	// RemotePairingTTL is how long a pairing code from the web panel works.
+	RemotePairingTTL = 10 * time.Minute
+
+	// RemotePairingPrefix starts the /start payload of a pairing link.
+	RemotePairingPrefix = "pair-"
+)
+
+// pairingAlphabet leaves out characters that are easy to mistype.
+const pairingAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+var ErrRemoteWrongSecret = errors.New("wrong remote password or pairing code")
+
+// RemoteLockedError is returned while a Telegram account is locked out after
+// too many wrong secrets.
+type RemoteLockedError struct {
+	Until time.Time
+}
+
+func (e *RemoteLockedError) Error() string {
+	return "too many failed attempts, try again later"
+}
+
+type RemoteAuthService struct {
+	db *gorm.DB
+}
+
+func NewRemoteAuthService(db *gorm.DB) *RemoteAuthService {
+	return &RemoteAuthService{db: db}
+}
+
+// HashPassword checks a new remote password and returns what is stored for
+// it in hosts.remote_password; an empty one turns password login off.
+func (s *RemoteAuthService) HashPassword(password string) (string, error) {
+	password = strings.TrimSpace(password)
+	if password == "" {
+		return "", nil
+	}
+	if len(password) > 72 {
+		return "", errors.New("remote password must be at most 72 characters")
+	}
+	b, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// RevokePasswordLogins signs out the accounts that logged into the remote
+// with the password, in the transaction that changes it.
+func (s *RemoteAuthService) RevokePasswordLogins(tx *gorm.DB, hostID uint) error {
+	return tx.Where("host_id = ? AND method = ?", hostID, models.RemoteAuthPassword).
+		Delete(&models.RemoteAuthorization{}).Error
+}
+
+// HasPassword reports whether password login to the remote is set up.
+func (s *RemoteAuthService) HasPassword(hostID uint) bool {
+	var host models.Host
+	if err := s.db.Select("remote_password").First(&host, hostID).Error; err != nil {
+		return false
+	}
+	return host.RemotePassword != ""
+}
+
+// IsAuthorized reports whether a Telegram account may use the remote.
+func (s *RemoteAuthService) IsAuthorized(hostID uint, telegramID int64) bool {
+	var auth models.RemoteAuthorization
+	if err := s.db.Where("host_id = ? AND telegram_id = ?", hostID, telegramID).First(&auth).Error; err != nil {
+		return false
+	}
+	if time.Since(auth.LastUsedAt) > time.Minute {
+		s.db.Model(&auth).Update("last_used_at", time.Now())
+	}
+	return true
+}
+
+// Login authorizes a Telegram account with the remote password or a pairing
+// code. Repeated failures lock the account out for a while.
+func (s *RemoteAuthService) Login(hostID uint, telegramID int64, name, secret string) error {
+	secret = strings.TrimSpace(secret)
+
+	var attempt models.RemoteLoginAttempt
+	s.db.Where("host_id = ? AND telegram_id = ?", hostID, telegramID).
+		Attrs(models.RemoteLoginAttempt{HostID: hostID, TelegramID: telegramID}).
+		FirstOrCreate(&attempt)
+	if attempt.LockedUntil != nil && time.Now().Before(*attempt.LockedUntil) {
+		return &RemoteLockedError{Until: *attempt.LockedUntil}
+	}
+
+	method, ok := s.checkSecret(hostID, telegramID, secret)
+	if !ok {
+		updates := map[string]interface{}{"failures": attempt.Failures + 1, "locked_until": nil}
+		if attempt.Failures+1 >= remoteMaxFailures {
+			until := time.Now().Add(remoteLockout)
+			updates["failures"] = 0
+			updates["locked_until"] = until
+			s.db.Model(&attempt).Updates(updates)
+			return &RemoteLockedError{Until: until}
+		}
+		s.db.Model(&attempt).Updates(updates)
+		return ErrRemoteWrongSecret
+	}
+
+	s.db.Model(&attempt).Updates(map[string]interface{}{"failures": 0, "locked_until": nil})
+	return s.authorize(hostID, telegramID, name, method)
+}
+
+// checkSecret returns how the secret authorizes the account, if it does. A
+// matching pairing code is used up.
+func (s *RemoteAuthService) checkSecret(hostID uint, telegramID int64, secret string) (string, bool) {
+	if secret == "" {
+		return "", false
+	}
+
+	var host models.Host
+	if err := s.db.Select("remote_password").First(&host, hostID).Error; err == nil && host.RemotePassword != "" &&
+		bcrypt.CompareHashAndPassword([]byte(host.RemotePassword), []byte(secret)) == nil {
+		return models.RemoteAuthPassword, true
+	}
+
+	code := strings.ToUpper(strings.TrimPrefix(secret, RemotePairingPrefix))
+	res := s.db.Where("host_id = ? AND code_hash = ? AND expires_at > ?", hostID, hashPairingCode(code), time.Now()).
+		Delete(&models.RemotePairingCode{})
+	if res.Error == nil && res.RowsAffected > 0 {
+		return models.RemoteAuthPairing, true
+	}
+	return "", false
+}
+
+func (s *RemoteAuthService) authorize(hostID uint, telegramID int64, name, method string) error {
+	now := time.Now()
+	var auth models.RemoteAuthorization
+	err := s.db.Where("host_id = ? AND telegram_id = ?", hostID, telegramID).First(&auth).Error
+	if err == nil {
+		return s.db.Model(&auth).Updates(map[string]interface{}{"name": name, "method": method, "last_used_at": now}).Error
+	}
+	return s.db.Create(&models.RemoteAuthorization{
+		HostID:     hostID,
+		TelegramID: telegramID,
+		Name:       name,
+		Method:     method,
+		LastUsedAt: now,
+	}).Error
+}
+
+// CreatePairingCode issues a one-time code that logs a Telegram account into
+// the remote. The code is returned only here.
+func (s *RemoteAuthService) CreatePairingCode(hostID, createdBy uint) (string, time.Time, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
+	code := make([]byte, len(b))
+	for i, v := range b {
+		code[i] = pairingAlphabet[int(v)%len(pairingAlphabet)]
+	}
+
+	// Expired codes of the workspace are dropped as new ones are made.
+	s.db.Where("host_id = ? AND expires_at <= ?", hostID, time.Now()).Delete(&models.RemotePairingCode{})
+
+	expiresAt := time.Now().Add(RemotePairingTTL)
+	if err := s.db.Create(&models.RemotePairingCode{
+		HostID:    hostID,
+		CodeHash:  hashPairingCode(string(code)),
+		CreatedBy: createdBy,
+		ExpiresAt: expiresAt,
+	}).Error; err != nil {
+		return "", time.Time{}, err
+	}
+	return string(code), expiresAt, nil
+}
+
+func (s *RemoteAuthService) ListAuthorizations(hostID uint) ([]models.RemoteAuthorization, error) {
+	var auths []models.RemoteAuthorization
+	err := s.db.Where("host_id = ?", hostID).Order("created_at DESC").Find(&auths).Error
+	return auths, err
+}
+
+// Revoke takes remote access away from a Telegram account.
+func (s *RemoteAuthService) Revoke(authID, hostID uint) error {
+	res := s.db.Where("id = ? AND host_id = ?", authID, hostID).Delete(&models.RemoteAuthorization{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("remote authorization not found")
+	}
+	return nil
+}
+
+// HashPlaintextPasswords hashes remote passwords saved before they were
+// stored as bcrypt hashes.
+func (s *RemoteAuthService) HashPlaintextPasswords() (int, error) {
+	var hosts []models.Host
+	if err := s.db.Select("id", "remote_password").
+		Where("remote_password <> '' AND remote_password NOT LIKE '$2%'").Find(&hosts).Error; err != nil {
+		return 0, err
+	}
+	for _, host := range hosts {
+		hash, err := bcrypt.GenerateFromPassword([]byte(host.RemotePassword), bcrypt.DefaultCost)
+		if err != nil {
+			return 0, err
+		}
+		if err := s.db.Model(&models.Host{}).Where("id = ?", host.ID).Update("remote_password", string(hash)).Error; err != nil {
+			return 0, err
+		}
+	}
+	return len(hosts), nil
+}
+
+func hashPairingCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,9 +1,12 @@
 package services
 
 import (
+	crand "crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 
 	"quiz-game-backend/internal/models"
@@ -11,23 +14,51 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrRoomNotFound is returned for an unknown room code, or one whose room
+// has been closed.
+var ErrRoomNotFound = errors.New("room not found or closed")
+
 type RoomService struct {
-	db *gorm.DB
+	db        *gorm.DB
+	presence  *PresenceService
+	nicknames *NicknameFilter
+
+	kickMu       sync.Mutex
+	kickHandlers []func(KickResult)
 }
 
-func NewRoomService(db *gorm.DB) *RoomService {
-	return &RoomService{db: db}
+func NewRoomService(db *gorm.DB, presence *PresenceService, nicknames *NicknameFilter) *RoomService {
+	return &RoomService{db: db, presence: presence, nicknames: nicknames}
 }
 
 type RoomWithMembers struct {
 	models.Room
-	Members []models.RoomMember `json:"members"`
+	Members []MemberWithPresence `json:"members"`
+}
+
+type MemberWithPresence struct {
+	models.RoomMember
+	Status     string     `json:"status"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
 }
 
 func (s *RoomService) loadMembers(room *models.Room) *RoomWithMembers {
 	var members []models.RoomMember
 	s.db.Where("room_id = ?", room.ID).Order("joined_at ASC").Find(&members)
-	return &RoomWithMembers{Room: *room, Members: members}
+
+	result := make([]MemberWithPresence, len(members))
+	for i, m := range members {
+		result[i] = MemberWithPresence{RoomMember: m, Status: PresenceOffline}
+		if s.presence == nil {
+			continue
+		}
+		if m.TelegramID > 0 {
+			result[i].Status, result[i].LastSeenAt = s.presence.TelegramStatus(m.TelegramID)
+		} else {
+			result[i].Status, result[i].LastSeenAt = s.presence.MemberStatus(m.ID)
+		}
+	}
+	return &RoomWithMembers{Room: *room, Members: result}
 }
 
 func (s *RoomService) CreateRoom(hostID uint, mode string) (*models.Room, error) {
@@ -59,7 +90,7 @@ func (s *RoomService) GetRoomByCode(code string) (*RoomWithMembers, error) {
 	var room models.Room
 	if err := s.db.Where("code = ? AND status = ?", code, models.RoomStatusActive).
 		First(&room).Error; err != nil {
-		return nil, errors.New("room not found or closed")
+		return nil, ErrRoomNotFound
 	}
 	return s.loadMembers(&room), nil
 }
@@ -93,6 +124,14 @@ func (s *RoomService) JoinRoom(code, nickname, webToken string, telegramID int64
 	if err != nil {
 		return nil, err
 	}
+	if s.IsBanned(room.ID, webToken, telegramID) {
+		return nil, ErrBannedFromRoom
+	}
+	if nickname != "" {
+		if err := s.nicknames.Validate(nickname); err != nil {
+			return nil, err
+		}
+	}
 
 	var existing models.RoomMember
 	if webToken != "" {
@@ -130,28 +169,13 @@ func (s *RoomService) JoinRoom(code, nickname, webToken string, telegramID int64
 	return &RoomJoinResult{Room: room.Room, Member: member}, nil
 }
 
-func (s *RoomService) Reconnect(webToken, code string) (*RoomJoinResult, error) {
-	room, err := s.GetRoomByCode(code)
-	if err != nil {
-		return nil, err
-	}
-
-	var member models.RoomMember
-	if err := s.db.Where("room_id = ? AND web_token = ?", room.ID, webToken).
-		First(&member).Error; err != nil {
-		return nil, errors.New("member not found")
-	}
-
-	return &RoomJoinResult{Room: room.Room, Member: member, IsRejoin: true}, nil
-}
-
-func (s *RoomService) UpdateNickname(memberID uint, webToken, nickname string) (*models.RoomMember, error) {
+func (s *RoomService) UpdateNickname(memberID uint, nickname string) (*models.RoomMember, error) {
 	var member models.RoomMember
 	if err := s.db.First(&member, memberID).Error; err != nil {
 		return nil, errors.New("member not found")
 	}
-	if member.WebToken != webToken {
-		return nil, errors.New("unauthorized")
+	if err := s.nicknames.Validate(nickname); err != nil {
+		return nil, err
 	}
 	member.Nickname = nickname
 	s.db.Save(&member)
@@ -159,12 +183,15 @@ func (s *RoomService) UpdateNickname(memberID uint, webToken, nickname string) (
 }
 
 func (s *RoomService) RemoveMember(memberID uint) error {
+	if s.presence != nil {
+		s.presence.ForgetMember(memberID)
+	}
 	return s.db.Delete(&models.RoomMember{}, memberID).Error
 }
 
 func (s *RoomService) CloseRoom(roomID, hostID uint) error {
 	var room models.Room
-	if err := s.db.Where("id = ? AND host_id = ?", roomID, hostID).First(&room).Error; err != nil {
+	if err := findInWorkspace(s.db, &room, roomID, hostID); err != nil {
 		return errors.New("room not found")
 	}
 	room.Status = models.RoomStatusClosed
@@ -177,28 +204,57 @@ func (s *RoomService) CloseRoom(roomID, hostID uint) error {
 	return nil
 }
 
+// RotateDisplayToken issues a new token for the projector view, invalidating
+// the previous one, which is returned so projectors still using it can be
+// disconnected.
+func (s *RoomService) RotateDisplayToken(roomID, hostID uint) (token, previous string, err error) {
+	var room models.Room
+	if err := findInWorkspace(s.db, &room, roomID, hostID); err != nil {
+		return "", "", errors.New("room not found")
+	}
+	token, err = randomToken(24)
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.db.Model(&room).Update("display_token", token).Error; err != nil {
+		return "", "", err
+	}
+	return token, room.DisplayToken, nil
+}
+
+func (s *RoomService) GetRoomByDisplayToken(token string) (*RoomWithMembers, error) {
+	var room models.Room
+	if token == "" {
+		return nil, errors.New("room not found")
+	}
+	if err := s.db.Where("display_token = ? AND status = ?", token, models.RoomStatusActive).
+		First(&room).Error; err != nil {
+		return nil, errors.New("room not found or closed")
+	}
+	return s.loadMembers(&room), nil
+}
+
 func (s *RoomService) ListMembers(roomID uint) ([]models.RoomMember, error) {
 	var members []models.RoomMember
 	s.db.Where("room_id = ?", roomID).Order("joined_at ASC").Find(&members)
 	return members, nil
 }
 
-func (s *RoomService) GetMemberByToken(roomID uint, webToken string) (*models.RoomMember, error) {
+func (s *RoomService) GetMemberByTelegramID(roomID uint, telegramID int64) (*models.RoomMember, error) {
 	var member models.RoomMember
-	if err := s.db.Where("room_id = ? AND web_token = ?", roomID, webToken).
+	if err := s.db.Where("room_id = ? AND telegram_id = ?", roomID, telegramID).
 		First(&member).Error; err != nil {
 		return nil, errors.New("member not found")
 	}
 	return &member, nil
 }
 
-func (s *RoomService) GetMemberByTelegramID(roomID uint, telegramID int64) (*models.RoomMember, error) {
-	var member models.RoomMember
-	if err := s.db.Where("room_id = ? AND telegram_id = ?", roomID, telegramID).
-		First(&member).Error; err != nil {
-		return nil, errors.New("member not found")
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := crand.Read(buf); err != nil {
+		return "", err
 	}
-	return &member, nil
+	return hex.EncodeToString(buf), nil
 }
 
 func (s *RoomService) generateUniqueCode() string {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"sync"
 	"time"
 
 	"quiz-game-backend/internal/models"
@@ -14,12 +15,31 @@ import (
 )
 
 type SessionService struct {
-	db      *gorm.DB
-	scoring *ScoringService
+	db        *gorm.DB
+	scoring   *ScoringService
+	presence  *PresenceService
+	nicknames *NicknameFilter
+	events    *EventBus
+	audit     *AuditService
+
+	autoMu         sync.Mutex
+	pendingReveals map[uint]*time.Timer
 }
 
-func NewSessionService(db *gorm.DB, scoring *ScoringService) *SessionService {
-	return &SessionService{db: db, scoring: scoring}
+func NewSessionService(db *gorm.DB, scoring *ScoringService, presence *PresenceService, nicknames *NicknameFilter, events *EventBus, audit *AuditService) *SessionService {
+	s := &SessionService{
+		db:             db,
+		scoring:        scoring,
+		presence:       presence,
+		nicknames:      nicknames,
+		events:         events,
+		audit:          audit,
+		pendingReveals: make(map[uint]*time.Timer),
+	}
+	if presence != nil {
+		presence.OnChange(s.onPresenceChange)
+	}
+	return s
 }
 
 func (s *SessionService) getOrderedQuestions(quizID uint) []questionWithMeta {
@@ -60,7 +80,7 @@ func (s *SessionService) getOrderedQuestions(quizID uint) []questionWithMeta {
 
 func (s *SessionService) CreateSessionInRoom(roomID, quizID, hostID uint) (*models.Session, error) {
 	var quiz models.Quiz
-	if err := s.db.Where("id = ? AND host_id = ?", quizID, hostID).First(&quiz).Error; err != nil {
+	if err := findInWorkspace(s.db, &quiz, quizID, hostID); err != nil {
 		return nil, errors.New("quiz not found")
 	}
 
@@ -70,17 +90,6 @@ func (s *SessionService) CreateSessionInRoom(roomID, quizID, hostID uint) (*mode
 	}
 
 	questions := s.getOrderedQuestions(quizID)
-
-	if room.Mode == models.RoomModeBot {
-		var filtered []questionWithMeta
-		for _, q := range questions {
-			if q.Question.Type != models.QuestionTypeOrdering && q.Question.Type != models.QuestionTypeMatching {
-				filtered = append(filtered, q)
-			}
-		}
-		questions = filtered
-	}
-
 	if len(questions) == 0 {
 		return nil, errors.New("quiz must have at least one question")
 	}
@@ -118,6 +127,12 @@ func (s *SessionService) CreateSessionInRoom(roomID, quizID, hostID uint) (*mode
 	}
 
 	s.db.Preload("Quiz").First(&session, session.ID)
+	// Room clients render the lobby from the state carried by the event.
+	if state, err := s.GetSession(session.ID); err == nil {
+		s.publishState(EventQuizStarted, state)
+	} else {
+		s.events.Publish(SessionEvent{Type: EventQuizStarted, SessionID: session.ID, RoomID: roomID})
+	}
 	return &session, nil
 }
 
@@ -219,7 +234,7 @@ func (s *SessionService) GetSession(sessionID uint) (*SessionState, error) {
 
 func (s *SessionService) StartQuiz(sessionID, hostID uint) (*SessionState, error) {
 	var session models.Session
-	if err := s.db.Where("id = ? AND host_id = ?", sessionID, hostID).First(&session).Error; err != nil {
+	if err := findInWorkspace(s.db, &session, sessionID, hostID); err != nil {
 		return nil, errors.New("session not found")
 	}
 
@@ -236,12 +251,14 @@ func (s *SessionService) StartQuiz(sessionID, hostID uint) (*SessionState, error
 	session.CurrentQuestion = 1
 	s.db.Save(&session)
 
-	return s.GetSession(sessionID)
+	state, err := s.GetSession(sessionID)
+	s.publishState(EventQuestion, state)
+	return state, err
 }
 
 func (s *SessionService) NextQuestion(sessionID, hostID uint) (*SessionState, error) {
 	var session models.Session
-	if err := s.db.Where("id = ? AND host_id = ?", sessionID, hostID).First(&session).Error; err != nil {
+	if err := findInWorkspace(s.db, &session, sessionID, hostID); err != nil {
 		return nil, errors.New("session not found")
 	}
 
@@ -258,19 +275,23 @@ func (s *SessionService) NextQuestion(sessionID, hostID uint) (*SessionState, er
 	if session.CurrentQuestion >= len(questions) {
 		session.Status = models.SessionStatusFinished
 		s.db.Save(&session)
-		return s.GetSession(sessionID)
+		state, err := s.GetSession(sessionID)
+		s.publishState(EventFinished, state)
+		return state, err
 	}
 
 	session.CurrentQuestion++
 	session.Status = models.SessionStatusQuestion
 	s.db.Save(&session)
 
-	return s.GetSession(sessionID)
+	state, err := s.GetSession(sessionID)
+	s.publishState(EventQuestion, state)
+	return state, err
 }
 
 func (s *SessionService) RevealAnswer(sessionID, hostID uint) (*SessionState, error) {
 	var session models.Session
-	if err := s.db.Where("id = ? AND host_id = ?", sessionID, hostID).First(&session).Error; err != nil {
+	if err := findInWorkspace(s.db, &session, sessionID, hostID); err != nil {
 		return nil, errors.New("session not found")
 	}
 
@@ -296,22 +317,31 @@ func (s *SessionService) RevealAnswer(sessionID, hostID uint) (*SessionState, er
 	answers = s.scoring.CalculateScoresForType(answers, int(totalParticipants), &currentQ)
 
 	tx := s.db.Begin()
+	// Conditional transition so a manual reveal racing an auto-reveal scores only once.
+	res := tx.Model(&models.Session{}).
+		Where("id = ? AND status = ?", sessionID, models.SessionStatusQuestion).
+		Update("status", models.SessionStatusRevealed)
+	if res.Error != nil || res.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, errors.New("no active question to reveal")
+	}
 	for _, a := range answers {
 		tx.Model(&models.Answer{}).Where("id = ?", a.ID).Update("score", a.Score)
 		tx.Model(&models.Participant{}).Where("id = ?", a.ParticipantID).
 			Update("total_score", gorm.Expr("total_score + ?", a.Score))
 	}
-
-	session.Status = models.SessionStatusRevealed
-	tx.Save(&session)
 	tx.Commit()
 
-	return s.GetSession(sessionID)
+	s.cancelAutoReveal(sessionID)
+
+	state, err := s.GetSession(sessionID)
+	s.publishState(EventRevealed, state)
+	return state, err
 }
 
 func (s *SessionService) ForceFinish(sessionID, hostID uint) (*SessionState, error) {
 	var session models.Session
-	if err := s.db.Where("id = ? AND host_id = ?", sessionID, hostID).First(&session).Error; err != nil {
+	if err := findInWorkspace(s.db, &session, sessionID, hostID); err != nil {
 		return nil, errors.New("session not found")
 	}
 
@@ -322,7 +352,19 @@ func (s *SessionService) ForceFinish(sessionID, hostID uint) (*SessionState, err
 	session.Status = models.SessionStatusFinished
 	s.db.Save(&session)
 
-	return s.GetSession(sessionID)
+	state, err := s.GetSession(sessionID)
+	s.publishState(EventFinished, state)
+	return state, err
+}
+
+// CheckInWorkspace reports a session of another workspace as not found, for
+// reads that don't otherwise take the workspace.
+func (s *SessionService) CheckInWorkspace(sessionID, hostID uint) error {
+	var session models.Session
+	if err := findInWorkspace(s.db, &session, sessionID, hostID); err != nil {
+		return errors.New("session not found")
+	}
+	return nil
 }
 
 func (s *SessionService) GetLeaderboard(sessionID uint) ([]LeaderboardEntry, error) {
@@ -432,7 +474,11 @@ func (s *SessionService) SubmitAnswerByMember(sessionID, memberID, optionID uint
 		existingAnswer.OptionID = optionID
 		existingAnswer.IsCorrect = option.IsCorrect
 		existingAnswer.AnsweredAt = time.Now()
-		return s.db.Save(&existingAnswer).Error
+		if err := s.db.Save(&existingAnswer).Error; err != nil {
+			return err
+		}
+		s.answerRecorded(&session, currentQ.ID)
+		return nil
 	}
 
 	answer := models.Answer{
@@ -444,7 +490,11 @@ func (s *SessionService) SubmitAnswerByMember(sessionID, memberID, optionID uint
 		Score:         0,
 		AnsweredAt:    time.Now(),
 	}
-	return s.db.Create(&answer).Error
+	if err := s.db.Create(&answer).Error; err != nil {
+		return err
+	}
+	s.answerRecorded(&session, currentQ.ID)
+	return nil
 }
 
 type ComplexAnswerData struct {
@@ -498,7 +548,11 @@ func (s *SessionService) SubmitComplexAnswerByMember(sessionID, memberID uint, a
 		existingAnswer.IsCorrect = isCorrect
 		existingAnswer.AnsweredAt = time.Now()
 		existingAnswer.OptionID = 0
-		return s.db.Save(&existingAnswer).Error
+		if err := s.db.Save(&existingAnswer).Error; err != nil {
+			return err
+		}
+		s.answerRecorded(&session, currentQ.ID)
+		return nil
 	}
 
 	answer := models.Answer{
@@ -511,7 +565,11 @@ func (s *SessionService) SubmitComplexAnswerByMember(sessionID, memberID uint, a
 		Score:         0,
 		AnsweredAt:    time.Now(),
 	}
-	return s.db.Create(&answer).Error
+	if err := s.db.Create(&answer).Error; err != nil {
+		return err
+	}
+	s.answerRecorded(&session, currentQ.ID)
+	return nil
 }
 
 func (s *SessionService) SubmitComplexAnswerByTelegram(sessionID uint, telegramID int64, answerData json.RawMessage) error {
@@ -558,7 +616,11 @@ func (s *SessionService) SubmitComplexAnswerByTelegram(sessionID uint, telegramI
 		existingAnswer.IsCorrect = isCorrect
 		existingAnswer.AnsweredAt = time.Now()
 		existingAnswer.OptionID = 0
-		return s.db.Save(&existingAnswer).Error
+		if err := s.db.Save(&existingAnswer).Error; err != nil {
+			return err
+		}
+		s.answerRecorded(&session, currentQ.ID)
+		return nil
 	}
 
 	answer := models.Answer{
@@ -571,7 +633,11 @@ func (s *SessionService) SubmitComplexAnswerByTelegram(sessionID uint, telegramI
 		Score:         0,
 		AnsweredAt:    time.Now(),
 	}
-	return s.db.Create(&answer).Error
+	if err := s.db.Create(&answer).Error; err != nil {
+		return err
+	}
+	s.answerRecorded(&session, currentQ.ID)
+	return nil
 }
 
 func (s *SessionService) evaluateAnswer(qType string, q *models.Question, data *ComplexAnswerData) (bool, error) {
@@ -710,9 +776,9 @@ type questionWithMeta struct {
 
 type SessionState struct {
 	models.Session
-	TotalQuestions       int               `json:"total_questions"`
-	CurrentQuestionData *QuestionResponse  `json:"current_question_data,omitempty"`
-	AnswerCount         int                `json:"answer_count"`
+	TotalQuestions      int               `json:"total_questions"`
+	CurrentQuestionData *QuestionResponse `json:"current_question_data,omitempty"`
+	AnswerCount         int               `json:"answer_count"`
 }
 
 type QuestionResponse struct {
@@ -778,7 +844,7 @@ type JoinResult struct {
 
 func (s *SessionService) CreateSession(quizID, hostID uint) (*models.Session, error) {
 	var quiz models.Quiz
-	if err := s.db.Where("id = ? AND host_id = ?", quizID, hostID).First(&quiz).Error; err != nil {
+	if err := findInWorkspace(s.db, &quiz, quizID, hostID); err != nil {
 		return nil, errors.New("quiz not found")
 	}
 
@@ -810,12 +876,26 @@ func (s *SessionService) JoinSession(code string, telegramID int64, nickname str
 		return nil, errors.New("session not found or already finished")
 	}
 
+	if session.RoomID > 0 {
+		var banned int64
+		s.db.Model(&models.RoomBan{}).
+			Where("room_id = ? AND telegram_id = ?", session.RoomID, telegramID).
+			Count(&banned)
+		if banned > 0 {
+			return nil, ErrBannedFromRoom
+		}
+	}
+
 	var existing models.Participant
 	if err := s.db.Where("session_id = ? AND telegram_id = ?", session.ID, telegramID).
 		First(&existing).Error; err == nil {
 		return &JoinResult{SessionID: session.ID, Participant: existing, IsRejoin: true}, nil
 	}
 
+	if err := s.nicknames.Validate(nickname); err != nil {
+		return nil, err
+	}
+
 	if session.Status != models.SessionStatusWaiting && session.Status != models.SessionStatusQuestion {
 		return nil, errors.New("session is not accepting new participants")
 	}
@@ -868,7 +948,11 @@ func (s *SessionService) SubmitAnswer(sessionID uint, telegramID int64, optionID
 		existingAnswer.OptionID = optionID
 		existingAnswer.IsCorrect = option.IsCorrect
 		existingAnswer.AnsweredAt = time.Now()
-		return s.db.Save(&existingAnswer).Error
+		if err := s.db.Save(&existingAnswer).Error; err != nil {
+			return err
+		}
+		s.answerRecorded(&session, currentQ.ID)
+		return nil
 	}
 
 	answer := models.Answer{
@@ -880,7 +964,31 @@ func (s *SessionService) SubmitAnswer(sessionID uint, telegramID int64, optionID
 		Score:         0,
 		AnsweredAt:    time.Now(),
 	}
-	return s.db.Create(&answer).Error
+	if err := s.db.Create(&answer).Error; err != nil {
+		return err
+	}
+	s.answerRecorded(&session, currentQ.ID)
+	return nil
+}
+
+// CorrectOptionID returns the correct option of the current single-choice
+// question. Telegram quiz polls need it when the question is sent.
+func (s *SessionService) CorrectOptionID(sessionID uint) (uint, error) {
+	var session models.Session
+	if err := s.db.First(&session, sessionID).Error; err != nil {
+		return 0, errors.New("session not found")
+	}
+
+	questions := s.getOrderedQuestions(session.QuizID)
+	if session.CurrentQuestion < 1 || session.CurrentQuestion > len(questions) {
+		return 0, errors.New("invalid question state")
+	}
+	for _, o := range questions[session.CurrentQuestion-1].Question.Options {
+		if o.IsCorrect {
+			return o.ID, nil
+		}
+	}
+	return 0, errors.New("question has no correct option")
 }
 
 func (s *SessionService) GetParticipantResult(sessionID uint, telegramID int64) (*ParticipantResult, error) {
@@ -9,11 +9,12 @@ import (
 )
 
 type TelegramUserService struct {
-	db *gorm.DB
+	db        *gorm.DB
+	nicknames *NicknameFilter
 }
 
-func NewTelegramUserService(db *gorm.DB) *TelegramUserService {
-	return &TelegramUserService{db: db}
+func NewTelegramUserService(db *gorm.DB, nicknames *NicknameFilter) *TelegramUserService {
+	return &TelegramUserService{db: db, nicknames: nicknames}
 }
 
 func (s *TelegramUserService) GetOrCreate(telegramID int64, hostID uint, nickname string) (*models.TelegramUser, bool, error) {
@@ -47,6 +48,10 @@ func (s *TelegramUserService) UpdateNickname(telegramID int64, hostID uint, nick
 		return nil, err
 	}
 
+	if err := s.nicknames.Validate(nickname); err != nil {
+		return nil, err
+	}
+
 	user.Nickname = nickname
 	user.UpdatedAt = time.Now()
 	if err := s.db.Save(&user).Error; err != nil {
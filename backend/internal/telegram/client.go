@@ -2,37 +2,87 @@ package telegram
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
+// allowedUpdates are the update types the bot handles. Telegram keeps the
+// last list it was given, so both webhook and polling mode send it.
+var allowedUpdates = []string{"message", "callback_query", "poll_answer"}
+
+var errFileTooLarge = errors.New("file too large")
+
 type Client struct {
 	token      string
 	httpClient *http.Client
 	baseURL    string
+	fileURL    string
+	outbox     *Outbox
+	priority   Priority
 }
 
-func NewClient(token string) *Client {
+// NewClient creates a Bot API client. apiURL is the API root, normally
+// https://api.telegram.org. Requests to a chat go through the client's
+// rate-limited outbox; release it with Close.
+func NewClient(apiURL, token string) *Client {
 	return &Client{
 		token:      token,
 		httpClient: &http.Client{Timeout: 30 * time.Second},
-		baseURL:    fmt.Sprintf("https://api.telegram.org/bot%s", token),
+		baseURL:    fmt.Sprintf("%s/bot%s", strings.TrimRight(apiURL, "/"), token),
+		fileURL:    fmt.Sprintf("%s/file/bot%s", strings.TrimRight(apiURL, "/"), token),
+		outbox:     NewOutbox(),
+		priority:   PriorityReply,
 	}
 }
 
+// WithPriority returns a client sharing this one's outbox whose chat requests
+// are queued at priority p.
+func (c *Client) WithPriority(p Priority) *Client {
+	cp := *c
+	cp.priority = p
+	return &cp
+}
+
+func (c *Client) Metrics() OutboxMetrics {
+	return c.outbox.Metrics()
+}
+
+func (c *Client) Close() {
+	c.outbox.Close()
+}
+
 func (c *Client) call(method string, payload interface{}) (json.RawMessage, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("marshal: %w", err)
 	}
 
-	resp, err := c.httpClient.Post(c.baseURL+"/"+method, "application/json", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("http: %w", err)
+	post := func() (json.RawMessage, error) {
+		resp, err := c.httpClient.Post(c.baseURL+"/"+method, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("http: %w", err)
+		}
+		return decodeResponse(resp)
+	}
+
+	// Callback answers and webhook management don't count against chat limits.
+	var target struct {
+		ChatID int64 `json:"chat_id"`
 	}
+	json.Unmarshal(body, &target)
+	if target.ChatID == 0 || method == "answerCallbackQuery" {
+		return post()
+	}
+	return c.outbox.Do(target.ChatID, c.priority, 1, post)
+}
+
+func decodeResponse(resp *http.Response) (json.RawMessage, error) {
 	defer resp.Body.Close()
 
 	data, err := io.ReadAll(resp.Body)
@@ -46,7 +96,11 @@ func (c *Client) call(method string, payload interface{}) (json.RawMessage, erro
 	}
 
 	if !apiResp.OK {
-		return nil, fmt.Errorf("telegram: %s", apiResp.Description)
+		apiErr := &APIError{Code: apiResp.ErrorCode, Description: apiResp.Description}
+		if apiResp.Parameters != nil {
+			apiErr.RetryAfter = apiResp.Parameters.RetryAfter
+		}
+		return nil, apiErr
 	}
 
 	return apiResp.Result, nil
@@ -97,6 +151,45 @@ func (c *Client) EditMessageText(chatID, messageID int64, text, parseMode string
 	return err
 }
 
+// SendQuizPoll sends a non-anonymous quiz poll and returns its message and
+// poll IDs. correct is the index of the right option.
+func (c *Client) SendQuizPoll(chatID int64, question string, options []string, correct int, replyMarkup interface{}) (int64, string, error) {
+	req := SendPollRequest{
+		ChatID:          chatID,
+		Question:        question,
+		IsAnonymous:     false,
+		Type:            "quiz",
+		CorrectOptionID: correct,
+	}
+	for _, o := range options {
+		req.Options = append(req.Options, InputPollOption{Text: o})
+	}
+
+	if replyMarkup != nil {
+		rm, err := json.Marshal(replyMarkup)
+		if err != nil {
+			return 0, "", err
+		}
+		req.ReplyMarkup = rm
+	}
+
+	result, err := c.call("sendPoll", req)
+	if err != nil {
+		return 0, "", err
+	}
+
+	var msg PollMessageResult
+	json.Unmarshal(result, &msg)
+	return msg.MessageID, msg.Poll.ID, nil
+}
+
+// StopPoll closes a poll and removes the inline keyboard under it.
+func (c *Client) StopPoll(chatID, messageID int64) error {
+	rm, _ := json.Marshal(InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{}})
+	_, err := c.call("stopPoll", StopPollRequest{ChatID: chatID, MessageID: messageID, ReplyMarkup: rm})
+	return err
+}
+
 func (c *Client) AnswerCallbackQuery(callbackID, text string, showAlert bool) error {
 	req := AnswerCallbackQueryRequest{
 		CallbackQueryID: callbackID,
@@ -108,7 +201,7 @@ func (c *Client) AnswerCallbackQuery(callbackID, text string, showAlert bool) er
 }
 
 func (c *Client) SetWebhook(url, secretToken string) error {
-	req := SetWebhookRequest{URL: url, SecretToken: secretToken}
+	req := SetWebhookRequest{URL: url, SecretToken: secretToken, AllowedUpdates: allowedUpdates}
 	_, err := c.call("setWebhook", req)
 	return err
 }
@@ -122,6 +215,74 @@ func (c *Client) DeleteMessage(chatID, messageID int64) error {
 	return err
 }
 
+// DownloadFile fetches a file users sent to the bot. Files larger than limit
+// bytes are rejected.
+func (c *Client) DownloadFile(fileID string, limit int64) ([]byte, error) {
+	result, err := c.call("getFile", struct {
+		FileID string `json:"file_id"`
+	}{FileID: fileID})
+	if err != nil {
+		return nil, err
+	}
+	var file File
+	if err := json.Unmarshal(result, &file); err != nil || file.FilePath == "" {
+		return nil, fmt.Errorf("telegram: file %s has no path", fileID)
+	}
+
+	resp, err := c.httpClient.Get(c.fileURL + "/" + file.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("http: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("telegram: download %s: %s", file.FilePath, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, errFileTooLarge
+	}
+	return data, nil
+}
+
+// GetUpdates long-polls for updates starting at offset. timeout is in seconds;
+// the request is aborted when ctx is cancelled.
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout int) ([]Update, error) {
+	body, err := json.Marshal(GetUpdatesRequest{
+		Offset:         offset,
+		Timeout:        timeout,
+		AllowedUpdates: allowedUpdates,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/getUpdates", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// The shared client's timeout would cut a long poll short.
+	resp, err := longPollClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http: %w", err)
+	}
+	result, err := decodeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []Update
+	if err := json.Unmarshal(result, &updates); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+	return updates, nil
+}
+
 func (c *Client) DeleteWebhook() error {
 	_, err := c.call("deleteWebhook", struct{}{})
 	return err
@@ -0,0 +1,261 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"quiz-game-backend/internal/i18n"
+	"quiz-game-backend/internal/services"
+)
+
+// complexActions maps ordering/matching callback prefixes to the action they trigger.
+var complexActions = map[string]string{
+	"ord:":         "pick",
+	"ordundo:":     "undo",
+	"ordsubmit:":   "submit",
+	"match:":       "pick",
+	"matchundo:":   "undo",
+	"matchsubmit:": "submit",
+}
+
+// buildQuestionData converts the current question into the per-user FSM form.
+// Ordering options and matching choices are sorted by text so the stored
+// order doesn't hint at the answer.
+func buildQuestionData(sessionID uint, qr *services.QuestionResponse) *QuestionData {
+	qType := qr.Type
+	if qType == "" {
+		qType = "single_choice"
+	}
+	qd := &QuestionData{Text: qr.Text, Type: qType, SessionID: sessionID}
+	for _, o := range qr.Options {
+		qd.Options = append(qd.Options, QuestionOption{ID: o.ID, Text: o.Text, MatchText: o.MatchText})
+	}
+
+	switch qType {
+	case "ordering":
+		sort.SliceStable(qd.Options, func(i, j int) bool { return qd.Options[i].Text < qd.Options[j].Text })
+	case "matching":
+		for _, o := range qd.Options {
+			qd.MatchChoices = append(qd.MatchChoices, o.MatchText)
+		}
+		sort.Strings(qd.MatchChoices)
+	}
+	return qd
+}
+
+// questionText renders the question header and text, followed by an optional note.
+func questionText(lang string, qd *QuestionData, current, total int, note string) string {
+	text := i18n.T(lang, "question.header", current, total) + "\n\n" + qd.Text
+	if note != "" {
+		text += "\n\n" + note
+	}
+	return text
+}
+
+// questionMessage renders a fresh question with nothing selected yet.
+func questionMessage(lang string, qd *QuestionData, current, total int) (string, interface{}) {
+	switch qd.Type {
+	case "ordering":
+		return orderingText(lang, qd, nil, current, total), OrderingKeyboard(lang, qd.SessionID, qd.Options, nil)
+	case "matching":
+		return matchingText(lang, qd, nil, current, total), MatchingKeyboard(lang, qd.SessionID, qd.MatchChoices, nil, len(qd.Options))
+	case "multiple_choice":
+		text := questionText(lang, qd, current, total, i18n.T(lang, "question.multi"))
+		return text, MultiChoiceKeyboard(lang, qd.SessionID, qd.Options, nil)
+	case "numeric":
+		return questionText(lang, qd, current, total, i18n.T(lang, "question.numeric")), nil
+	default:
+		return questionText(lang, qd, current, total, ""), AnswerKeyboard(qd.SessionID, qd.Options, 0)
+	}
+}
+
+func orderingDoneHint(lang string) string {
+	return "\n" + i18n.T(lang, "answer.ordering_done")
+}
+
+func matchingDoneHint(lang string) string {
+	return "\n" + i18n.T(lang, "answer.matching_done")
+}
+
+func orderingText(lang string, qd *QuestionData, order []uint, current, total int) string {
+	var b strings.Builder
+	b.WriteString(questionText(lang, qd, current, total, ""))
+	b.WriteString("\n\n")
+	if len(order) == 0 {
+		b.WriteString(i18n.T(lang, "question.ordering"))
+		return b.String()
+	}
+
+	texts := make(map[uint]string, len(qd.Options))
+	for _, o := range qd.Options {
+		texts[o.ID] = o.Text
+	}
+	for i, id := range order {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, texts[id])
+	}
+	if len(order) == len(qd.Options) {
+		b.WriteString(orderingDoneHint(lang))
+	}
+	return b.String()
+}
+
+func matchingText(lang string, qd *QuestionData, picks []int, current, total int) string {
+	var b strings.Builder
+	b.WriteString(questionText(lang, qd, current, total, ""))
+	b.WriteString("\n\n")
+	for i, idx := range picks {
+		fmt.Fprintf(&b, "%s → %s\n", qd.Options[i].Text, qd.MatchChoices[idx])
+	}
+	if len(picks) < len(qd.Options) {
+		if len(picks) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(i18n.T(lang, "question.match_to", qd.Options[len(picks)].Text))
+	} else {
+		b.WriteString(matchingDoneHint(lang))
+	}
+	return b.String()
+}
+
+// complexCallback validates a complex-question callback and returns the session
+// ID, the numeric argument and the user's state.
+func (h *UpdateHandler) complexCallback(cb *CallbackQuery, qType string) (uint, int, *UserState, bool) {
+	us := h.state.Get(cb.From.ID)
+	if us.State != StateInSession {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(cb.From.ID, "answer.not_in_session"), true)
+		return 0, 0, nil, false
+	}
+
+	parts := strings.Split(cb.Data, ":")
+	if len(parts) != 3 {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(cb.From.ID, "error.bad_data"), true)
+		return 0, 0, nil, false
+	}
+	sessionID, _ := strconv.ParseUint(parts[1], 10, 64)
+	arg, _ := strconv.Atoi(parts[2])
+
+	if us.QuestionData == nil || us.QuestionData.Type != qType || us.QuestionData.SessionID != uint(sessionID) {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(cb.From.ID, "question.closed"), true)
+		return 0, 0, nil, false
+	}
+	return uint(sessionID), arg, us, true
+}
+
+func (h *UpdateHandler) handleOrderingCallback(cb *CallbackQuery, action string) {
+	sessionID, arg, us, ok := h.complexCallback(cb, "ordering")
+	if !ok {
+		return
+	}
+	qd := us.QuestionData
+	order := append([]uint{}, us.OrderIDs...)
+	lang := h.state.Locale(cb.From.ID)
+
+	switch action {
+	case "pick":
+		optionID := uint(arg)
+		valid := false
+		for _, o := range qd.Options {
+			if o.ID == optionID {
+				valid = true
+			}
+		}
+		for _, id := range order {
+			if id == optionID {
+				valid = false
+			}
+		}
+		if !valid {
+			h.client.AnswerCallbackQuery(cb.ID, "", false)
+			return
+		}
+		order = append(order, optionID)
+	case "undo":
+		if len(order) > 0 {
+			order = order[:len(order)-1]
+		}
+	case "submit":
+		if len(order) != len(qd.Options) {
+			h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "answer.ordering_short"), true)
+			return
+		}
+		answerJSON, _ := json.Marshal(map[string]interface{}{"order": order})
+		summary := strings.TrimSuffix(orderingText(lang, qd, order, us.CurrentQNum, us.TotalQuestions), orderingDoneHint(lang))
+		h.submitComplex(cb, sessionID, answerJSON, summary)
+		return
+	}
+
+	h.state.UpdateField(cb.From.ID, func(s *UserState) { s.OrderIDs = order })
+	if cb.Message != nil {
+		h.client.EditMessageText(cb.Message.Chat.ID, cb.Message.MessageID,
+			orderingText(lang, qd, order, us.CurrentQNum, us.TotalQuestions), "HTML",
+			OrderingKeyboard(lang, sessionID, qd.Options, order))
+	}
+	h.client.AnswerCallbackQuery(cb.ID, "", false)
+}
+
+func (h *UpdateHandler) handleMatchingCallback(cb *CallbackQuery, action string) {
+	sessionID, arg, us, ok := h.complexCallback(cb, "matching")
+	if !ok {
+		return
+	}
+	qd := us.QuestionData
+	picks := append([]int{}, us.MatchPicks...)
+	lang := h.state.Locale(cb.From.ID)
+
+	switch action {
+	case "pick":
+		if arg < 0 || arg >= len(qd.MatchChoices) || len(picks) >= len(qd.Options) {
+			h.client.AnswerCallbackQuery(cb.ID, "", false)
+			return
+		}
+		for _, idx := range picks {
+			if idx == arg {
+				h.client.AnswerCallbackQuery(cb.ID, "", false)
+				return
+			}
+		}
+		picks = append(picks, arg)
+	case "undo":
+		if len(picks) > 0 {
+			picks = picks[:len(picks)-1]
+		}
+	case "submit":
+		if len(picks) != len(qd.Options) {
+			h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "answer.matching_short"), true)
+			return
+		}
+		pairs := make(map[string]string, len(picks))
+		for i, idx := range picks {
+			pairs[strconv.FormatUint(uint64(qd.Options[i].ID), 10)] = qd.MatchChoices[idx]
+		}
+		answerJSON, _ := json.Marshal(map[string]interface{}{"pairs": pairs})
+		summary := strings.TrimSuffix(matchingText(lang, qd, picks, us.CurrentQNum, us.TotalQuestions), matchingDoneHint(lang))
+		h.submitComplex(cb, sessionID, answerJSON, summary)
+		return
+	}
+
+	h.state.UpdateField(cb.From.ID, func(s *UserState) { s.MatchPicks = picks })
+	if cb.Message != nil {
+		h.client.EditMessageText(cb.Message.Chat.ID, cb.Message.MessageID,
+			matchingText(lang, qd, picks, us.CurrentQNum, us.TotalQuestions), "HTML",
+			MatchingKeyboard(lang, sessionID, qd.MatchChoices, picks, len(qd.Options)))
+	}
+	h.client.AnswerCallbackQuery(cb.ID, "", false)
+}
+
+func (h *UpdateHandler) submitComplex(cb *CallbackQuery, sessionID uint, answerJSON []byte, summary string) {
+	if err := h.sessionSvc.SubmitComplexAnswerByTelegram(sessionID, cb.From.ID, answerJSON); err != nil {
+		h.client.AnswerCallbackQuery(cb.ID, h.errText(cb.From.ID, err), true)
+		return
+	}
+
+	if cb.Message != nil {
+		h.client.EditMessageText(cb.Message.Chat.ID, cb.Message.MessageID,
+			summary+"\n\n"+h.tr(cb.From.ID, "answer.accepted_note"), "HTML", nil)
+	}
+
+	h.client.AnswerCallbackQuery(cb.ID, h.tr(cb.From.ID, "answer.accepted"), false)
+}
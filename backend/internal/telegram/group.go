@@ -0,0 +1,489 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"quiz-game-backend/internal/i18n"
+	"quiz-game-backend/internal/models"
+	"quiz-game-backend/internal/services"
+)
+
+// groupEditInterval is the minimum gap between answer-counter edits of a group
+// question; Telegram allows about 20 messages a minute per group.
+const groupEditInterval = 3 * time.Second
+
+// GroupInfo is a group chat playing a session. Every question is one shared
+// message that is edited as answers come in and again on reveal.
+type GroupInfo struct {
+	ChatID    int64
+	BotLink   string // https://t.me/<bot>, for questions answered in private
+	MessageID int64  // current question message, 0 before the first one
+	Text      string // body of the current message without the answer counter
+	Keyboard  interface{}
+	Question  int // question number the message belongs to
+	Answers   int
+
+	lastEdit time.Time
+	pending  bool
+}
+
+// isGroupCallback reports whether callback data belongs to a group quiz.
+func isGroupCallback(data string) bool {
+	return strings.HasPrefix(data, "grp:") || strings.HasPrefix(data, "gans:") || strings.HasPrefix(data, "gjoin:")
+}
+
+// ─── Group chat handler ───
+
+// handleGroupMessage answers commands sent in a group. Everything else in the
+// group is chatter and is ignored.
+func (h *UpdateHandler) handleGroupMessage(msg *Message) {
+	lang := h.state.DefaultLocale()
+	chatID := msg.Chat.ID
+
+	switch {
+	case isCommand(msg, "quiz"):
+		if !h.isHostAuthorized(msg.From.ID) {
+			h.client.SendMessage(chatID, i18n.T(lang, "group.only_host"), "", nil)
+			return
+		}
+		h.showGroupQuizPicker(chatID, 0, 0)
+	case isCommand(msg, "start"):
+		h.client.SendMessage(chatID, i18n.T(lang, "group.help"), "HTML", nil)
+	}
+}
+
+// isHostAuthorized reports whether a Telegram user may use the host remote.
+func (h *UpdateHandler) isHostAuthorized(userID int64) bool {
+	return h.remoteAuth.IsAuthorized(h.hostID, userID)
+}
+
+func (h *UpdateHandler) showGroupQuizPicker(chatID int64, page int, editMsgID int64) {
+	lang := h.state.DefaultLocale()
+	items, count, page, totalPages := h.quizPage(lang, page)
+	if count == 0 {
+		h.client.SendMessage(chatID, i18n.T(lang, "group.no_quizzes"), "", nil)
+		return
+	}
+
+	text := i18n.T(lang, "group.pick_quiz")
+	kb := GroupQuizPickKeyboard(items, page, totalPages)
+	if editMsgID > 0 {
+		if err := h.client.EditMessageText(chatID, editMsgID, text, "HTML", kb); err == nil {
+			return
+		}
+	}
+	h.client.SendMessage(chatID, text, "HTML", kb)
+}
+
+func groupLobbyText(lang string, s *services.SessionState) string {
+	return i18n.T(lang, "group.lobby", s.Quiz.Title, s.TotalQuestions, len(s.Participants))
+}
+
+func (h *UpdateHandler) handleGroupCallback(cb *CallbackQuery) {
+	if cb.Message == nil {
+		h.client.AnswerCallbackQuery(cb.ID, "", false)
+		return
+	}
+	switch {
+	case strings.HasPrefix(cb.Data, "gans:"):
+		h.handleGroupAnswer(cb)
+	case strings.HasPrefix(cb.Data, "gjoin:"):
+		h.handleGroupJoin(cb)
+	default:
+		h.handleGroupHostAction(cb)
+	}
+}
+
+// handleGroupHostAction runs the host buttons of a group quiz: picking a quiz,
+// starting, revealing, moving on and finishing.
+func (h *UpdateHandler) handleGroupHostAction(cb *CallbackQuery) {
+	userID := cb.From.ID
+	chatID := cb.Message.Chat.ID
+	lang := h.state.DefaultLocale()
+
+	parts := strings.Split(cb.Data, ":")
+	if len(parts) != 3 {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "error.bad_data"), true)
+		return
+	}
+	action := parts[1]
+	id, _ := strconv.ParseUint(parts[2], 10, 64)
+
+	if action == "noop" {
+		h.client.AnswerCallbackQuery(cb.ID, "", false)
+		return
+	}
+	if !h.isHostAuthorized(userID) {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "group.only_host"), true)
+		return
+	}
+
+	switch action {
+	case "page":
+		h.client.AnswerCallbackQuery(cb.ID, "", false)
+		h.showGroupQuizPicker(chatID, int(id), cb.Message.MessageID)
+		return
+
+	case "quiz":
+		session, err := h.sessionSvc.CreateSession(uint(id), h.hostID)
+		if err != nil {
+			h.client.AnswerCallbackQuery(cb.ID, h.errText(userID, err), true)
+			return
+		}
+		h.audit.Record(h.hostID, services.TelegramActor(userID), services.AuditSessionCreate, services.AuditTargetSession, session.ID, nil, h.audit.SessionSummary(session.ID))
+		sessState, err := h.sessionSvc.GetSession(session.ID)
+		if err != nil {
+			h.client.AnswerCallbackQuery(cb.ID, h.errText(userID, err), true)
+			return
+		}
+		h.trackGroup(sessState, chatID)
+		h.client.AnswerCallbackQuery(cb.ID, "", false)
+		h.client.EditMessageText(chatID, cb.Message.MessageID, groupLobbyText(lang, sessState), "HTML", GroupLobbyKeyboard(lang, session.ID))
+		return
+	}
+
+	sessionID := uint(id)
+	sessState, err := h.sessionSvc.GetSession(sessionID)
+	if err != nil || sessState.HostID != h.hostID {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "remote.no_session"), true)
+		return
+	}
+	// Registers the group again if the tracker lost it, e.g. after a restart.
+	h.trackGroup(sessState, chatID)
+
+	switch action {
+	case "start", "next":
+		_, err = h.sessionChange(userID, services.AuditSessionNext, sessionID, h.sessionSvc.NextQuestion)
+	case "reveal":
+		_, err = h.sessionChange(userID, services.AuditSessionReveal, sessionID, h.sessionSvc.RevealAnswer)
+	case "finish":
+		_, err = h.sessionChange(userID, services.AuditSessionFinish, sessionID, h.sessionSvc.ForceFinish)
+	default:
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "error.unknown_cmd"), true)
+		return
+	}
+	if err != nil {
+		h.client.AnswerCallbackQuery(cb.ID, h.errText(userID, err), true)
+		return
+	}
+	h.client.AnswerCallbackQuery(cb.ID, "", false)
+
+	// The lobby stays in the chat as a header without its buttons.
+	switch {
+	case action == "start":
+		h.client.EditMessageText(chatID, cb.Message.MessageID, groupLobbyText(lang, sessState), "HTML", nil)
+	case action == "finish" && sessState.Status == "waiting":
+		h.client.EditMessageText(chatID, cb.Message.MessageID, i18n.T(lang, "group.cancelled", sessState.Quiz.Title), "HTML", nil)
+	}
+}
+
+func (h *UpdateHandler) trackGroup(sessState *services.SessionState, chatID int64) {
+	var host models.Host
+	h.db.Select("bot_link").First(&host, h.hostID)
+	h.tracker.SetGroup(sessState, chatID, host.BotLink)
+}
+
+// joinGroupSession adds a group member to the session under their bot
+// nickname, falling back to the Telegram first name.
+func (h *UpdateHandler) joinGroupSession(sessionID uint, from User) (*services.JoinResult, error) {
+	sessState, err := h.sessionSvc.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if sessState.Status == "finished" {
+		return nil, errors.New("session already finished")
+	}
+	nickname := from.FirstName
+	if user, _, err := h.tgUserSvc.GetOrCreate(from.ID, h.hostID, from.FirstName); err == nil && user.Nickname != "" {
+		nickname = user.Nickname
+	}
+	if nickname == "" {
+		nickname = "Player"
+	}
+	return h.sessionSvc.JoinSession(sessState.Code, from.ID, nickname)
+}
+
+func (h *UpdateHandler) groupJoinError(cb *CallbackQuery, err error) {
+	if errors.Is(err, services.ErrBannedFromRoom) {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(cb.From.ID, "join.banned"), true)
+		return
+	}
+	h.client.AnswerCallbackQuery(cb.ID, h.errText(cb.From.ID, err), true)
+}
+
+func (h *UpdateHandler) handleGroupJoin(cb *CallbackQuery) {
+	userID := cb.From.ID
+	sessionID, err := strconv.ParseUint(strings.TrimPrefix(cb.Data, "gjoin:"), 10, 64)
+	if err != nil {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "error.bad_data"), true)
+		return
+	}
+
+	result, err := h.joinGroupSession(uint(sessionID), cb.From)
+	if err != nil {
+		h.groupJoinError(cb, err)
+		return
+	}
+	if result.IsRejoin {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "group.already"), false)
+		return
+	}
+	h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "group.joined", result.Participant.Nickname), false)
+
+	sessState, err := h.sessionSvc.GetSession(uint(sessionID))
+	if err != nil || sessState.Status != "waiting" {
+		return
+	}
+	lang := h.state.DefaultLocale()
+	h.client.EditMessageText(cb.Message.Chat.ID, cb.Message.MessageID, groupLobbyText(lang, sessState), "HTML", GroupLobbyKeyboard(lang, sessState.ID))
+}
+
+// handleGroupAnswer records a tap on a group question. Members who haven't
+// joined yet are joined on their first answer.
+func (h *UpdateHandler) handleGroupAnswer(cb *CallbackQuery) {
+	userID := cb.From.ID
+	parts := strings.Split(cb.Data, ":")
+	if len(parts) != 3 {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "error.bad_data"), true)
+		return
+	}
+	sessionID, _ := strconv.ParseUint(parts[1], 10, 64)
+	optionID, _ := strconv.ParseUint(parts[2], 10, 64)
+
+	err := h.sessionSvc.SubmitAnswer(uint(sessionID), userID, uint(optionID))
+	if err != nil && strings.Contains(err.Error(), "participant not found") {
+		if _, joinErr := h.joinGroupSession(uint(sessionID), cb.From); joinErr != nil {
+			h.groupJoinError(cb, joinErr)
+			return
+		}
+		err = h.sessionSvc.SubmitAnswer(uint(sessionID), userID, uint(optionID))
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "not accepting") {
+			h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "answer.time_up"), true)
+		} else {
+			h.client.AnswerCallbackQuery(cb.ID, h.errText(userID, err), true)
+		}
+		return
+	}
+	h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "answer.accepted"), false)
+}
+
+// ─── Group chat tracker ───
+
+// SetGroup starts posting a session's questions to a group chat. A session the
+// tracker doesn't follow yet is seeded from sessState, so only later changes
+// are posted.
+func (t *SessionTracker) SetGroup(sessState *services.SessionState, chatID int64, botLink string) {
+	t.mu.Lock()
+	info, exists := t.sessions[sessState.ID]
+	if !exists {
+		info = &SessionInfo{
+			SessionID:       sessState.ID,
+			LastStatus:      sessState.Status,
+			LastQuestion:    sessState.CurrentQuestion,
+			LastAnswerCount: sessState.AnswerCount,
+			Participants:    make(map[int64]*ParticipantInfo),
+			lastState:       sessState,
+			lastQuestionID:  questionID(sessState),
+		}
+		t.track(info)
+	}
+	t.mu.Unlock()
+
+	info.mu.Lock()
+	if info.Group == nil || info.Group.ChatID != chatID {
+		info.Group = &GroupInfo{ChatID: chatID, BotLink: botLink}
+	}
+	info.mu.Unlock()
+}
+
+// closeGroupMessage removes the buttons from the current group message.
+func (t *SessionTracker) closeGroupMessage(g GroupInfo) {
+	if g.MessageID == 0 {
+		return
+	}
+	text := g.Text
+	if g.Question > 0 && g.Answers > 0 {
+		text += "\n\n" + i18n.T(t.state.DefaultLocale(), "group.answers", g.Answers)
+	}
+	t.results.EditMessageText(g.ChatID, g.MessageID, text, "HTML", nil)
+}
+
+func (t *SessionTracker) sendGroupQuestion(info *SessionInfo, sessState *services.SessionState) {
+	info.mu.Lock()
+	if info.Group == nil {
+		info.mu.Unlock()
+		return
+	}
+	prev := *info.Group
+	info.mu.Unlock()
+	t.closeGroupMessage(prev)
+
+	lang := t.state.DefaultLocale()
+	qd := buildQuestionData(info.SessionID, sessState.CurrentQuestionData)
+
+	// A poll shows its own vote count, so there is no message to edit until
+	// the reveal.
+	if poll := t.buildQuizPoll(info.SessionID, qd, sessState.CurrentQuestion, sessState.TotalQuestions); poll != nil {
+		t.sendPollTo(info.SessionID, prev.ChatID, sessState.CurrentQuestionData.Images, poll, GroupPollKeyboard(lang, info.SessionID), true)
+		info.mu.Lock()
+		if g := info.Group; g != nil {
+			g.MessageID = 0
+			g.Text = ""
+			g.Keyboard = nil
+			g.Question = 0
+			g.Answers = 0
+		}
+		info.mu.Unlock()
+		return
+	}
+
+	note := ""
+	dmLink := ""
+	if qd.Type != "single_choice" {
+		note = i18n.T(lang, "group.dm_only")
+		if prev.BotLink != "" {
+			dmLink = fmt.Sprintf("%s?start=%s", prev.BotLink, sessState.Code)
+		}
+	}
+	text := questionText(lang, qd, sessState.CurrentQuestion, sessState.TotalQuestions, note)
+	kb := GroupQuestionKeyboard(lang, qd, dmLink)
+
+	if len(sessState.CurrentQuestionData.Images) > 0 && t.media != nil {
+		t.media.SendQuestionMedia(prev.ChatID, sessState.CurrentQuestionData.Images)
+	}
+	msgID, err := t.questions.SendMessage(prev.ChatID, text, "HTML", kb)
+	if err != nil {
+		log.Printf("send group question to %d: %v", prev.ChatID, err)
+	}
+
+	info.mu.Lock()
+	if g := info.Group; g != nil {
+		g.MessageID = msgID
+		g.Text = text
+		g.Keyboard = kb
+		g.Question = sessState.CurrentQuestion
+		g.Answers = 0
+		g.lastEdit = time.Now()
+	}
+	info.mu.Unlock()
+}
+
+// refreshGroupCount shows the new answer count under the group question. Edits
+// are spaced by groupEditInterval; a change inside the interval is shown when
+// it ends. The caller holds info.syncMu.
+func (t *SessionTracker) refreshGroupCount(info *SessionInfo, answers int) {
+	info.mu.Lock()
+	g := info.Group
+	if g == nil || g.MessageID == 0 {
+		info.mu.Unlock()
+		return
+	}
+	g.Answers = answers
+	if wait := groupEditInterval - time.Since(g.lastEdit); wait > 0 {
+		if !g.pending {
+			g.pending = true
+			time.AfterFunc(wait, func() {
+				info.syncMu.Lock()
+				defer info.syncMu.Unlock()
+				t.flushGroupCount(info)
+			})
+		}
+		info.mu.Unlock()
+		return
+	}
+	info.mu.Unlock()
+	t.flushGroupCount(info)
+}
+
+func (t *SessionTracker) flushGroupCount(info *SessionInfo) {
+	info.mu.Lock()
+	g := info.Group
+	if g == nil {
+		info.mu.Unlock()
+		return
+	}
+	g.pending = false
+	if info.LastStatus != "question" || g.Question != info.LastQuestion || g.MessageID == 0 {
+		info.mu.Unlock()
+		return
+	}
+	g.lastEdit = time.Now()
+	snapshot := *g
+	info.mu.Unlock()
+
+	text := snapshot.Text + "\n\n" + i18n.T(t.state.DefaultLocale(), "group.answers", snapshot.Answers)
+	if err := t.results.EditMessageText(snapshot.ChatID, snapshot.MessageID, text, "HTML", snapshot.Keyboard); err != nil {
+		log.Printf("edit group question in %d: %v", snapshot.ChatID, err)
+	}
+}
+
+func (t *SessionTracker) sendGroupReveal(info *SessionInfo, sessState *services.SessionState) {
+	info.mu.Lock()
+	if info.Group == nil {
+		info.mu.Unlock()
+		return
+	}
+	g := *info.Group
+	info.mu.Unlock()
+
+	lang := t.state.DefaultLocale()
+	qd := buildQuestionData(info.SessionID, sessState.CurrentQuestionData)
+	text := questionText(lang, qd, sessState.CurrentQuestion, sessState.TotalQuestions, "")
+	if answer := correctAnswerText(lang, sessState.CurrentQuestionData); answer != "" {
+		text += "\n\n" + answer
+	}
+	text += "\n\n" + i18n.T(lang, "group.answers", sessState.AnswerCount)
+	kb := GroupRevealKeyboard(lang, info.SessionID, sessState.CurrentQuestion, sessState.TotalQuestions)
+
+	msgID := g.MessageID
+	if msgID == 0 || t.results.EditMessageText(g.ChatID, msgID, text, "HTML", kb) != nil {
+		var err error
+		msgID, err = t.results.SendMessage(g.ChatID, text, "HTML", kb)
+		if err != nil {
+			log.Printf("send group reveal to %d: %v", g.ChatID, err)
+		}
+	}
+
+	info.mu.Lock()
+	if gi := info.Group; gi != nil {
+		gi.MessageID = msgID
+		gi.Text = text
+		gi.Keyboard = kb
+		gi.Question = 0 // the reveal shows its own count
+	}
+	info.mu.Unlock()
+}
+
+// sendGroupLeaderboard closes the last group message and posts the results.
+// A quiz cancelled from the lobby has no results to post.
+func (t *SessionTracker) sendGroupLeaderboard(info *SessionInfo, started bool) {
+	info.mu.Lock()
+	if info.Group == nil {
+		info.mu.Unlock()
+		return
+	}
+	g := *info.Group
+	info.mu.Unlock()
+
+	t.closeGroupMessage(g)
+	if !started {
+		return
+	}
+
+	entries, err := t.sessionSvc.GetLeaderboard(info.SessionID)
+	if err != nil {
+		return
+	}
+	lang := t.state.DefaultLocale()
+	text := leaderboardText(lang, entries) + "\n\n" + i18n.T(lang, "group.again")
+	if _, err := t.results.SendMessage(g.ChatID, text, "HTML", nil); err != nil {
+		log.Printf("send group leaderboard to %d: %v", g.ChatID, err)
+	}
+}
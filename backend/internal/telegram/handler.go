@@ -2,16 +2,17 @@ package telegram
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
-	"quiz-game-backend/internal/models"
+	"quiz-game-backend/internal/i18n"
 	"quiz-game-backend/internal/services"
 	"quiz-game-backend/internal/ws"
 
-	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
@@ -25,6 +26,11 @@ type UpdateHandler struct {
 	roomSvc    *services.RoomService
 	quizSvc    *services.QuizService
 	tgUserSvc  *services.TelegramUserService
+	presence   *services.PresenceService
+	chatSvc    *services.ChatService
+	aiSvc      *services.AIGenerateService
+	remoteAuth *services.RemoteAuthService
+	audit      *services.AuditService
 	hub        *ws.Hub
 	db         *gorm.DB
 	hostID     uint
@@ -38,6 +44,11 @@ func NewUpdateHandler(
 	roomSvc *services.RoomService,
 	quizSvc *services.QuizService,
 	tgUserSvc *services.TelegramUserService,
+	presence *services.PresenceService,
+	chatSvc *services.ChatService,
+	aiSvc *services.AIGenerateService,
+	remoteAuth *services.RemoteAuthService,
+	audit *services.AuditService,
 	hub *ws.Hub,
 	db *gorm.DB,
 	hostID uint,
@@ -50,6 +61,11 @@ func NewUpdateHandler(
 		roomSvc:    roomSvc,
 		quizSvc:    quizSvc,
 		tgUserSvc:  tgUserSvc,
+		presence:   presence,
+		chatSvc:    chatSvc,
+		aiSvc:      aiSvc,
+		remoteAuth: remoteAuth,
+		audit:      audit,
 		hub:        hub,
 		db:         db,
 		hostID:     hostID,
@@ -57,15 +73,77 @@ func NewUpdateHandler(
 }
 
 func (h *UpdateHandler) Handle(upd Update) {
+	h.touchPresence(upd)
+	h.rememberLanguage(upd)
+
 	if upd.CallbackQuery != nil {
 		h.handleCallback(upd.CallbackQuery)
 		return
 	}
+	if upd.PollAnswer != nil {
+		h.handlePollAnswer(upd.PollAnswer)
+		return
+	}
 	if upd.Message != nil {
 		h.handleMessage(upd.Message)
 	}
 }
 
+func (h *UpdateHandler) touchPresence(upd Update) {
+	if h.presence == nil {
+		return
+	}
+	var userID int64
+	if upd.CallbackQuery != nil {
+		userID = upd.CallbackQuery.From.ID
+	} else if upd.Message != nil && upd.Message.From != nil {
+		userID = upd.Message.From.ID
+	} else if upd.PollAnswer != nil && upd.PollAnswer.User != nil {
+		userID = upd.PollAnswer.User.ID
+	}
+	if userID == 0 {
+		return
+	}
+	us := h.state.Get(userID)
+	var roomID uint
+	if !isHostState(us.State) {
+		roomID = us.RoomID
+	}
+	h.presence.TouchTelegram(roomID, userID)
+}
+
+// rememberLanguage stores the sender's Telegram language so replies and
+// session updates follow it until the user picks one with /language.
+func (h *UpdateHandler) rememberLanguage(upd Update) {
+	var from *User
+	if upd.CallbackQuery != nil {
+		from = &upd.CallbackQuery.From
+	} else if upd.Message != nil {
+		from = upd.Message.From
+	} else if upd.PollAnswer != nil {
+		from = upd.PollAnswer.User
+	}
+	if from != nil {
+		h.state.SetLanguageCode(from.ID, from.LanguageCode)
+	}
+}
+
+// tr renders a catalog message in the user's language.
+func (h *UpdateHandler) tr(userID int64, key string, args ...interface{}) string {
+	return i18n.T(h.state.Locale(userID), key, args...)
+}
+
+// errText renders a service error for the user.
+func (h *UpdateHandler) errText(userID int64, err error) string {
+	lang := h.state.Locale(userID)
+	return i18n.T(lang, "error.generic", i18n.Error(lang, err.Error()))
+}
+
+// mainMenu returns the reply keyboard in the user's language.
+func (h *UpdateHandler) mainMenu(userID int64) *ReplyKeyboardMarkup {
+	return MainMenuKeyboard(h.state.Locale(userID))
+}
+
 func (h *UpdateHandler) sendAndTrack(chatID int64, userID int64, text, parseMode string, kb interface{}) int64 {
 	us := h.state.Get(userID)
 	if us.LastBotMsgID > 0 {
@@ -86,6 +164,11 @@ func (h *UpdateHandler) handleMessage(msg *Message) {
 	chatID := msg.Chat.ID
 	text := strings.TrimSpace(msg.Text)
 
+	if msg.Chat.IsGroup() {
+		h.handleGroupMessage(msg)
+		return
+	}
+
 	if isCommand(msg, "start") {
 		h.cmdStart(msg, userID, chatID, text)
 		return
@@ -101,26 +184,38 @@ func (h *UpdateHandler) handleMessage(msg *Message) {
 		return
 	}
 
-	switch text {
-	case "🎮 Войти в квиз":
+	if isCommand(msg, "language") {
+		h.cmdLanguage(userID, chatID)
+		return
+	}
+
+	// Reply-keyboard labels are matched in every language, so a keyboard
+	// sent before a language switch keeps working.
+	button, _ := i18n.Button(text)
+	switch button {
+	case "btn.join":
 		h.state.Set(userID, &UserState{State: StateEnterCode})
-		h.client.SendMessage(chatID, "Введите 6-значный код комнаты:", "", nil)
+		h.client.SendMessage(chatID, h.tr(userID, "join.enter_code"), "", nil)
 		return
-	case "👤 Мой профиль":
+	case "btn.profile":
 		h.cmdProfile(userID, chatID)
 		return
-	case "📊 История игр":
+	case "btn.history":
 		h.cmdHistory(userID, chatID)
 		return
-	case "🔄 Переподключиться":
+	case "btn.rejoin":
 		h.cmdRejoin(userID, chatID)
 		return
-	case "🎯 Пульт ведущего":
+	case "btn.host":
 		h.startHostAuth(userID, chatID)
 		return
 	}
 
 	us := h.state.Get(userID)
+	if (us.State == StateHostRemote || hostInputStates[us.State]) && !h.requireRemoteAccess(userID) {
+		h.client.SendMessage(chatID, h.tr(userID, "remote.revoked"), "", h.mainMenu(userID))
+		return
+	}
 	switch us.State {
 	case StateEnterCode:
 		h.onCode(userID, chatID, text, msg.From.FirstName)
@@ -131,11 +226,25 @@ func (h *UpdateHandler) handleMessage(msg *Message) {
 	case StateEnterNumeric:
 		h.onNumericAnswer(userID, chatID, text, us)
 	case StateHostPassword:
-		h.onHostPassword(userID, chatID, text)
+		h.onHostPassword(msg, userID, chatID, text)
+	case StateHostRename:
+		h.onHostRename(userID, chatID, text, us)
+	case StateHostQuizTitle:
+		h.onQuizTitle(userID, chatID, text)
+	case StateHostQuestionText:
+		h.onQuestionText(userID, chatID, text, us)
+	case StateHostOptions:
+		h.onQuestionOptions(userID, chatID, text, us)
+	case StateHostNumber:
+		h.onQuestionNumber(userID, chatID, text, us)
+	case StateHostImport:
+		h.onQuizImport(userID, chatID, msg)
+	case StateHostAIPrompt:
+		h.onAIPrompt(userID, chatID, text)
 	case StateHostRemote:
-		h.client.SendMessage(chatID, "🎯 Вы в режиме пульта. Используйте кнопки в сообщении выше.\n\nДля выхода нажмите /start", "HTML", nil)
+		h.client.SendMessage(chatID, h.tr(userID, "remote.in_remote"), "HTML", nil)
 	default:
-		h.client.SendMessage(chatID, "Используйте /start или кнопки меню.", "", MainMenuKeyboard())
+		h.client.SendMessage(chatID, h.tr(userID, "menu.hint"), "", h.mainMenu(userID))
 	}
 }
 
@@ -148,14 +257,20 @@ func (h *UpdateHandler) cmdStart(msg *Message, userID, chatID int64, text string
 	}
 
 	args := extractStartArgs(text)
+	if strings.HasPrefix(args, services.RemotePairingPrefix) {
+		h.state.Set(userID, &UserState{State: StateHostPassword})
+		h.loginRemote(userID, chatID, args, msg.From)
+		return
+	}
 
 	us := h.state.Get(userID)
 	if us.State == StateInSession && us.SessionID > 0 && args == "" {
 		sessState, err := h.sessionSvc.GetSession(us.SessionID)
 		if err == nil && sessState.Status != "finished" {
+			lang := h.state.Locale(userID)
 			h.client.SendMessage(chatID,
-				"🎮 Вы сейчас в активной сессии.\n\nНажмите <b>🔄 Переподключиться</b> чтобы вернуться в игру, или введите новый код.",
-				"HTML", SessionMenuKeyboard())
+				i18n.T(lang, "start.in_session", i18n.T(lang, "btn.rejoin")),
+				"HTML", SessionMenuKeyboard(lang))
 			return
 		}
 	}
@@ -174,21 +289,16 @@ func (h *UpdateHandler) cmdStart(msg *Message, userID, chatID int64, text string
 			h.doJoin(userID, chatID, code, nickname)
 		} else {
 			h.state.Set(userID, &UserState{State: StateEnterNickname, Code: code})
-			h.client.SendMessage(chatID,
-				fmt.Sprintf("👋 Добро пожаловать в Quiz Game!\n\nКод комнаты: <b>%s</b>\nВведите ваш никнейм:", code),
-				"HTML", nil)
+			h.client.SendMessage(chatID, h.tr(userID, "start.welcome_code", code), "HTML", nil)
 		}
 		return
 	}
 
 	if nickname != "" && !created {
-		h.client.SendMessage(chatID,
-			fmt.Sprintf("👋 Привет, <b>%s</b>!\n\nВыберите действие:", nickname),
-			"HTML", MainMenuKeyboard())
+		h.client.SendMessage(chatID, h.tr(userID, "start.greeting", nickname), "HTML", h.mainMenu(userID))
 	} else {
 		h.state.Set(userID, &UserState{State: StateEnterNickname})
-		h.client.SendMessage(chatID,
-			"👋 Добро пожаловать в Quiz Game!\n\nВведите ваш никнейм:", "", nil)
+		h.client.SendMessage(chatID, h.tr(userID, "start.welcome"), "", nil)
 	}
 }
 
@@ -196,7 +306,7 @@ func (h *UpdateHandler) cmdStart(msg *Message, userID, chatID int64, text string
 
 func (h *UpdateHandler) onCode(userID, chatID int64, code, firstName string) {
 	if len(code) != 6 || !isDigits(code) {
-		h.client.SendMessage(chatID, "❌ Код должен состоять из 6 цифр. Попробуйте ещё раз:", "", nil)
+		h.client.SendMessage(chatID, h.tr(userID, "join.bad_code"), "", nil)
 		return
 	}
 
@@ -210,28 +320,27 @@ func (h *UpdateHandler) onCode(userID, chatID int64, code, firstName string) {
 		h.doJoin(userID, chatID, code, nickname)
 	} else {
 		h.state.Set(userID, &UserState{State: StateEnterNickname, Code: code})
-		h.client.SendMessage(chatID,
-			fmt.Sprintf("✅ Код принят: <b>%s</b>\n\nВведите ваш никнейм:", code),
-			"HTML", nil)
+		h.client.SendMessage(chatID, h.tr(userID, "join.code_ok", code), "HTML", nil)
 	}
 }
 
 func (h *UpdateHandler) onNickname(userID, chatID int64, nickname string) {
 	if len(nickname) < 1 || len(nickname) > 100 {
-		h.client.SendMessage(chatID, "❌ Никнейм должен быть от 1 до 100 символов. Попробуйте ещё раз:", "", nil)
+		h.client.SendMessage(chatID, h.tr(userID, "nick.bad_length"), "", nil)
 		return
 	}
 
-	h.tgUserSvc.UpdateNickname(userID, h.hostID, nickname)
+	if _, err := h.tgUserSvc.UpdateNickname(userID, h.hostID, nickname); errors.Is(err, services.ErrNicknameBlocked) {
+		h.client.SendMessage(chatID, h.tr(userID, "nick.blocked"), "", nil)
+		return
+	}
 
 	us := h.state.Get(userID)
 	code := us.Code
 
 	if code == "" {
 		h.state.Clear(userID)
-		h.client.SendMessage(chatID,
-			fmt.Sprintf("✅ Никнейм установлен: <b>%s</b>\n\nВыберите действие:", nickname),
-			"HTML", MainMenuKeyboard())
+		h.client.SendMessage(chatID, h.tr(userID, "nick.set", nickname), "HTML", h.mainMenu(userID))
 		return
 	}
 
@@ -240,34 +349,47 @@ func (h *UpdateHandler) onNickname(userID, chatID int64, nickname string) {
 
 func (h *UpdateHandler) doJoin(userID, chatID int64, code, nickname string) {
 	result, err := h.sessionSvc.JoinSession(code, userID, nickname)
+	if errors.Is(err, services.ErrBannedFromRoom) {
+		h.client.SendMessage(chatID, h.tr(userID, "join.banned"), "", h.mainMenu(userID))
+		h.state.Clear(userID)
+		return
+	}
 	if err != nil {
-		h.client.SendMessage(chatID,
-			fmt.Sprintf("❌ Ошибка: %s\n\nПопробуйте /start заново.", err.Error()),
-			"", MainMenuKeyboard())
+		lang := h.state.Locale(userID)
+		h.client.SendMessage(chatID, i18n.T(lang, "join.failed", i18n.Error(lang, err.Error())), "", MainMenuKeyboard(lang))
 		h.state.Clear(userID)
 		return
 	}
 
+	sessState, sessErr := h.sessionSvc.GetSession(result.SessionID)
+	var roomID uint
+	if sessErr == nil {
+		roomID = sessState.RoomID
+	}
+
 	h.state.Set(userID, &UserState{
 		State:     StateInSession,
 		SessionID: result.SessionID,
+		RoomID:    roomID,
 		Code:      code,
 		Nickname:  nickname,
 	})
+	if h.presence != nil {
+		h.presence.TouchTelegram(roomID, userID)
+	}
 
 	var statusText string
 	if result.IsRejoin {
-		statusText = fmt.Sprintf("🔄 Вы переподключились к квизу!\n\nНикнейм: <b>%s</b>", nickname)
+		statusText = h.tr(userID, "join.rejoined", nickname)
 	} else {
-		statusText = fmt.Sprintf("🎮 Вы подключились к квизу!\n\nНикнейм: <b>%s</b>\nОжидайте начала игры...", nickname)
+		statusText = h.tr(userID, "join.joined", nickname)
 	}
 
 	msgID, _ := h.client.SendMessage(chatID, statusText, "HTML", nil)
 
 	h.tracker.AddParticipant(result.SessionID, userID, chatID, msgID)
 
-	sessState, err := h.sessionSvc.GetSession(result.SessionID)
-	if err == nil && sessState.Status != "waiting" {
+	if sessErr == nil && sessState.Status != "waiting" {
 		go h.tracker.SyncParticipant(result.SessionID, userID)
 	}
 
@@ -282,19 +404,18 @@ func (h *UpdateHandler) doJoin(userID, chatID int64, code, nickname string) {
 func (h *UpdateHandler) tryRecoverSession(userID, chatID int64, us *UserState) {
 	if us.SessionID == 0 {
 		h.state.Clear(userID)
-		h.client.SendMessage(chatID, "Сессия не найдена. Используйте /start", "", MainMenuKeyboard())
+		h.client.SendMessage(chatID, h.tr(userID, "session.not_found"), "", h.mainMenu(userID))
 		return
 	}
 
 	sessState, err := h.sessionSvc.GetSession(us.SessionID)
 	if err != nil || sessState.Status == "finished" {
 		h.state.Clear(userID)
-		h.client.SendMessage(chatID, "Сессия завершена. Нажмите /start для новой игры.", "", MainMenuKeyboard())
+		h.client.SendMessage(chatID, h.tr(userID, "session.finished"), "", h.mainMenu(userID))
 		return
 	}
 
-	msgID, _ := h.client.SendMessage(chatID,
-		"🔄 Переподключение к квизу...", "HTML", nil)
+	msgID, _ := h.client.SendMessage(chatID, h.tr(userID, "session.reconnecting"), "HTML", nil)
 
 	h.tracker.AddParticipant(us.SessionID, userID, chatID, msgID)
 	go h.tracker.SyncParticipant(us.SessionID, userID)
@@ -303,19 +424,18 @@ func (h *UpdateHandler) tryRecoverSession(userID, chatID int64, us *UserState) {
 func (h *UpdateHandler) cmdRejoin(userID, chatID int64) {
 	us := h.state.Get(userID)
 	if us.State != StateInSession || us.SessionID == 0 {
-		h.client.SendMessage(chatID, "Вы не подключены к сессии. Нажмите /start", "", MainMenuKeyboard())
+		h.client.SendMessage(chatID, h.tr(userID, "session.not_joined"), "", h.mainMenu(userID))
 		return
 	}
 
 	sessState, err := h.sessionSvc.GetSession(us.SessionID)
 	if err != nil || sessState.Status == "finished" {
 		h.state.Clear(userID)
-		h.client.SendMessage(chatID, "Сессия завершена. Нажмите /start для новой игры.", "", MainMenuKeyboard())
+		h.client.SendMessage(chatID, h.tr(userID, "session.finished"), "", h.mainMenu(userID))
 		return
 	}
 
-	msgID, _ := h.client.SendMessage(chatID,
-		"🔄 Переподключение к квизу...", "HTML", nil)
+	msgID, _ := h.client.SendMessage(chatID, h.tr(userID, "session.reconnecting"), "HTML", nil)
 
 	h.tracker.AddParticipant(us.SessionID, userID, chatID, msgID)
 	go h.tracker.SyncParticipant(us.SessionID, userID)
@@ -326,23 +446,22 @@ func (h *UpdateHandler) cmdRejoin(userID, chatID int64) {
 func (h *UpdateHandler) cmdProfile(userID, chatID int64) {
 	user, _, err := h.tgUserSvc.GetOrCreate(userID, h.hostID, "Player")
 	if err != nil {
-		h.client.SendMessage(chatID, "Ошибка загрузки профиля", "", nil)
+		h.client.SendMessage(chatID, h.tr(userID, "profile.failed"), "", nil)
 		return
 	}
-	h.client.SendMessage(chatID,
-		fmt.Sprintf("👤 <b>Ваш профиль</b>\n\nНикнейм: <b>%s</b>\n\nЧтобы изменить ник, отправьте:\n/nickname Новый_ник", user.Nickname),
-		"HTML", nil)
+	h.client.SendMessage(chatID, h.tr(userID, "profile.show", user.Nickname), "HTML", nil)
 }
 
 func (h *UpdateHandler) cmdHistory(userID, chatID int64) {
 	entries, err := h.tgUserSvc.GetHistory(userID, h.hostID)
 	if err != nil || len(entries) == 0 {
-		h.client.SendMessage(chatID, "📊 У вас пока нет завершённых игр.", "", nil)
+		h.client.SendMessage(chatID, h.tr(userID, "history.empty"), "", nil)
 		return
 	}
 
+	lang := h.state.Locale(userID)
 	medals := map[int]string{1: "🥇", 2: "🥈", 3: "🥉"}
-	lines := []string{"📊 <b>Ваша история игр:</b>\n"}
+	lines := []string{i18n.T(lang, "history.title")}
 	limit := 20
 	if len(entries) < limit {
 		limit = len(entries)
@@ -352,7 +471,7 @@ func (h *UpdateHandler) cmdHistory(userID, chatID int64) {
 		if !ok {
 			medal = fmt.Sprintf("%d.", e.Position)
 		}
-		lines = append(lines, fmt.Sprintf("%s <b>%s</b>\n   Очки: %d | Место: %d/%d",
+		lines = append(lines, i18n.T(lang, "history.entry",
 			medal, e.QuizTitle, e.TotalScore, e.Position, e.TotalPlayers))
 	}
 
@@ -362,79 +481,158 @@ func (h *UpdateHandler) cmdHistory(userID, chatID int64) {
 func (h *UpdateHandler) cmdNickname(userID, chatID int64, text string) {
 	parts := strings.SplitN(text, " ", 2)
 	if len(parts) < 2 || strings.TrimSpace(parts[1]) == "" {
-		h.client.SendMessage(chatID, "Использование: /nickname Ваш_новый_ник", "", nil)
+		h.client.SendMessage(chatID, h.tr(userID, "nick.usage"), "", nil)
 		return
 	}
 
 	newNick := strings.TrimSpace(parts[1])
 	if len(newNick) > 100 {
-		h.client.SendMessage(chatID, "Никнейм слишком длинный (макс 100 символов)", "", nil)
+		h.client.SendMessage(chatID, h.tr(userID, "nick.too_long"), "", nil)
 		return
 	}
 
 	user, err := h.tgUserSvc.UpdateNickname(userID, h.hostID, newNick)
+	if errors.Is(err, services.ErrNicknameBlocked) {
+		h.client.SendMessage(chatID, h.tr(userID, "nick.blocked"), "", nil)
+		return
+	}
 	if err != nil {
-		h.client.SendMessage(chatID, fmt.Sprintf("Ошибка: %s", err.Error()), "", nil)
+		h.client.SendMessage(chatID, h.errText(userID, err), "", nil)
 		return
 	}
 
-	h.client.SendMessage(chatID,
-		fmt.Sprintf("✅ Никнейм изменён на: <b>%s</b>", user.Nickname),
-		"HTML", MainMenuKeyboard())
+	h.client.SendMessage(chatID, h.tr(userID, "nick.changed", user.Nickname), "HTML", h.mainMenu(userID))
 }
 
-// ─── Host Remote Control ───
+// ─── Language ───
 
-func (h *UpdateHandler) startHostAuth(userID, chatID int64) {
-	var host models.Host
-	if err := h.db.First(&host, h.hostID).Error; err != nil || host.RemotePassword == "" {
-		h.client.SendMessage(chatID,
-			"❌ Пульт ведущего не настроен.\n\nВладелец бота должен задать пароль для пульта в настройках на сайте.",
-			"HTML", MainMenuKeyboard())
+func (h *UpdateHandler) cmdLanguage(userID, chatID int64) {
+	lang := h.state.Locale(userID)
+	h.client.SendMessage(chatID, i18n.T(lang, "lang.choose"), "", LanguageKeyboard(lang))
+}
+
+func (h *UpdateHandler) handleLanguageCallback(cb *CallbackQuery) {
+	userID := cb.From.ID
+	choice := strings.TrimPrefix(cb.Data, "lang:")
+	if choice != "auto" && !i18n.IsSupported(choice) {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "error.bad_data"), true)
 		return
 	}
+	h.state.UpdateField(userID, func(s *UserState) {
+		s.Language = ""
+		if choice != "auto" {
+			s.Language = choice
+		}
+	})
+	h.client.AnswerCallbackQuery(cb.ID, "", false)
 
-	us := h.state.Get(userID)
-	if us.HostAuthPassword == host.RemotePassword {
+	lang := h.state.Locale(userID)
+	text := i18n.T(lang, "lang.set", i18n.T(lang, "lang.name"))
+	if choice == "auto" {
+		text = i18n.T(lang, "lang.auto")
+	}
+	if cb.Message != nil {
+		h.client.DeleteMessage(cb.Message.Chat.ID, cb.Message.MessageID)
+	}
+
+	// A new message is the only way to replace the reply keyboard labels.
+	var kb interface{}
+	switch us := h.state.Get(userID); {
+	case us.State == StateInSession || us.State == StateEnterNumeric:
+		kb = SessionMenuKeyboard(lang)
+	case !isHostState(us.State):
+		kb = MainMenuKeyboard(lang)
+	}
+	h.client.SendMessage(userID, text, "HTML", kb)
+}
+
+// ─── Host Remote Control ───
+
+func (h *UpdateHandler) startHostAuth(userID, chatID int64) {
+	if h.remoteAuth.IsAuthorized(h.hostID, userID) {
 		h.state.Set(userID, &UserState{State: StateHostRemote})
 		h.showRoomList(userID, chatID)
 		return
 	}
 
+	key := "remote.password"
+	if !h.remoteAuth.HasPassword(h.hostID) {
+		key = "remote.pairing_only"
+	}
 	h.state.Set(userID, &UserState{State: StateHostPassword})
-	h.client.SendMessage(chatID, "🔐 Введите пароль пульта ведущего:", "", nil)
+	h.client.SendMessage(chatID, h.tr(userID, key), "HTML", nil)
+}
+
+// onHostPassword logs into the remote with a typed password or pairing code.
+// The message is deleted so the secret doesn't stay in the chat.
+func (h *UpdateHandler) onHostPassword(msg *Message, userID, chatID int64, secret string) {
+	h.client.DeleteMessage(chatID, msg.MessageID)
+	h.loginRemote(userID, chatID, secret, msg.From)
 }
 
-func (h *UpdateHandler) onHostPassword(userID, chatID int64, password string) {
-	var host models.Host
-	if err := h.db.First(&host, h.hostID).Error; err != nil {
-		h.client.SendMessage(chatID, "Ошибка. Попробуйте /start", "", MainMenuKeyboard())
+func (h *UpdateHandler) loginRemote(userID, chatID int64, secret string, from *User) {
+	err := h.remoteAuth.Login(h.hostID, userID, remoteAccountName(from), secret)
+	var locked *services.RemoteLockedError
+	switch {
+	case errors.As(err, &locked):
+		h.state.Clear(userID)
+		minutes := int(time.Until(locked.Until).Minutes()) + 1
+		h.client.SendMessage(chatID, h.tr(userID, "remote.locked", minutes), "", h.mainMenu(userID))
+		return
+	case errors.Is(err, services.ErrRemoteWrongSecret):
+		h.client.SendMessage(chatID, h.tr(userID, "remote.wrong_password"), "", nil)
+		return
+	case err != nil:
+		log.Printf("remote login for %d: %v", userID, err)
+		h.client.SendMessage(chatID, h.tr(userID, "error.retry_start"), "", h.mainMenu(userID))
 		h.state.Clear(userID)
 		return
 	}
 
-	if strings.TrimSpace(password) != host.RemotePassword {
-		h.client.SendMessage(chatID, "❌ Неверный пароль. Попробуйте ещё раз:", "", nil)
-		return
+	h.state.Set(userID, &UserState{State: StateHostRemote})
+	h.showRoomList(userID, chatID)
+}
+
+// remoteAccountName is how a Telegram account is listed among the remote's
+// authorizations in the web panel.
+func remoteAccountName(from *User) string {
+	if from == nil {
+		return ""
+	}
+	if from.Username != "" {
+		return strings.TrimSpace(from.FirstName + " @" + from.Username)
 	}
+	return from.FirstName
+}
 
-	h.state.UpdateField(userID, func(s *UserState) {
-		s.HostAuthPassword = host.RemotePassword
-	})
-	h.state.Set(userID, &UserState{State: StateHostRemote, HostAuthPassword: host.RemotePassword})
+// requireRemoteAccess drops a user out of the remote if their access was
+// revoked or the password changed since they logged in.
+func (h *UpdateHandler) requireRemoteAccess(userID int64) bool {
+	if h.remoteAuth.IsAuthorized(h.hostID, userID) {
+		return true
+	}
+	h.state.Clear(userID)
+	return false
+}
 
-	h.showRoomList(userID, chatID)
+// sessionChange runs a host control on a session and records who pressed it.
+func (h *UpdateHandler) sessionChange(userID int64, action string, sessionID uint, change func(sessionID, hostID uint) (*services.SessionState, error)) (*services.SessionState, error) {
+	return h.audit.SessionChange(h.hostID, services.TelegramActor(userID), action, sessionID, func() (*services.SessionState, error) {
+		return change(sessionID, h.hostID)
+	})
 }
 
 func (h *UpdateHandler) showRoomList(userID, chatID int64) {
 	rooms, _ := h.roomSvc.GetActiveRooms(h.hostID)
+	lang := h.state.Locale(userID)
 
 	if len(rooms) == 0 {
 		kb := &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{
-			{{Text: "➕ Создать комнату", CallbackData: "host:newroom:0"}},
+			{{Text: i18n.T(lang, "kb.create_room"), CallbackData: "host:newroom:0"}},
+			{{Text: i18n.T(lang, "kb.quizzes"), CallbackData: "host:quizzes:0"}},
 		}}
 		h.sendAndTrack(chatID, userID,
-			"🎯 <b>Пульт ведущего</b>\n\n📋 Нет активных комнат.\n\nСоздайте комнату прямо здесь или на сайте.",
+			i18n.T(lang, "remote.title")+"\n\n"+i18n.T(lang, "remote.no_rooms"),
 			"HTML", kb)
 		return
 	}
@@ -449,8 +647,8 @@ func (h *UpdateHandler) showRoomList(userID, chatID int64) {
 	}
 
 	h.sendAndTrack(chatID, userID,
-		"🎯 <b>Пульт ведущего</b>\n\n✅ Авторизация успешна!\nВыберите комнату:",
-		"HTML", HostRoomPickKeyboard(items))
+		i18n.T(lang, "remote.title")+"\n\n"+i18n.T(lang, "remote.pick_room"),
+		"HTML", HostRoomPickKeyboard(lang, items))
 }
 
 func (h *UpdateHandler) showRoomControl(userID, chatID int64, roomID uint, editMsgID int64) {
@@ -460,6 +658,7 @@ func (h *UpdateHandler) showRoomControl(userID, chatID int64, roomID uint, editM
 	}
 	members, _ := h.roomSvc.ListMembers(roomID)
 	currentSession, _ := h.roomSvc.GetCurrentSession(roomID)
+	lang := h.state.Locale(userID)
 
 	var sessLine string
 	hasSession := false
@@ -467,14 +666,13 @@ func (h *UpdateHandler) showRoomControl(userID, chatID int64, roomID uint, editM
 		hasSession = true
 		sessState, _ := h.sessionSvc.GetSession(currentSession.ID)
 		if sessState != nil {
-			sessLine = fmt.Sprintf("\n\n🎮 Квиз: <b>%s</b>\nСтатус: <b>%s</b>", sessState.Quiz.Title, sessState.Status)
+			sessLine = "\n\n" + i18n.T(lang, "remote.room_session", sessState.Quiz.Title, i18n.T(lang, "status."+sessState.Status))
 		}
 	}
 
-	text := fmt.Sprintf("🚪 <b>Комната %s</b>\n\n👥 Участников: <b>%d</b>\nРежим: <b>%s</b>%s",
-		rw.Room.Code, len(members), rw.Room.Mode, sessLine)
+	text := i18n.T(lang, "remote.room", rw.Room.Code, len(members), rw.Room.Mode) + sessLine
 
-	kb := HostRoomControlKeyboard(roomID, hasSession)
+	kb := HostRoomControlKeyboard(lang, roomID, hasSession)
 
 	if editMsgID > 0 {
 		if err := h.client.EditMessageText(chatID, editMsgID, text, "HTML", kb); err == nil {
@@ -485,6 +683,23 @@ func (h *UpdateHandler) showRoomControl(userID, chatID int64, roomID uint, editM
 }
 
 func (h *UpdateHandler) showQuizPicker(userID, chatID int64, roomID uint, page int, editMsgID int64) {
+	lang := h.state.Locale(userID)
+	items, count, page, totalPages := h.quizPage(lang, page)
+
+	text := i18n.T(lang, "remote.pick_quiz", count)
+	kb := HostQuizPickKeyboard(lang, roomID, items, page, totalPages)
+
+	if editMsgID > 0 {
+		if err := h.client.EditMessageText(chatID, editMsgID, text, "HTML", kb); err == nil {
+			return
+		}
+	}
+	h.sendAndTrack(chatID, userID, text, "HTML", kb)
+}
+
+// quizPage lists one page of the host's quizzes. It returns the items, the
+// total number of quizzes, and the page clamped to the page count.
+func (h *UpdateHandler) quizPage(lang string, page int) ([]QuizPickItem, int, int, int) {
 	quizzes, _ := h.quizSvc.GetQuizzesByHost(h.hostID)
 
 	totalPages := (len(quizzes) + quizzesPerPage - 1) / quizzesPerPage
@@ -512,34 +727,17 @@ func (h *UpdateHandler) showQuizPicker(userID, chatID int64, roomID uint, page i
 		}
 		items = append(items, QuizPickItem{
 			QuizID: q.ID,
-			Label:  fmt.Sprintf("📝 %s (%d вопр.)", q.Title, qCount),
+			Label:  i18n.T(lang, "remote.quiz_label", q.Title, qCount),
 		})
 	}
-
-	text := fmt.Sprintf("📋 <b>Выберите квиз</b>\n\n%d квизов доступно", len(quizzes))
-	kb := HostQuizPickKeyboard(roomID, items, page, totalPages)
-
-	if editMsgID > 0 {
-		if err := h.client.EditMessageText(chatID, editMsgID, text, "HTML", kb); err == nil {
-			return
-		}
-	}
-	h.sendAndTrack(chatID, userID, text, "HTML", kb)
+	return items, len(quizzes), page, totalPages
 }
 
 // ─── Host action on session via room ───
 
 func (h *UpdateHandler) handleHostAction(cb *CallbackQuery, action string, sessionID uint) {
 	chatID := cb.Message.Chat.ID
-
-	broadcastToAll := func(msgType string, data interface{}, roomID uint) {
-		if h.hub != nil {
-			h.hub.Broadcast(sessionID, ws.WSMessage{Type: msgType, Data: data})
-			if roomID > 0 {
-				h.hub.BroadcastToRoom(roomID, ws.WSMessage{Type: msgType, Data: data})
-			}
-		}
-	}
+	lang := h.state.Locale(cb.From.ID)
 
 	sessForRoom, _ := h.sessionSvc.GetSession(sessionID)
 	var roomID uint
@@ -549,38 +747,28 @@ func (h *UpdateHandler) handleHostAction(cb *CallbackQuery, action string, sessi
 
 	switch action {
 	case "reveal":
-		state, err := h.sessionSvc.RevealAnswer(sessionID, h.hostID)
-		if err != nil {
-			h.client.AnswerCallbackQuery(cb.ID, "Ошибка: "+err.Error(), true)
+		if _, err := h.sessionChange(cb.From.ID, services.AuditSessionReveal, sessionID, h.sessionSvc.RevealAnswer); err != nil {
+			h.client.AnswerCallbackQuery(cb.ID, h.errText(cb.From.ID, err), true)
 			return
 		}
-		broadcastToAll("revealed", state, roomID)
-		h.client.AnswerCallbackQuery(cb.ID, "👁 Ответ показан", false)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "remote.revealed"), false)
 
 	case "next":
-		state, err := h.sessionSvc.NextQuestion(sessionID, h.hostID)
-		if err != nil {
-			h.client.AnswerCallbackQuery(cb.ID, "Ошибка: "+err.Error(), true)
+		if _, err := h.sessionChange(cb.From.ID, services.AuditSessionNext, sessionID, h.sessionSvc.NextQuestion); err != nil {
+			h.client.AnswerCallbackQuery(cb.ID, h.errText(cb.From.ID, err), true)
 			return
 		}
-		msgType := "question"
-		if state.Status == "finished" {
-			msgType = "finished"
-		}
-		broadcastToAll(msgType, state, roomID)
-		h.client.AnswerCallbackQuery(cb.ID, "➡️ Далее", false)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "remote.next"), false)
 
 	case "finish":
-		state, err := h.sessionSvc.ForceFinish(sessionID, h.hostID)
-		if err != nil {
-			h.client.AnswerCallbackQuery(cb.ID, "Ошибка: "+err.Error(), true)
+		if _, err := h.sessionChange(cb.From.ID, services.AuditSessionFinish, sessionID, h.sessionSvc.ForceFinish); err != nil {
+			h.client.AnswerCallbackQuery(cb.ID, h.errText(cb.From.ID, err), true)
 			return
 		}
-		broadcastToAll("finished", state, roomID)
-		h.client.AnswerCallbackQuery(cb.ID, "🏆 Квиз завершён", false)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "remote.finished"), false)
 
 	case "refresh":
-		h.client.AnswerCallbackQuery(cb.ID, "🔄 Обновлено", false)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "common.refreshed"), false)
 
 	case "backroom":
 		h.client.AnswerCallbackQuery(cb.ID, "", false)
@@ -595,8 +783,8 @@ func (h *UpdateHandler) handleHostAction(cb *CallbackQuery, action string, sessi
 		return
 	}
 
-	text := h.tracker.buildHostControlText(sessState)
-	kb := HostControlKeyboard(sessionID, sessState.Status, sessState.CurrentQuestion, sessState.TotalQuestions)
+	text := h.tracker.buildHostControlText(lang, sessState)
+	kb := HostControlKeyboard(lang, sessionID, sessState.Status, sessState.CurrentQuestion, sessState.TotalQuestions)
 
 	if cb.Message != nil && cb.Message.MessageID > 0 {
 		if err := h.client.EditMessageText(chatID, cb.Message.MessageID, text, "HTML", kb); err != nil {
@@ -619,6 +807,11 @@ func (h *UpdateHandler) handleHostAction(cb *CallbackQuery, action string, sessi
 // ─── Callback router ───
 
 func (h *UpdateHandler) handleCallback(cb *CallbackQuery) {
+	if isGroupCallback(cb.Data) {
+		h.handleGroupCallback(cb)
+		return
+	}
+
 	if strings.HasPrefix(cb.Data, "host:") {
 		h.routeHostCallback(cb)
 		return
@@ -634,8 +827,29 @@ func (h *UpdateHandler) handleCallback(cb *CallbackQuery) {
 		return
 	}
 
+	for prefix, action := range complexActions {
+		if strings.HasPrefix(cb.Data, prefix) {
+			if strings.HasPrefix(prefix, "ord") {
+				h.handleOrderingCallback(cb, action)
+			} else {
+				h.handleMatchingCallback(cb, action)
+			}
+			return
+		}
+	}
+
+	if strings.HasPrefix(cb.Data, "react:") {
+		h.handleReaction(cb)
+		return
+	}
+
+	if strings.HasPrefix(cb.Data, "lang:") {
+		h.handleLanguageCallback(cb)
+		return
+	}
+
 	if !strings.HasPrefix(cb.Data, "ans:") {
-		h.client.AnswerCallbackQuery(cb.ID, "Неверные данные", true)
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(cb.From.ID, "error.bad_data"), true)
 		return
 	}
 
@@ -645,7 +859,7 @@ func (h *UpdateHandler) handleCallback(cb *CallbackQuery) {
 func (h *UpdateHandler) routeHostCallback(cb *CallbackQuery) {
 	parts := strings.Split(cb.Data, ":")
 	if len(parts) < 3 {
-		h.client.AnswerCallbackQuery(cb.ID, "Неверные данные", true)
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(cb.From.ID, "error.bad_data"), true)
 		return
 	}
 
@@ -653,10 +867,24 @@ func (h *UpdateHandler) routeHostCallback(cb *CallbackQuery) {
 	id, _ := strconv.ParseUint(parts[2], 10, 64)
 	chatID := cb.Message.Chat.ID
 	userID := cb.From.ID
+	lang := h.state.Locale(userID)
 
 	us := h.state.Get(userID)
+	if hostInputStates[us.State] {
+		// Any button press abandons pending input.
+		h.state.UpdateField(userID, func(s *UserState) {
+			s.State = StateHostRemote
+			s.RenameTarget = ""
+			s.Draft = nil
+		})
+		us.State = StateHostRemote
+	}
 	if us.State != StateHostRemote && action != "noop" {
-		h.client.AnswerCallbackQuery(cb.ID, "Авторизуйтесь: /start → 🎯 Пульт ведущего", true)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "remote.auth_required", i18n.T(lang, "btn.host")), true)
+		return
+	}
+	if action != "noop" && !h.requireRemoteAccess(userID) {
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "remote.revoked"), true)
 		return
 	}
 
@@ -671,10 +899,11 @@ func (h *UpdateHandler) routeHostCallback(cb *CallbackQuery) {
 	case "newroom":
 		room, err := h.roomSvc.CreateRoom(h.hostID, "bot")
 		if err != nil {
-			h.client.AnswerCallbackQuery(cb.ID, "Ошибка: "+err.Error(), true)
+			h.client.AnswerCallbackQuery(cb.ID, h.errText(userID, err), true)
 			return
 		}
-		h.client.AnswerCallbackQuery(cb.ID, "✅ Комната создана", false)
+		h.audit.Record(h.hostID, services.TelegramActor(userID), services.AuditRoomOpen, services.AuditTargetRoom, room.ID, nil, h.audit.RoomSummary(room.ID))
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "remote.room_created"), false)
 		h.state.UpdateField(userID, func(s *UserState) { s.RoomID = room.ID })
 		h.showRoomControl(userID, chatID, room.ID, 0)
 
@@ -684,18 +913,20 @@ func (h *UpdateHandler) routeHostCallback(cb *CallbackQuery) {
 		h.showRoomControl(userID, chatID, uint(id), cb.Message.MessageID)
 
 	case "roomrefresh":
-		h.client.AnswerCallbackQuery(cb.ID, "🔄 Обновлено", false)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "common.refreshed"), false)
 		h.showRoomControl(userID, chatID, uint(id), cb.Message.MessageID)
 
 	case "closeroom":
+		before := h.audit.RoomSummary(uint(id))
 		if err := h.roomSvc.CloseRoom(uint(id), h.hostID); err != nil {
-			h.client.AnswerCallbackQuery(cb.ID, "Ошибка: "+err.Error(), true)
+			h.client.AnswerCallbackQuery(cb.ID, h.errText(userID, err), true)
 			return
 		}
+		h.audit.Record(h.hostID, services.TelegramActor(userID), services.AuditRoomClose, services.AuditTargetRoom, uint(id), before, h.audit.RoomSummary(uint(id)))
 		if h.hub != nil {
 			h.hub.BroadcastToRoom(uint(id), ws.WSMessage{Type: "room_closed"})
 		}
-		h.client.AnswerCallbackQuery(cb.ID, "❌ Комната закрыта", false)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "remote.room_closed"), false)
 		h.showRoomList(userID, chatID)
 
 	case "pickquiz":
@@ -709,60 +940,125 @@ func (h *UpdateHandler) routeHostCallback(cb *CallbackQuery) {
 
 	case "startquiz":
 		if len(parts) < 4 {
-			h.client.AnswerCallbackQuery(cb.ID, "Неверные данные", true)
+			h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "error.bad_data"), true)
 			return
 		}
 		quizID, _ := strconv.ParseUint(parts[3], 10, 64)
-		roomID := uint(id)
+		h.startQuizInRoom(cb, uint(id), uint(quizID))
 
-		session, err := h.sessionSvc.CreateSessionInRoom(roomID, uint(quizID), h.hostID)
-		if err != nil {
-			h.client.AnswerCallbackQuery(cb.ID, "Ошибка: "+err.Error(), true)
+	case "quizzes":
+		h.client.AnswerCallbackQuery(cb.ID, "", false)
+		h.showQuizList(userID, chatID, int(id), cb.Message.MessageID)
+
+	case "quiz":
+		h.client.AnswerCallbackQuery(cb.ID, "", false)
+		h.showQuizCard(userID, chatID, uint(id), "", cb.Message.MessageID)
+
+	case "newquiz":
+		h.client.AnswerCallbackQuery(cb.ID, "", false)
+		h.promptHostInput(userID, chatID, StateHostQuizTitle, nil, "quiz.enter_title", "host:quizzes:0")
+
+	case "addq":
+		h.client.AnswerCallbackQuery(cb.ID, "", false)
+		h.client.EditMessageText(chatID, cb.Message.MessageID, i18n.T(lang, "question.pick_type"), "HTML", HostQuestionTypeKeyboard(lang, uint(id)))
+
+	case "qtype":
+		if len(parts) < 4 {
+			h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "error.bad_data"), true)
 			return
 		}
-		state, _ := h.sessionSvc.GetSession(session.ID)
-		if h.hub != nil {
-			h.hub.BroadcastToRoom(roomID, ws.WSMessage{Type: "quiz_started", Data: state})
-		}
+		h.client.AnswerCallbackQuery(cb.ID, "", false)
+		draft := &QuestionDraft{QuizID: uint(id), Type: parts[3]}
+		h.promptHostInput(userID, chatID, StateHostQuestionText, draft, "question.enter_text", fmt.Sprintf("host:quiz:%d", id))
 
-		h.client.AnswerCallbackQuery(cb.ID, "▶️ Квиз запущен!", false)
+	case "playquiz":
+		room, err := h.roomSvc.CreateRoom(h.hostID, "bot")
+		if err != nil {
+			h.client.AnswerCallbackQuery(cb.ID, h.errText(userID, err), true)
+			return
+		}
+		h.audit.Record(h.hostID, services.TelegramActor(userID), services.AuditRoomOpen, services.AuditTargetRoom, room.ID, nil, h.audit.RoomSummary(room.ID))
+		h.state.UpdateField(userID, func(s *UserState) { s.RoomID = room.ID })
+		h.startQuizInRoom(cb, room.ID, uint(id))
 
-		text := h.tracker.buildHostControlText(state)
-		kb := HostControlKeyboard(session.ID, state.Status, state.CurrentQuestion, state.TotalQuestions)
+	case "import":
+		h.client.AnswerCallbackQuery(cb.ID, "", false)
+		h.promptHostInput(userID, chatID, StateHostImport, nil, "import.prompt", "host:quizzes:0")
 
-		if cb.Message != nil && cb.Message.MessageID > 0 {
-			h.client.EditMessageText(chatID, cb.Message.MessageID, text, "HTML", kb)
-		} else {
-			msgID, _ := h.client.SendMessage(chatID, text, "HTML", kb)
-			if msgID > 0 {
-				h.tracker.SetHostRemote(session.ID, chatID, msgID)
-			}
+	case "ai":
+		if h.aiSvc == nil || !h.aiSvc.IsAvailable() {
+			h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "ai.unavailable"), true)
+			return
 		}
-		h.tracker.SetHostRemote(session.ID, chatID, cb.Message.MessageID)
+		h.client.AnswerCallbackQuery(cb.ID, "", false)
+		h.promptHostInput(userID, chatID, StateHostAIPrompt, nil, "ai.prompt", "host:quizzes:0")
 
 	case "pick":
 		h.handleHostPick(cb, uint(id))
 
+	case "players":
+		h.client.AnswerCallbackQuery(cb.ID, "", false)
+		h.showPlayers(userID, chatID, uint(id), cb.Message.MessageID)
+
+	case "kick", "ban", "rename":
+		if len(parts) < 4 {
+			h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "error.bad_data"), true)
+			return
+		}
+		h.handlePlayerAction(cb, action, uint(id), parts[3])
+
 	case "reveal", "next", "finish", "refresh", "backroom":
 		h.handleHostAction(cb, action, uint(id))
 
 	default:
-		h.client.AnswerCallbackQuery(cb.ID, "Неизвестная команда", true)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "error.unknown_cmd"), true)
+	}
+}
+
+// startQuizInRoom starts a quiz in a room and turns the pressed message into
+// the host control panel.
+func (h *UpdateHandler) startQuizInRoom(cb *CallbackQuery, roomID, quizID uint) {
+	userID := cb.From.ID
+	chatID := cb.Message.Chat.ID
+	lang := h.state.Locale(userID)
+
+	session, err := h.sessionSvc.CreateSessionInRoom(roomID, quizID, h.hostID)
+	if err != nil {
+		h.client.AnswerCallbackQuery(cb.ID, h.errText(userID, err), true)
+		return
 	}
+	h.audit.Record(h.hostID, services.TelegramActor(userID), services.AuditSessionCreate, services.AuditTargetSession, session.ID, nil, h.audit.SessionSummary(session.ID))
+	state, _ := h.sessionSvc.GetSession(session.ID)
+
+	h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "remote.quiz_started"), false)
+
+	text := h.tracker.buildHostControlText(lang, state)
+	kb := HostControlKeyboard(lang, session.ID, state.Status, state.CurrentQuestion, state.TotalQuestions)
+
+	if cb.Message != nil && cb.Message.MessageID > 0 {
+		h.client.EditMessageText(chatID, cb.Message.MessageID, text, "HTML", kb)
+	} else {
+		msgID, _ := h.client.SendMessage(chatID, text, "HTML", kb)
+		if msgID > 0 {
+			h.tracker.SetHostRemote(session.ID, chatID, msgID)
+		}
+	}
+	h.tracker.SetHostRemote(session.ID, chatID, cb.Message.MessageID)
 }
 
 func (h *UpdateHandler) handleHostPick(cb *CallbackQuery, sessionID uint) {
 	userID := cb.From.ID
 	chatID := cb.Message.Chat.ID
 
+	lang := h.state.Locale(userID)
 	sessState, err := h.sessionSvc.GetSession(sessionID)
 	if err != nil {
-		h.client.AnswerCallbackQuery(cb.ID, "Сессия не найдена", true)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "remote.no_session"), true)
 		return
 	}
 
 	if sessState.HostID != h.hostID {
-		h.client.AnswerCallbackQuery(cb.ID, "Нет доступа к этой сессии", true)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "remote.no_access"), true)
 		return
 	}
 
@@ -771,8 +1067,8 @@ func (h *UpdateHandler) handleHostPick(cb *CallbackQuery, sessionID uint) {
 		s.SessionID = sessionID
 	})
 
-	text := h.tracker.buildHostControlText(sessState)
-	kb := HostControlKeyboard(sessionID, sessState.Status, sessState.CurrentQuestion, sessState.TotalQuestions)
+	text := h.tracker.buildHostControlText(lang, sessState)
+	kb := HostControlKeyboard(lang, sessionID, sessState.Status, sessState.CurrentQuestion, sessState.TotalQuestions)
 
 	msgID, _ := h.client.SendMessage(chatID, text, "HTML", kb)
 
@@ -784,14 +1080,15 @@ func (h *UpdateHandler) handleHostPick(cb *CallbackQuery, sessionID uint) {
 func (h *UpdateHandler) handleAnswerCallback(cb *CallbackQuery) {
 	userID := cb.From.ID
 	us := h.state.Get(userID)
+	lang := h.state.Locale(userID)
 	if us.State != StateInSession {
-		h.client.AnswerCallbackQuery(cb.ID, "Вы не в активной сессии. Нажмите /rejoin", true)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "answer.rejoin_hint"), true)
 		return
 	}
 
 	parts := strings.Split(cb.Data, ":")
 	if len(parts) != 3 {
-		h.client.AnswerCallbackQuery(cb.ID, "Неверные данные", true)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "error.bad_data"), true)
 		return
 	}
 
@@ -802,22 +1099,21 @@ func (h *UpdateHandler) handleAnswerCallback(cb *CallbackQuery) {
 	if err != nil {
 		errText := err.Error()
 		if strings.Contains(errText, "not accepting") {
-			h.client.AnswerCallbackQuery(cb.ID, "Время для ответа вышло", true)
+			h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "answer.time_up"), true)
 		} else if strings.Contains(errText, "participant not found") {
-			h.client.AnswerCallbackQuery(cb.ID, "Переподключение...", false)
+			h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "answer.reconnecting"), false)
 			if us.Code != "" && us.Nickname != "" {
 				go h.doJoin(userID, cb.Message.Chat.ID, us.Code, us.Nickname)
 			}
 		} else {
-			h.client.AnswerCallbackQuery(cb.ID, "Ошибка: "+errText, true)
+			h.client.AnswerCallbackQuery(cb.ID, h.errText(userID, err), true)
 		}
 		return
 	}
 
 	if us.QuestionData != nil && cb.Message != nil {
 		kb := AnswerKeyboard(uint(sessionID), us.QuestionData.Options, uint(optionID))
-		text := fmt.Sprintf("❓ <b>Вопрос %d из %d</b>\n\n%s\n\n✅ <b>Ваш ответ принят</b>",
-			us.CurrentQNum, us.TotalQuestions, us.QuestionData.Text)
+		text := questionText(lang, us.QuestionData, us.CurrentQNum, us.TotalQuestions, i18n.T(lang, "answer.yours_accepted"))
 
 		if err := h.client.EditMessageText(cb.Message.Chat.ID, cb.Message.MessageID, text, "HTML", kb); err != nil {
 			log.Printf("edit answer msg: %v", err)
@@ -828,25 +1124,63 @@ func (h *UpdateHandler) handleAnswerCallback(cb *CallbackQuery) {
 		s.SelectedOptionID = uint(optionID)
 	})
 
-	if h.hub != nil {
-		h.hub.Broadcast(uint(sessionID), ws.WSMessage{
-			Type: "answer_received",
-			Data: gin.H{"session_id": sessionID},
-		})
+	h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "answer.accepted"), false)
+}
+
+func (h *UpdateHandler) handleReaction(cb *CallbackQuery) {
+	userID := cb.From.ID
+	lang := h.state.Locale(userID)
+	parts := strings.Split(cb.Data, ":")
+	if len(parts) != 3 {
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "error.bad_data"), true)
+		return
+	}
+	roomID, _ := strconv.ParseUint(parts[1], 10, 64)
+	idx, err := strconv.Atoi(parts[2])
+	if err != nil || idx < 0 || idx >= len(services.Reactions) {
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "error.bad_data"), true)
+		return
 	}
 
-	h.client.AnswerCallbackQuery(cb.ID, "✅ Ответ принят!", false)
+	us := h.state.Get(userID)
+	if us.RoomID != uint(roomID) {
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "react.not_in_room"), true)
+		return
+	}
+
+	nickname := us.Nickname
+	if nickname == "" {
+		nickname = cb.From.FirstName
+	}
+	sender := services.ChatSender{TelegramID: userID, Nickname: nickname}
+	event, err := h.chatSvc.SendReaction(uint(roomID), sender, services.Reactions[idx])
+	if err != nil {
+		switch err.Error() {
+		case "too many reactions, slow down":
+			h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "react.slow_down"), false)
+		case "you are muted in this room":
+			h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "react.muted"), true)
+		default:
+			h.client.AnswerCallbackQuery(cb.ID, h.errText(userID, err), true)
+		}
+		return
+	}
+
+	if h.hub != nil {
+		h.hub.BroadcastToRoom(uint(roomID), ws.WSMessage{Type: "reaction", Data: event})
+	}
+	h.client.AnswerCallbackQuery(cb.ID, event.Emoji, false)
 }
 
 func (h *UpdateHandler) onNumericAnswer(userID, chatID int64, text string, us *UserState) {
 	val, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
 	if err != nil {
-		h.client.SendMessage(chatID, "⚠️ Введите корректное число.", "", nil)
+		h.client.SendMessage(chatID, h.tr(userID, "numeric.invalid"), "", nil)
 		return
 	}
 
 	if us.QuestionData == nil || us.QuestionData.SessionID == 0 {
-		h.client.SendMessage(chatID, "⚠️ Нет активного вопроса.", "", nil)
+		h.client.SendMessage(chatID, h.tr(userID, "numeric.no_question"), "", nil)
 		return
 	}
 
@@ -856,7 +1190,7 @@ func (h *UpdateHandler) onNumericAnswer(userID, chatID int64, text string, us *U
 
 	sessionID := us.QuestionData.SessionID
 	if err := h.sessionSvc.SubmitComplexAnswerByTelegram(uint(sessionID), userID, answerJSON); err != nil {
-		h.client.SendMessage(chatID, "⚠️ "+err.Error(), "", nil)
+		h.client.SendMessage(chatID, "⚠️ "+i18n.Error(h.state.Locale(userID), err.Error()), "", nil)
 		return
 	}
 
@@ -864,27 +1198,22 @@ func (h *UpdateHandler) onNumericAnswer(userID, chatID int64, text string, us *U
 		s.State = StateInSession
 	})
 
-	h.sendAndTrack(chatID, userID, fmt.Sprintf("✅ <b>Ваш ответ: %s</b>\n\nОжидайте результат...", text), "HTML", nil)
+	h.sendAndTrack(chatID, userID, h.tr(userID, "numeric.accepted", text), "HTML", nil)
 
-	if h.hub != nil {
-		h.hub.Broadcast(uint(sessionID), ws.WSMessage{
-			Type: "answer_received",
-			Data: gin.H{"session_id": sessionID},
-		})
-	}
 }
 
 func (h *UpdateHandler) handleMultiChoiceToggle(cb *CallbackQuery) {
 	userID := cb.From.ID
 	us := h.state.Get(userID)
+	lang := h.state.Locale(userID)
 	if us.State != StateInSession {
-		h.client.AnswerCallbackQuery(cb.ID, "Вы не в активной сессии", true)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "answer.not_in_session"), true)
 		return
 	}
 
 	parts := strings.Split(cb.Data, ":")
 	if len(parts) != 3 {
-		h.client.AnswerCallbackQuery(cb.ID, "Неверные данные", true)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "error.bad_data"), true)
 		return
 	}
 	sessionID, _ := strconv.ParseUint(parts[1], 10, 64)
@@ -908,9 +1237,9 @@ func (h *UpdateHandler) handleMultiChoiceToggle(cb *CallbackQuery) {
 	})
 
 	if us.QuestionData != nil && cb.Message != nil {
-		kb := MultiChoiceKeyboard(uint(sessionID), us.QuestionData.Options, selected)
-		text := fmt.Sprintf("❓ <b>Вопрос %d из %d</b>\n\n%s\n\n<i>Выбрано: %d</i>",
-			us.CurrentQNum, us.TotalQuestions, us.QuestionData.Text, len(selected))
+		kb := MultiChoiceKeyboard(lang, uint(sessionID), us.QuestionData.Options, selected)
+		text := questionText(lang, us.QuestionData, us.CurrentQNum, us.TotalQuestions,
+			i18n.T(lang, "answer.multi_selected", len(selected)))
 		h.client.EditMessageText(cb.Message.Chat.ID, cb.Message.MessageID, text, "HTML", kb)
 	}
 	h.client.AnswerCallbackQuery(cb.ID, "", false)
@@ -919,20 +1248,21 @@ func (h *UpdateHandler) handleMultiChoiceToggle(cb *CallbackQuery) {
 func (h *UpdateHandler) handleMultiChoiceSubmit(cb *CallbackQuery) {
 	userID := cb.From.ID
 	us := h.state.Get(userID)
+	lang := h.state.Locale(userID)
 	if us.State != StateInSession {
-		h.client.AnswerCallbackQuery(cb.ID, "Вы не в активной сессии", true)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "answer.not_in_session"), true)
 		return
 	}
 
 	parts := strings.Split(cb.Data, ":")
 	if len(parts) != 3 {
-		h.client.AnswerCallbackQuery(cb.ID, "Неверные данные", true)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "error.bad_data"), true)
 		return
 	}
 	sessionID, _ := strconv.ParseUint(parts[1], 10, 64)
 
 	if len(us.SelectedOptionIDs) == 0 {
-		h.client.AnswerCallbackQuery(cb.ID, "Выберите хотя бы один ответ", true)
+		h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "answer.multi_empty"), true)
 		return
 	}
 
@@ -942,24 +1272,17 @@ func (h *UpdateHandler) handleMultiChoiceSubmit(cb *CallbackQuery) {
 
 	err := h.sessionSvc.SubmitComplexAnswerByTelegram(uint(sessionID), userID, answerJSON)
 	if err != nil {
-		h.client.AnswerCallbackQuery(cb.ID, "Ошибка: "+err.Error(), true)
+		h.client.AnswerCallbackQuery(cb.ID, h.errText(userID, err), true)
 		return
 	}
 
 	if us.QuestionData != nil && cb.Message != nil {
-		text := fmt.Sprintf("❓ <b>Вопрос %d из %d</b>\n\n%s\n\n✅ <b>Ответ принят (выбрано: %d)</b>",
-			us.CurrentQNum, us.TotalQuestions, us.QuestionData.Text, len(us.SelectedOptionIDs))
+		text := questionText(lang, us.QuestionData, us.CurrentQNum, us.TotalQuestions,
+			i18n.T(lang, "answer.multi_accepted", len(us.SelectedOptionIDs)))
 		h.client.EditMessageText(cb.Message.Chat.ID, cb.Message.MessageID, text, "HTML", nil)
 	}
 
-	if h.hub != nil {
-		h.hub.Broadcast(uint(sessionID), ws.WSMessage{
-			Type: "answer_received",
-			Data: gin.H{"session_id": sessionID},
-		})
-	}
-
-	h.client.AnswerCallbackQuery(cb.ID, "✅ Ответ принят!", false)
+	h.client.AnswerCallbackQuery(cb.ID, i18n.T(lang, "answer.accepted"), false)
 }
 
 // ─── Helpers ───
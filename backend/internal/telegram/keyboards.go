@@ -1,57 +1,62 @@
 package telegram
 
-import "fmt"
+import (
+	"fmt"
 
-func MainMenuKeyboard() *ReplyKeyboardMarkup {
+	"quiz-game-backend/internal/i18n"
+	"quiz-game-backend/internal/services"
+)
+
+func MainMenuKeyboard(lang string) *ReplyKeyboardMarkup {
 	return &ReplyKeyboardMarkup{
 		Keyboard: [][]KeyboardButton{
-			{{Text: "🎮 Войти в квиз"}},
-			{{Text: "👤 Мой профиль"}, {Text: "📊 История игр"}},
-			{{Text: "🎯 Пульт ведущего"}},
+			{{Text: i18n.T(lang, "btn.join")}},
+			{{Text: i18n.T(lang, "btn.profile")}, {Text: i18n.T(lang, "btn.history")}},
+			{{Text: i18n.T(lang, "btn.host")}},
 		},
 		ResizeKeyboard: true,
 	}
 }
 
-func SessionMenuKeyboard() *ReplyKeyboardMarkup {
+func SessionMenuKeyboard(lang string) *ReplyKeyboardMarkup {
 	return &ReplyKeyboardMarkup{
 		Keyboard: [][]KeyboardButton{
-			{{Text: "🔄 Переподключиться"}},
-			{{Text: "🎮 Войти в квиз"}},
+			{{Text: i18n.T(lang, "btn.rejoin")}},
+			{{Text: i18n.T(lang, "btn.join")}},
 		},
 		ResizeKeyboard: true,
 	}
 }
 
-func HostControlKeyboard(sessionID uint, status string, current, total int) *InlineKeyboardMarkup {
+func HostControlKeyboard(lang string, sessionID uint, status string, current, total int) *InlineKeyboardMarkup {
 	var rows [][]InlineKeyboardButton
 
 	switch status {
 	case "waiting":
 		rows = append(rows, []InlineKeyboardButton{
-			{Text: "▶️ Начать квиз", CallbackData: fmt.Sprintf("host:next:%d", sessionID)},
+			{Text: i18n.T(lang, "kb.start_quiz"), CallbackData: fmt.Sprintf("host:next:%d", sessionID)},
 		})
 	case "question":
 		rows = append(rows, []InlineKeyboardButton{
-			{Text: "👁 Показать ответ", CallbackData: fmt.Sprintf("host:reveal:%d", sessionID)},
+			{Text: i18n.T(lang, "kb.reveal"), CallbackData: fmt.Sprintf("host:reveal:%d", sessionID)},
 		})
 		rows = append(rows, []InlineKeyboardButton{
-			{Text: "⏭ Завершить квиз", CallbackData: fmt.Sprintf("host:finish:%d", sessionID)},
+			{Text: i18n.T(lang, "kb.stop_quiz"), CallbackData: fmt.Sprintf("host:finish:%d", sessionID)},
 		})
 	case "revealed":
 		if current < total {
 			rows = append(rows, []InlineKeyboardButton{
-				{Text: "➡️ Следующий вопрос", CallbackData: fmt.Sprintf("host:next:%d", sessionID)},
+				{Text: i18n.T(lang, "kb.next"), CallbackData: fmt.Sprintf("host:next:%d", sessionID)},
 			})
 		}
 		rows = append(rows, []InlineKeyboardButton{
-			{Text: "🏆 Завершить квиз", CallbackData: fmt.Sprintf("host:finish:%d", sessionID)},
+			{Text: i18n.T(lang, "kb.finish_quiz"), CallbackData: fmt.Sprintf("host:finish:%d", sessionID)},
 		})
 	}
 
 	rows = append(rows, []InlineKeyboardButton{
-		{Text: "🔄 Обновить", CallbackData: fmt.Sprintf("host:refresh:%d", sessionID)},
-		{Text: "🔙 К комнате", CallbackData: fmt.Sprintf("host:backroom:%d", sessionID)},
+		{Text: i18n.T(lang, "kb.refresh"), CallbackData: fmt.Sprintf("host:refresh:%d", sessionID)},
+		{Text: i18n.T(lang, "kb.back_room"), CallbackData: fmt.Sprintf("host:backroom:%d", sessionID)},
 	})
 
 	return &InlineKeyboardMarkup{InlineKeyboard: rows}
@@ -62,7 +67,7 @@ type RoomPickItem struct {
 	Label  string
 }
 
-func HostRoomPickKeyboard(rooms []RoomPickItem) *InlineKeyboardMarkup {
+func HostRoomPickKeyboard(lang string, rooms []RoomPickItem) *InlineKeyboardMarkup {
 	var rows [][]InlineKeyboardButton
 	for _, r := range rooms {
 		rows = append(rows, []InlineKeyboardButton{
@@ -70,24 +75,48 @@ func HostRoomPickKeyboard(rooms []RoomPickItem) *InlineKeyboardMarkup {
 		})
 	}
 	rows = append(rows, []InlineKeyboardButton{
-		{Text: "➕ Новая комната", CallbackData: "host:newroom:0"},
+		{Text: i18n.T(lang, "kb.new_room"), CallbackData: "host:newroom:0"},
+		{Text: i18n.T(lang, "kb.quizzes"), CallbackData: "host:quizzes:0"},
 	})
 	return &InlineKeyboardMarkup{InlineKeyboard: rows}
 }
 
-func HostRoomControlKeyboard(roomID uint, hasSession bool) *InlineKeyboardMarkup {
+func HostRoomControlKeyboard(lang string, roomID uint, hasSession bool) *InlineKeyboardMarkup {
 	var rows [][]InlineKeyboardButton
 	if !hasSession {
 		rows = append(rows, []InlineKeyboardButton{
-			{Text: "📋 Выбрать квиз", CallbackData: fmt.Sprintf("host:pickquiz:%d:0", roomID)},
+			{Text: i18n.T(lang, "kb.pick_quiz"), CallbackData: fmt.Sprintf("host:pickquiz:%d:0", roomID)},
 		})
 	}
 	rows = append(rows, []InlineKeyboardButton{
-		{Text: "🔄 Обновить", CallbackData: fmt.Sprintf("host:roomrefresh:%d", roomID)},
+		{Text: i18n.T(lang, "kb.players"), CallbackData: fmt.Sprintf("host:players:%d", roomID)},
+		{Text: i18n.T(lang, "kb.refresh"), CallbackData: fmt.Sprintf("host:roomrefresh:%d", roomID)},
+	})
+	rows = append(rows, []InlineKeyboardButton{
+		{Text: i18n.T(lang, "kb.close_room"), CallbackData: fmt.Sprintf("host:closeroom:%d", roomID)},
+		{Text: i18n.T(lang, "kb.back_rooms"), CallbackData: "host:rooms:0"},
 	})
+	return &InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+type PlayerPickItem struct {
+	Ref      string // "m<memberID>" or "t<telegramID>"
+	Nickname string
+}
+
+func HostPlayersKeyboard(lang string, roomID uint, players []PlayerPickItem) *InlineKeyboardMarkup {
+	var rows [][]InlineKeyboardButton
+	for _, p := range players {
+		rows = append(rows, []InlineKeyboardButton{
+			{Text: p.Nickname, CallbackData: "host:noop:0"},
+			{Text: "✏️", CallbackData: fmt.Sprintf("host:rename:%d:%s", roomID, p.Ref)},
+			{Text: "🚪", CallbackData: fmt.Sprintf("host:kick:%d:%s", roomID, p.Ref)},
+			{Text: "⛔", CallbackData: fmt.Sprintf("host:ban:%d:%s", roomID, p.Ref)},
+		})
+	}
 	rows = append(rows, []InlineKeyboardButton{
-		{Text: "❌ Закрыть комнату", CallbackData: fmt.Sprintf("host:closeroom:%d", roomID)},
-		{Text: "🔙 К комнатам", CallbackData: "host:rooms:0"},
+		{Text: i18n.T(lang, "kb.refresh"), CallbackData: fmt.Sprintf("host:players:%d", roomID)},
+		{Text: i18n.T(lang, "kb.back_room"), CallbackData: fmt.Sprintf("host:room:%d", roomID)},
 	})
 	return &InlineKeyboardMarkup{InlineKeyboard: rows}
 }
@@ -97,7 +126,7 @@ type QuizPickItem struct {
 	Label  string
 }
 
-func HostQuizPickKeyboard(roomID uint, quizzes []QuizPickItem, page, totalPages int) *InlineKeyboardMarkup {
+func HostQuizPickKeyboard(lang string, roomID uint, quizzes []QuizPickItem, page, totalPages int) *InlineKeyboardMarkup {
 	var rows [][]InlineKeyboardButton
 	for _, q := range quizzes {
 		rows = append(rows, []InlineKeyboardButton{
@@ -123,11 +152,70 @@ func HostQuizPickKeyboard(roomID uint, quizzes []QuizPickItem, page, totalPages
 		rows = append(rows, navRow)
 	}
 	rows = append(rows, []InlineKeyboardButton{
-		{Text: "🔙 К комнате", CallbackData: fmt.Sprintf("host:room:%d", roomID)},
+		{Text: i18n.T(lang, "kb.back_room"), CallbackData: fmt.Sprintf("host:room:%d", roomID)},
 	})
 	return &InlineKeyboardMarkup{InlineKeyboard: rows}
 }
 
+// HostQuizListKeyboard lists the host's quizzes for editing, followed by the
+// ways to add one.
+func HostQuizListKeyboard(lang string, quizzes []QuizPickItem, page, totalPages int, aiAvailable bool) *InlineKeyboardMarkup {
+	var rows [][]InlineKeyboardButton
+	for _, q := range quizzes {
+		rows = append(rows, []InlineKeyboardButton{
+			{Text: q.Label, CallbackData: fmt.Sprintf("host:quiz:%d", q.QuizID)},
+		})
+	}
+	if totalPages > 1 {
+		var navRow []InlineKeyboardButton
+		if page > 0 {
+			navRow = append(navRow, InlineKeyboardButton{Text: "◀️", CallbackData: fmt.Sprintf("host:quizzes:%d", page-1)})
+		}
+		navRow = append(navRow, InlineKeyboardButton{Text: fmt.Sprintf("%d/%d", page+1, totalPages), CallbackData: "host:noop:0"})
+		if page < totalPages-1 {
+			navRow = append(navRow, InlineKeyboardButton{Text: "▶️", CallbackData: fmt.Sprintf("host:quizzes:%d", page+1)})
+		}
+		rows = append(rows, navRow)
+	}
+	rows = append(rows, []InlineKeyboardButton{
+		{Text: i18n.T(lang, "kb.new_quiz"), CallbackData: "host:newquiz:0"},
+	})
+	addRow := []InlineKeyboardButton{{Text: i18n.T(lang, "kb.import_quiz"), CallbackData: "host:import:0"}}
+	if aiAvailable {
+		addRow = append(addRow, InlineKeyboardButton{Text: i18n.T(lang, "kb.ai_quiz"), CallbackData: "host:ai:0"})
+	}
+	rows = append(rows, addRow)
+	rows = append(rows, []InlineKeyboardButton{
+		{Text: i18n.T(lang, "kb.back_rooms"), CallbackData: "host:rooms:0"},
+	})
+	return &InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+func HostQuizCardKeyboard(lang string, quizID uint) *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{
+		{{Text: i18n.T(lang, "kb.add_question"), CallbackData: fmt.Sprintf("host:addq:%d", quizID)}},
+		{{Text: i18n.T(lang, "kb.play_quiz"), CallbackData: fmt.Sprintf("host:playquiz:%d", quizID)}},
+		{{Text: i18n.T(lang, "kb.back_quizzes"), CallbackData: "host:quizzes:0"}},
+	}}
+}
+
+func HostQuestionTypeKeyboard(lang string, quizID uint) *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{
+		{{Text: i18n.T(lang, "kb.type_single"), CallbackData: fmt.Sprintf("host:qtype:%d:single_choice", quizID)}},
+		{{Text: i18n.T(lang, "kb.type_multi"), CallbackData: fmt.Sprintf("host:qtype:%d:multiple_choice", quizID)}},
+		{{Text: i18n.T(lang, "kb.type_numeric"), CallbackData: fmt.Sprintf("host:qtype:%d:numeric", quizID)}},
+		{{Text: i18n.T(lang, "kb.cancel"), CallbackData: fmt.Sprintf("host:quiz:%d", quizID)}},
+	}}
+}
+
+// HostCancelKeyboard sits under a prompt for typed input; the button leads
+// back without saving.
+func HostCancelKeyboard(lang string, callbackData string) *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{
+		{{Text: i18n.T(lang, "kb.cancel"), CallbackData: callbackData}},
+	}}
+}
+
 type SessionPickItem struct {
 	SessionID uint
 	Label     string
@@ -157,7 +245,7 @@ func AnswerKeyboard(sessionID uint, options []QuestionOption, selectedID uint) *
 	return &InlineKeyboardMarkup{InlineKeyboard: rows}
 }
 
-func MultiChoiceKeyboard(sessionID uint, options []QuestionOption, selectedIDs []uint) *InlineKeyboardMarkup {
+func MultiChoiceKeyboard(lang string, sessionID uint, options []QuestionOption, selectedIDs []uint) *InlineKeyboardMarkup {
 	selected := make(map[uint]bool)
 	for _, id := range selectedIDs {
 		selected[id] = true
@@ -175,7 +263,157 @@ func MultiChoiceKeyboard(sessionID uint, options []QuestionOption, selectedIDs [
 		})
 	}
 	rows = append(rows, []InlineKeyboardButton{
-		{Text: "✅ Подтвердить", CallbackData: fmt.Sprintf("multisubmit:%d:0", sessionID)},
+		{Text: i18n.T(lang, "kb.confirm"), CallbackData: fmt.Sprintf("multisubmit:%d:0", sessionID)},
+	})
+	return &InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// ReactionKeyboard is a single row of emoji attached to result messages in room sessions.
+func ReactionKeyboard(roomID uint) *InlineKeyboardMarkup {
+	row := make([]InlineKeyboardButton, len(services.Reactions))
+	for i, emoji := range services.Reactions {
+		row[i] = InlineKeyboardButton{Text: emoji, CallbackData: fmt.Sprintf("react:%d:%d", roomID, i)}
+	}
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{row}}
+}
+
+// OrderingKeyboard shows the options not placed yet; tapping one appends it to the order.
+func OrderingKeyboard(lang string, sessionID uint, options []QuestionOption, order []uint) *InlineKeyboardMarkup {
+	placed := make(map[uint]bool, len(order))
+	for _, id := range order {
+		placed[id] = true
+	}
+	var rows [][]InlineKeyboardButton
+	for _, opt := range options {
+		if placed[opt.ID] {
+			continue
+		}
+		rows = append(rows, []InlineKeyboardButton{
+			{Text: opt.Text, CallbackData: fmt.Sprintf("ord:%d:%d", sessionID, opt.ID)},
+		})
+	}
+	rows = appendControlRow(lang, rows, "ord", sessionID, len(order) > 0, len(order) == len(options))
+	return &InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// MatchingKeyboard offers the right-hand choices not used yet for the current left item.
+func MatchingKeyboard(lang string, sessionID uint, choices []string, picks []int, total int) *InlineKeyboardMarkup {
+	used := make(map[int]bool, len(picks))
+	for _, idx := range picks {
+		used[idx] = true
+	}
+	var rows [][]InlineKeyboardButton
+	if len(picks) < total {
+		for i, choice := range choices {
+			if used[i] {
+				continue
+			}
+			rows = append(rows, []InlineKeyboardButton{
+				{Text: choice, CallbackData: fmt.Sprintf("match:%d:%d", sessionID, i)},
+			})
+		}
+	}
+	rows = appendControlRow(lang, rows, "match", sessionID, len(picks) > 0, len(picks) == total)
+	return &InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+func appendControlRow(lang string, rows [][]InlineKeyboardButton, prefix string, sessionID uint, canUndo, complete bool) [][]InlineKeyboardButton {
+	var row []InlineKeyboardButton
+	if canUndo {
+		row = append(row, InlineKeyboardButton{Text: i18n.T(lang, "kb.undo"), CallbackData: fmt.Sprintf("%sundo:%d:0", prefix, sessionID)})
+	}
+	if complete {
+		row = append(row, InlineKeyboardButton{Text: i18n.T(lang, "kb.confirm"), CallbackData: fmt.Sprintf("%ssubmit:%d:0", prefix, sessionID)})
+	}
+	if len(row) == 0 {
+		return rows
+	}
+	return append(rows, row)
+}
+
+// LanguageKeyboard offers the supported bot languages and a reset to the
+// Telegram client language.
+func LanguageKeyboard(lang string) *InlineKeyboardMarkup {
+	var row []InlineKeyboardButton
+	for _, locale := range i18n.Supported {
+		row = append(row, InlineKeyboardButton{Text: i18n.T(locale, "lang.name"), CallbackData: "lang:" + locale})
+	}
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{
+		row,
+		{{Text: i18n.T(lang, "kb.lang_auto"), CallbackData: "lang:auto"}},
+	}}
+}
+
+// GroupQuizPickKeyboard lists the host's quizzes in a group chat.
+func GroupQuizPickKeyboard(quizzes []QuizPickItem, page, totalPages int) *InlineKeyboardMarkup {
+	var rows [][]InlineKeyboardButton
+	for _, q := range quizzes {
+		rows = append(rows, []InlineKeyboardButton{
+			{Text: q.Label, CallbackData: fmt.Sprintf("grp:quiz:%d", q.QuizID)},
+		})
+	}
+	if totalPages > 1 {
+		var navRow []InlineKeyboardButton
+		if page > 0 {
+			navRow = append(navRow, InlineKeyboardButton{Text: "◀️", CallbackData: fmt.Sprintf("grp:page:%d", page-1)})
+		}
+		navRow = append(navRow, InlineKeyboardButton{Text: fmt.Sprintf("%d/%d", page+1, totalPages), CallbackData: "grp:noop:0"})
+		if page < totalPages-1 {
+			navRow = append(navRow, InlineKeyboardButton{Text: "▶️", CallbackData: fmt.Sprintf("grp:page:%d", page+1)})
+		}
+		rows = append(rows, navRow)
+	}
+	return &InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// GroupLobbyKeyboard lets group members join and the host start or cancel.
+func GroupLobbyKeyboard(lang string, sessionID uint) *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{
+		{{Text: i18n.T(lang, "kb.join"), CallbackData: fmt.Sprintf("gjoin:%d", sessionID)}},
+		{
+			{Text: i18n.T(lang, "kb.start_quiz"), CallbackData: fmt.Sprintf("grp:start:%d", sessionID)},
+			{Text: i18n.T(lang, "kb.cancel"), CallbackData: fmt.Sprintf("grp:finish:%d", sessionID)},
+		},
+	}}
+}
+
+// GroupQuestionKeyboard is the shared answer keyboard of a group question.
+// Only single-choice questions can be answered in the group; the others link
+// to the private chat with the bot when dmLink is set.
+func GroupQuestionKeyboard(lang string, qd *QuestionData, dmLink string) *InlineKeyboardMarkup {
+	var rows [][]InlineKeyboardButton
+	if qd.Type == "single_choice" {
+		for _, opt := range qd.Options {
+			rows = append(rows, []InlineKeyboardButton{
+				{Text: opt.Text, CallbackData: fmt.Sprintf("gans:%d:%d", qd.SessionID, opt.ID)},
+			})
+		}
+	} else if dmLink != "" {
+		rows = append(rows, []InlineKeyboardButton{{Text: i18n.T(lang, "kb.answer_dm"), URL: dmLink}})
+	}
+	rows = append(rows, []InlineKeyboardButton{
+		{Text: i18n.T(lang, "kb.reveal"), CallbackData: fmt.Sprintf("grp:reveal:%d", qd.SessionID)},
+	})
+	return &InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// GroupPollKeyboard is the host button under a group quiz poll.
+func GroupPollKeyboard(lang string, sessionID uint) *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{
+		{{Text: i18n.T(lang, "kb.reveal"), CallbackData: fmt.Sprintf("grp:reveal:%d", sessionID)}},
+	}}
+}
+
+// GroupRevealKeyboard holds the host buttons under a revealed group question.
+func GroupRevealKeyboard(lang string, sessionID uint, current, total int) *InlineKeyboardMarkup {
+	var rows [][]InlineKeyboardButton
+	if current < total {
+		rows = append(rows, []InlineKeyboardButton{
+			{Text: i18n.T(lang, "kb.next"), CallbackData: fmt.Sprintf("grp:next:%d", sessionID)},
+		})
+	}
+	rows = append(rows, []InlineKeyboardButton{
+		{Text: i18n.T(lang, "kb.finish_quiz"), CallbackData: fmt.Sprintf("grp:finish:%d", sessionID)},
 	})
 	return &InlineKeyboardMarkup{InlineKeyboard: rows}
 }
@@ -1,12 +1,12 @@
 package telegram
 
 import (
-	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -26,19 +26,31 @@ type BotInstance struct {
 	State   *StateManager
 	Tracker *SessionTracker
 	Handler *UpdateHandler
+
+	cancelPoll func()
+	pollDone   chan struct{}
 }
 
 type BotManager struct {
 	db              *gorm.DB
+	tokens          *services.BotTokenService
 	sessionSvc      *services.SessionService
 	roomSvc         *services.RoomService
 	quizSvc         *services.QuizService
 	tgUserSvc       *services.TelegramUserService
+	presenceSvc     *services.PresenceService
+	chatSvc         *services.ChatService
+	aiSvc           *services.AIGenerateService
+	remoteAuth      *services.RemoteAuthService
+	audit           *services.AuditService
 	hub             *ws.Hub
 	webhookBaseURL  string
 	webhookSecret   string
+	mode            string
+	apiURL          string
 	pollInterval    time.Duration
 	refreshInterval time.Duration
+	stateTTL        time.Duration
 
 	mu   sync.RWMutex
 	bots map[string]*BotInstance // secret -> bot
@@ -48,41 +60,99 @@ type BotManager struct {
 
 func NewBotManager(
 	db *gorm.DB,
+	tokens *services.BotTokenService,
 	sessionSvc *services.SessionService,
 	roomSvc *services.RoomService,
 	quizSvc *services.QuizService,
 	tgUserSvc *services.TelegramUserService,
+	presenceSvc *services.PresenceService,
+	chatSvc *services.ChatService,
+	aiSvc *services.AIGenerateService,
+	remoteAuth *services.RemoteAuthService,
+	audit *services.AuditService,
 	hub *ws.Hub,
 	webhookBaseURL string,
 	webhookSecret string,
+	mode string,
+	apiURL string,
 	pollInterval time.Duration,
 	refreshInterval time.Duration,
+	stateTTL time.Duration,
 ) *BotManager {
-	return &BotManager{
+	m := &BotManager{
 		db:              db,
+		tokens:          tokens,
 		sessionSvc:      sessionSvc,
 		roomSvc:         roomSvc,
 		quizSvc:         quizSvc,
 		tgUserSvc:       tgUserSvc,
+		presenceSvc:     presenceSvc,
+		chatSvc:         chatSvc,
+		aiSvc:           aiSvc,
+		remoteAuth:      remoteAuth,
+		audit:           audit,
 		hub:             hub,
 		webhookBaseURL:  webhookBaseURL,
 		webhookSecret:   webhookSecret,
+		mode:            mode,
+		apiURL:          apiURL,
 		pollInterval:    pollInterval,
 		refreshInterval: refreshInterval,
+		stateTTL:        stateTTL,
 		bots:            make(map[string]*BotInstance),
 		stopCh:          make(chan struct{}),
 	}
+	roomSvc.OnKick(m.onKick)
+	return m
+}
+
+// onKick forwards a kick of a Telegram player to the bot of the room's host.
+func (m *BotManager) onKick(k services.KickResult) {
+	if k.TelegramID == 0 {
+		return
+	}
+	var room models.Room
+	if err := m.db.First(&room, k.RoomID).Error; err != nil {
+		return
+	}
+
+	m.mu.RLock()
+	var handler *UpdateHandler
+	for _, bot := range m.bots {
+		if bot.HostID == room.HostID {
+			handler = bot.Handler
+			break
+		}
+	}
+	m.mu.RUnlock()
+
+	if handler != nil {
+		go handler.notifyKicked(k)
+	}
 }
 
 func tokenSecret(token string) string {
-	h := sha256.Sum256([]byte(token))
-	return fmt.Sprintf("%x", h[:16])
+	return services.BotKey(token)
+}
+
+// RemoveHost stops the bot of a deleted account without waiting for the next
+// token refresh.
+func (m *BotManager) RemoveHost(hostID uint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for secret, bot := range m.bots {
+		if bot.HostID == hostID {
+			log.Printf("[BotManager] removing bot for host %d", hostID)
+			go m.shutdownBot(bot)
+			delete(m.bots, secret)
+		}
+	}
 }
 
 func (m *BotManager) Start() {
 	m.refreshTokens()
 	go m.refreshLoop()
-	log.Println("[BotManager] started")
+	log.Printf("[BotManager] started (%s mode)", m.mode)
 }
 
 func (m *BotManager) Stop() {
@@ -91,34 +161,64 @@ func (m *BotManager) Stop() {
 	defer m.mu.Unlock()
 
 	for _, bot := range m.bots {
-		bot.Tracker.Stop()
-		bot.Client.DeleteWebhook()
+		m.shutdownBot(bot)
 	}
 	m.bots = make(map[string]*BotInstance)
 	log.Println("[BotManager] stopped")
 }
 
+// shutdownBot stops update delivery and session tracking for a bot.
+func (m *BotManager) shutdownBot(bot *BotInstance) {
+	if m.mode == BotModePolling {
+		bot.stopPolling()
+	} else {
+		bot.Client.DeleteWebhook()
+	}
+	bot.Tracker.Stop()
+	bot.Client.Close()
+}
+
 func (m *BotManager) refreshLoop() {
 	ticker := time.NewTicker(m.refreshInterval)
 	defer ticker.Stop()
+	cleanup := time.NewTicker(time.Hour)
+	defer cleanup.Stop()
 	for {
 		select {
 		case <-m.stopCh:
 			return
 		case <-ticker.C:
 			m.refreshTokens()
+		case <-cleanup.C:
+			m.cleanupStates()
 		}
 	}
 }
 
+// cleanupStates drops conversation states idle for longer than the TTL.
+func (m *BotManager) cleanupStates() {
+	if n := CleanupTelegramStates(m.db, m.stateTTL); n > 0 {
+		log.Printf("[BotManager] removed %d idle conversation states", n)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, bot := range m.bots {
+		bot.State.evictIdle()
+	}
+}
+
 func (m *BotManager) refreshTokens() {
-	var hosts []models.Host
-	m.db.Where("bot_token != '' AND bot_token IS NOT NULL").Find(&hosts)
+	bots, err := m.tokens.Active()
+	if err != nil {
+		log.Printf("[BotManager] loading bot tokens: %v", err)
+		if bots == nil {
+			return
+		}
+	}
 
-	newSecrets := make(map[string]models.Host)
-	for _, h := range hosts {
-		secret := tokenSecret(h.BotToken)
-		newSecrets[secret] = h
+	newSecrets := make(map[string]services.BotCredentials)
+	for _, b := range bots {
+		newSecrets[tokenSecret(b.Token)] = b
 	}
 
 	m.mu.Lock()
@@ -127,24 +227,28 @@ func (m *BotManager) refreshTokens() {
 	for secret, bot := range m.bots {
 		if _, exists := newSecrets[secret]; !exists {
 			log.Printf("[BotManager] removing bot for host %d", bot.HostID)
-			bot.Tracker.Stop()
-			go bot.Client.DeleteWebhook()
+			go m.shutdownBot(bot)
 			delete(m.bots, secret)
 		}
 	}
 
-	for secret, host := range newSecrets {
-		if _, exists := m.bots[secret]; exists {
+	for secret, creds := range newSecrets {
+		host := creds.Host
+		if bot, exists := m.bots[secret]; exists {
+			bot.State.SetDefaultLocale(host.BotLanguage)
+			bot.Tracker.SetQuizPolls(host.BotQuizPolls)
 			continue
 		}
 
-		client := NewClient(host.BotToken)
-		stateM := NewStateManager()
-		tracker := NewSessionTracker(client, stateM, m.sessionSvc, m.pollInterval)
-		handler := NewUpdateHandler(client, stateM, tracker, m.sessionSvc, m.roomSvc, m.quizSvc, m.tgUserSvc, m.hub, m.db, host.ID)
+		client := NewClient(m.apiURL, creds.Token)
+		stateM := NewStateManager(m.db, secret)
+		stateM.SetDefaultLocale(host.BotLanguage)
+		tracker := NewSessionTracker(client, NewMediaSender(m.db, client.WithPriority(PriorityQuestion), secret), stateM, m.sessionSvc, m.pollInterval)
+		tracker.SetQuizPolls(host.BotQuizPolls)
+		handler := NewUpdateHandler(client, stateM, tracker, m.sessionSvc, m.roomSvc, m.quizSvc, m.tgUserSvc, m.presenceSvc, m.chatSvc, m.aiSvc, m.remoteAuth, m.audit, m.hub, m.db, host.ID)
 
 		bot := &BotInstance{
-			Token:   host.BotToken,
+			Token:   creds.Token,
 			Secret:  secret,
 			HostID:  host.ID,
 			Client:  client,
@@ -153,9 +257,20 @@ func (m *BotManager) refreshTokens() {
 			Handler: handler,
 		}
 
+		go handler.RestoreSessions()
+
+		if m.mode == BotModePolling {
+			bot.startPolling()
+			m.bots[secret] = bot
+			log.Printf("[BotManager] registered bot for host %d (long polling)", host.ID)
+			continue
+		}
+
 		webhookURL := fmt.Sprintf("%s/webhook/bot/%s", m.webhookBaseURL, secret)
 		if err := client.SetWebhook(webhookURL, m.webhookSecret); err != nil {
 			log.Printf("[BotManager] failed to set webhook for host %d: %v", host.ID, err)
+			tracker.Stop()
+			client.Close()
 			continue
 		}
 
@@ -166,7 +281,36 @@ func (m *BotManager) refreshTokens() {
 	log.Printf("[BotManager] active bots: %d", len(m.bots))
 }
 
+type BotMetrics struct {
+	HostID uint          `json:"host_id"`
+	Outbox OutboxMetrics `json:"outbox"`
+}
+
+// Metrics godoc
+// @Summary      Telegram delivery metrics
+// @Description  Per-bot outbound queue depth, 429 retries, and delivery counts and latency per priority
+// @Tags         internal
+// @Produce      json
+// @Param        X-Bot-API-Key header string true "Bot API Key"
+// @Success      200 {array} telegram.BotMetrics
+// @Router       /api/v1/internal/bot-metrics [get]
+func (m *BotManager) Metrics(c *gin.Context) {
+	m.mu.RLock()
+	result := make([]BotMetrics, 0, len(m.bots))
+	for _, bot := range m.bots {
+		result = append(result, BotMetrics{HostID: bot.HostID, Outbox: bot.Client.Metrics()})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool { return result[i].HostID < result[j].HostID })
+	c.JSON(http.StatusOK, result)
+}
+
 func (m *BotManager) HandleWebhook(c *gin.Context) {
+	if m.mode == BotModePolling {
+		c.Status(http.StatusNotFound)
+		return
+	}
 	secret := c.Param("secret")
 
 	if m.webhookSecret != "" {
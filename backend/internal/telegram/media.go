@@ -0,0 +1,191 @@
+package telegram
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bots may upload files of at most 50 MB through the Bot API.
+const maxUploadSize = 50 << 20
+
+var uploadClient = &http.Client{Timeout: 5 * time.Minute}
+
+// MediaFile is a file to send to Telegram: a cached FileID, a public URL,
+// or a local Path that is uploaded with a multipart request.
+type MediaFile struct {
+	Type   string // "image", "audio" or "video", as stored on QuestionImage
+	FileID string
+	URL    string
+	Path   string
+}
+
+func (f MediaFile) telegramType() string {
+	switch f.Type {
+	case "audio":
+		return "audio"
+	case "video":
+		return "video"
+	}
+	return "photo"
+}
+
+// reference returns what to put into the media field when no upload is needed.
+func (f MediaFile) reference() string {
+	if f.FileID != "" {
+		return f.FileID
+	}
+	return f.URL
+}
+
+func (c *Client) SendPhoto(chatID int64, file MediaFile, caption, parseMode string, replyMarkup interface{}) (*MediaMessageResult, error) {
+	file.Type = "image"
+	return c.sendMedia(chatID, file, caption, parseMode, replyMarkup)
+}
+
+func (c *Client) SendAudio(chatID int64, file MediaFile, caption, parseMode string, replyMarkup interface{}) (*MediaMessageResult, error) {
+	file.Type = "audio"
+	return c.sendMedia(chatID, file, caption, parseMode, replyMarkup)
+}
+
+func (c *Client) SendVideo(chatID int64, file MediaFile, caption, parseMode string, replyMarkup interface{}) (*MediaMessageResult, error) {
+	file.Type = "video"
+	return c.sendMedia(chatID, file, caption, parseMode, replyMarkup)
+}
+
+func (c *Client) sendMedia(chatID int64, file MediaFile, caption, parseMode string, replyMarkup interface{}) (*MediaMessageResult, error) {
+	field := file.telegramType()
+	method := "send" + strings.ToUpper(field[:1]) + field[1:]
+
+	fields := map[string]string{"chat_id": fmt.Sprint(chatID)}
+	if caption != "" {
+		fields["caption"] = caption
+		fields["parse_mode"] = parseMode
+	}
+	if replyMarkup != nil {
+		rm, err := json.Marshal(replyMarkup)
+		if err != nil {
+			return nil, err
+		}
+		fields["reply_markup"] = string(rm)
+	}
+
+	uploads := map[string]string{}
+	if ref := file.reference(); ref != "" {
+		fields[field] = ref
+	} else {
+		uploads[field] = file.Path
+	}
+
+	result, err := c.callMultipart(method, fields, uploads)
+	if err != nil {
+		return nil, err
+	}
+	var msg MediaMessageResult
+	if err := json.Unmarshal(result, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// SendMediaGroup sends 2-10 files as an album. Audio can only be grouped with audio.
+func (c *Client) SendMediaGroup(chatID int64, files []MediaFile) ([]MediaMessageResult, error) {
+	if len(files) < 2 || len(files) > 10 {
+		return nil, errors.New("media group must have 2 to 10 items")
+	}
+
+	media := make([]InputMedia, len(files))
+	uploads := map[string]string{}
+	for i, f := range files {
+		media[i] = InputMedia{Type: f.telegramType(), Media: f.reference()}
+		if media[i].Media == "" {
+			name := fmt.Sprintf("file%d", i)
+			media[i].Media = "attach://" + name
+			uploads[name] = f.Path
+		}
+	}
+	mediaJSON, err := json.Marshal(media)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{"chat_id": fmt.Sprint(chatID), "media": string(mediaJSON)}
+	result, err := c.callMultipart("sendMediaGroup", fields, uploads)
+	if err != nil {
+		return nil, err
+	}
+	var msgs []MediaMessageResult
+	if err := json.Unmarshal(result, &msgs); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// callMultipart posts form fields plus local files (form field name -> path).
+// The body is streamed so large videos are not held in memory.
+func (c *Client) callMultipart(method string, fields, uploads map[string]string) (json.RawMessage, error) {
+	for _, path := range uploads {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("media: %w", err)
+		}
+		if info.Size() > maxUploadSize {
+			return nil, fmt.Errorf("media: %s is larger than 50 MB", filepath.Base(path))
+		}
+	}
+
+	post := func() (json.RawMessage, error) {
+		pr, pw := io.Pipe()
+		mw := multipart.NewWriter(pw)
+		go func() {
+			pw.CloseWithError(writeMultipart(mw, fields, uploads))
+		}()
+
+		resp, err := uploadClient.Post(c.baseURL+"/"+method, mw.FormDataContentType(), pr)
+		if err != nil {
+			pr.Close()
+			return nil, fmt.Errorf("http: %w", err)
+		}
+		return decodeResponse(resp)
+	}
+
+	chatID, _ := strconv.ParseInt(fields["chat_id"], 10, 64)
+	// Every item of an album counts as a message.
+	cost := 1.0
+	var items []json.RawMessage
+	if json.Unmarshal([]byte(fields["media"]), &items) == nil && len(items) > 1 {
+		cost = float64(len(items))
+	}
+	return c.outbox.Do(chatID, c.priority, cost, post)
+}
+
+func writeMultipart(mw *multipart.Writer, fields, uploads map[string]string) error {
+	for k, v := range fields {
+		if err := mw.WriteField(k, v); err != nil {
+			return err
+		}
+	}
+	for name, path := range uploads {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		part, err := mw.CreateFormFile(name, filepath.Base(path))
+		if err == nil {
+			_, err = io.Copy(part, f)
+		}
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
@@ -0,0 +1,150 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"quiz-game-backend/internal/i18n"
+	"quiz-game-backend/internal/services"
+	"quiz-game-backend/internal/ws"
+)
+
+// showPlayers lists web members and Telegram players of a room with
+// rename/kick/ban buttons.
+func (h *UpdateHandler) showPlayers(userID, chatID int64, roomID uint, editMsgID int64) {
+	rw, err := h.roomSvc.GetRoom(roomID)
+	if err != nil || rw.HostID != h.hostID {
+		return
+	}
+
+	var items []PlayerPickItem
+	seenTG := make(map[int64]bool)
+	for _, m := range rw.Members {
+		items = append(items, PlayerPickItem{Ref: fmt.Sprintf("m%d", m.ID), Nickname: m.Nickname})
+		if m.TelegramID > 0 {
+			seenTG[m.TelegramID] = true
+		}
+	}
+	if current, _ := h.roomSvc.GetCurrentSession(roomID); current != nil {
+		entries, _ := h.sessionSvc.GetLeaderboard(current.ID)
+		for _, e := range entries {
+			if e.TelegramID > 0 && !seenTG[e.TelegramID] {
+				items = append(items, PlayerPickItem{Ref: fmt.Sprintf("t%d", e.TelegramID), Nickname: "📱 " + e.Nickname})
+			}
+		}
+	}
+
+	lang := h.state.Locale(userID)
+	text := i18n.T(lang, "players.title", rw.Room.Code) + "\n\n" + i18n.T(lang, "players.legend")
+	if len(items) == 0 {
+		text = i18n.T(lang, "players.title", rw.Room.Code) + "\n\n" + i18n.T(lang, "players.empty")
+	}
+	kb := HostPlayersKeyboard(lang, roomID, items)
+
+	if editMsgID > 0 {
+		if err := h.client.EditMessageText(chatID, editMsgID, text, "HTML", kb); err == nil {
+			return
+		}
+	}
+	h.sendAndTrack(chatID, userID, text, "HTML", kb)
+}
+
+func parsePlayerRef(ref string) (services.PlayerRef, bool) {
+	if len(ref) < 2 {
+		return services.PlayerRef{}, false
+	}
+	id, err := strconv.ParseInt(ref[1:], 10, 64)
+	if err != nil || id <= 0 {
+		return services.PlayerRef{}, false
+	}
+	switch ref[0] {
+	case 'm':
+		return services.PlayerRef{MemberID: uint(id)}, true
+	case 't':
+		return services.PlayerRef{TelegramID: id}, true
+	}
+	return services.PlayerRef{}, false
+}
+
+func (h *UpdateHandler) handlePlayerAction(cb *CallbackQuery, action string, roomID uint, rawRef string) {
+	userID := cb.From.ID
+	chatID := cb.Message.Chat.ID
+
+	ref, ok := parsePlayerRef(rawRef)
+	if !ok {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "error.bad_data"), true)
+		return
+	}
+
+	if action == "rename" {
+		h.state.UpdateField(userID, func(s *UserState) {
+			s.State = StateHostRename
+			s.RoomID = roomID
+			s.RenameTarget = rawRef
+		})
+		h.client.AnswerCallbackQuery(cb.ID, "", false)
+		h.client.SendMessage(chatID, h.tr(userID, "players.rename"), "", nil)
+		return
+	}
+
+	result, err := h.roomSvc.KickPlayer(roomID, h.hostID, ref, action == "ban")
+	if err != nil {
+		h.client.AnswerCallbackQuery(cb.ID, h.errText(userID, err), true)
+		return
+	}
+
+	if result.Banned {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "players.banned", result.Nickname), false)
+	} else {
+		h.client.AnswerCallbackQuery(cb.ID, h.tr(userID, "players.kicked", result.Nickname), false)
+	}
+	h.showPlayers(userID, chatID, roomID, cb.Message.MessageID)
+}
+
+func (h *UpdateHandler) onHostRename(userID, chatID int64, text string, us *UserState) {
+	ref, ok := parsePlayerRef(us.RenameTarget)
+	h.state.UpdateField(userID, func(s *UserState) {
+		s.State = StateHostRemote
+		s.RenameTarget = ""
+	})
+	if !ok {
+		h.showPlayers(userID, chatID, us.RoomID, 0)
+		return
+	}
+
+	result, err := h.roomSvc.RenameMember(us.RoomID, h.hostID, ref, strings.TrimSpace(text))
+	if err != nil {
+		msg := h.errText(userID, err)
+		if errors.Is(err, services.ErrNicknameBlocked) {
+			msg = h.tr(userID, "nick.not_allowed")
+		}
+		h.client.SendMessage(chatID, msg, "", nil)
+		h.showPlayers(userID, chatID, us.RoomID, 0)
+		return
+	}
+
+	if h.hub != nil {
+		h.hub.BroadcastToRoom(us.RoomID, ws.WSMessage{Type: "member_renamed", Data: result})
+	}
+	h.client.SendMessage(chatID, h.tr(userID, "players.renamed", result.Nickname), "HTML", nil)
+	h.showPlayers(userID, chatID, us.RoomID, 0)
+}
+
+// notifyKicked tells a Telegram player they were removed and drops their session state.
+func (h *UpdateHandler) notifyKicked(k services.KickResult) {
+	us := h.state.Get(k.TelegramID)
+	if us.RoomID != k.RoomID {
+		return
+	}
+	h.tracker.RemoveParticipant(k.TelegramID)
+	h.state.Clear(k.TelegramID)
+
+	lang := h.state.Locale(k.TelegramID)
+	text := i18n.T(lang, "kicked.removed")
+	if k.Banned {
+		text = i18n.T(lang, "kicked.banned")
+	}
+	h.client.SendMessage(k.TelegramID, text, "", MainMenuKeyboard(lang))
+}
@@ -0,0 +1,342 @@
+package telegram
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Priority orders queued Bot API requests; lower values are sent first.
+type Priority int
+
+const (
+	PriorityQuestion Priority = iota
+	PriorityReply
+	PriorityResult
+	PriorityHostControl
+	priorityCount
+)
+
+var priorityNames = [priorityCount]string{"question", "reply", "result", "host_control"}
+
+// Telegram allows about 30 messages per second per bot, one per second in a
+// private chat and 20 per minute in a group.
+const (
+	globalRate     = 30.0
+	globalBurst    = 30.0
+	privateRate    = 1.0
+	groupRate      = 20.0 / 60.0
+	chatBurst      = 3.0
+	maxSendRetries = 3
+	bucketIdleTTL  = time.Minute
+)
+
+var errOutboxClosed = errors.New("telegram: outbox closed")
+
+type tokenBucket struct {
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newTokenBucket(rate, burst float64, now time.Time) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// readyAt returns when n tokens will be available. Requests larger than the
+// burst wait for a full bucket and leave it in debt.
+func (b *tokenBucket) readyAt(now time.Time, n float64) time.Time {
+	b.refill(now)
+	if n > b.burst {
+		n = b.burst
+	}
+	at := now
+	if b.tokens < n {
+		at = now.Add(time.Duration((n - b.tokens) / b.rate * float64(time.Second)))
+	}
+	if b.pausedUntil.After(at) {
+		at = b.pausedUntil
+	}
+	return at
+}
+
+func (b *tokenBucket) take(n float64) {
+	b.tokens -= n
+}
+
+type outboxResult struct {
+	data json.RawMessage
+	err  error
+}
+
+type outboxJob struct {
+	chatID   int64
+	priority Priority
+	cost     float64
+	send     func() (json.RawMessage, error)
+	enqueued time.Time
+	attempts int
+	done     chan outboxResult
+}
+
+type priorityStats struct {
+	sent         int64
+	failed       int64
+	totalLatency time.Duration
+	maxLatency   time.Duration
+}
+
+// PriorityMetrics describes deliveries of one priority class.
+type PriorityMetrics struct {
+	Sent         int64   `json:"sent"`
+	Failed       int64   `json:"failed"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	MaxLatencyMs float64 `json:"max_latency_ms"`
+}
+
+// OutboxMetrics is a snapshot of a bot's outbound queue.
+type OutboxMetrics struct {
+	Queued      int                        `json:"queued"`
+	RateLimited int64                      `json:"rate_limited"`
+	Priorities  map[string]PriorityMetrics `json:"priorities"`
+}
+
+// Outbox serializes a bot's chat-bound Bot API requests through token buckets:
+// one for the whole bot and one per chat. Requests wait in per-priority FIFO
+// queues and are retried after Telegram's retry_after on HTTP 429.
+type Outbox struct {
+	mu          sync.Mutex
+	queues      [priorityCount][]*outboxJob
+	global      *tokenBucket
+	chats       map[int64]*tokenBucket
+	stats       [priorityCount]priorityStats
+	rateLimited int64
+	lastPrune   time.Time
+	closed      bool
+
+	wake   chan struct{}
+	stopCh chan struct{}
+}
+
+func NewOutbox() *Outbox {
+	now := time.Now()
+	o := &Outbox{
+		global:    newTokenBucket(globalRate, globalBurst, now),
+		chats:     make(map[int64]*tokenBucket),
+		lastPrune: now,
+		wake:      make(chan struct{}, 1),
+		stopCh:    make(chan struct{}),
+	}
+	go o.dispatch()
+	return o
+}
+
+// Do queues a request to a chat and blocks until it was sent or failed.
+func (o *Outbox) Do(chatID int64, priority Priority, cost float64, send func() (json.RawMessage, error)) (json.RawMessage, error) {
+	job := &outboxJob{
+		chatID:   chatID,
+		priority: priority,
+		cost:     cost,
+		send:     send,
+		enqueued: time.Now(),
+		done:     make(chan outboxResult, 1),
+	}
+
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return nil, errOutboxClosed
+	}
+	o.queues[priority] = append(o.queues[priority], job)
+	o.mu.Unlock()
+	o.signal()
+
+	res := <-job.done
+	return res.data, res.err
+}
+
+// Close stops the dispatcher and fails every queued request.
+func (o *Outbox) Close() {
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return
+	}
+	o.closed = true
+	var pending []*outboxJob
+	for p := range o.queues {
+		pending = append(pending, o.queues[p]...)
+		o.queues[p] = nil
+	}
+	o.mu.Unlock()
+
+	close(o.stopCh)
+	for _, job := range pending {
+		job.done <- outboxResult{err: errOutboxClosed}
+	}
+}
+
+func (o *Outbox) Metrics() OutboxMetrics {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	m := OutboxMetrics{RateLimited: o.rateLimited, Priorities: make(map[string]PriorityMetrics, priorityCount)}
+	for p := range o.queues {
+		m.Queued += len(o.queues[p])
+		st := o.stats[p]
+		pm := PriorityMetrics{Sent: st.sent, Failed: st.failed, MaxLatencyMs: float64(st.maxLatency.Microseconds()) / 1000}
+		if n := st.sent + st.failed; n > 0 {
+			pm.AvgLatencyMs = float64(st.totalLatency.Microseconds()) / 1000 / float64(n)
+		}
+		m.Priorities[priorityNames[p]] = pm
+	}
+	return m
+}
+
+func (o *Outbox) signal() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (o *Outbox) dispatch() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		next := o.dispatchReady()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if !next.IsZero() {
+			timer.Reset(time.Until(next))
+		} else {
+			timer.Reset(time.Hour)
+		}
+
+		select {
+		case <-o.stopCh:
+			return
+		case <-o.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// dispatchReady starts every job whose buckets allow it, highest priority
+// first. It returns when the next waiting job becomes ready, or zero if the
+// queues are empty.
+func (o *Outbox) dispatchReady() time.Time {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(o.lastPrune) > bucketIdleTTL {
+		o.pruneBuckets(now)
+	}
+
+	var next time.Time
+	for p := range o.queues {
+		queue := o.queues[p]
+		kept := queue[:0]
+		for _, job := range queue {
+			at := o.readyAt(job, now)
+			if at.After(now) {
+				kept = append(kept, job)
+				if next.IsZero() || at.Before(next) {
+					next = at
+				}
+				continue
+			}
+			o.global.take(job.cost)
+			o.chatBucket(job.chatID, now).take(job.cost)
+			go o.execute(job)
+		}
+		o.queues[p] = kept
+	}
+	return next
+}
+
+func (o *Outbox) readyAt(job *outboxJob, now time.Time) time.Time {
+	at := o.global.readyAt(now, job.cost)
+	if chatAt := o.chatBucket(job.chatID, now).readyAt(now, job.cost); chatAt.After(at) {
+		at = chatAt
+	}
+	return at
+}
+
+func (o *Outbox) chatBucket(chatID int64, now time.Time) *tokenBucket {
+	b, ok := o.chats[chatID]
+	if !ok {
+		rate := privateRate
+		if chatID < 0 {
+			rate = groupRate
+		}
+		b = newTokenBucket(rate, chatBurst, now)
+		o.chats[chatID] = b
+	}
+	return b
+}
+
+// pruneBuckets forgets chats that have been quiet long enough to be full again.
+func (o *Outbox) pruneBuckets(now time.Time) {
+	for id, b := range o.chats {
+		if now.Sub(b.last) > bucketIdleTTL && now.After(b.pausedUntil) {
+			delete(o.chats, id)
+		}
+	}
+	o.lastPrune = now
+}
+
+func (o *Outbox) execute(job *outboxJob) {
+	data, err := job.send()
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 && job.attempts < maxSendRetries {
+		job.attempts++
+		o.mu.Lock()
+		o.rateLimited++
+		if !o.closed {
+			pause := time.Now().Add(time.Duration(apiErr.RetryAfter) * time.Second)
+			o.chatBucket(job.chatID, time.Now()).pausedUntil = pause
+			o.queues[job.priority] = append([]*outboxJob{job}, o.queues[job.priority]...)
+			o.mu.Unlock()
+			o.signal()
+			return
+		}
+		o.mu.Unlock()
+		err = errOutboxClosed
+	}
+
+	latency := time.Since(job.enqueued)
+	o.mu.Lock()
+	st := &o.stats[job.priority]
+	if err != nil {
+		st.failed++
+	} else {
+		st.sent++
+	}
+	st.totalLatency += latency
+	if latency > st.maxLatency {
+		st.maxLatency = latency
+	}
+	o.mu.Unlock()
+
+	job.done <- outboxResult{data: data, err: err}
+}
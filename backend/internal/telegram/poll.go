@@ -0,0 +1,183 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"quiz-game-backend/internal/services"
+)
+
+// Bot API limits for polls. Questions that don't fit go out with buttons.
+const (
+	pollQuestionLimit = 300
+	pollOptionLimit   = 100
+	pollMaxOptions    = 10
+)
+
+// PollInfo is a quiz poll sent for a session question. Polls are kept in
+// memory only; votes on a poll sent before a restart are ignored.
+type PollInfo struct {
+	PollID    string
+	SessionID uint
+	ChatID    int64
+	MessageID int64
+	OptionIDs []uint // option IDs in poll order
+	Group     bool   // sent to a group, whose members may not have joined yet
+}
+
+// quizPoll is a single-choice question rendered as a Telegram quiz poll.
+type quizPoll struct {
+	question  string
+	options   []string
+	optionIDs []uint
+	correct   int
+}
+
+// SetQuizPolls switches single-choice questions between inline buttons and
+// native quiz polls.
+func (t *SessionTracker) SetQuizPolls(enabled bool) {
+	t.mu.Lock()
+	t.quizPolls = enabled
+	t.mu.Unlock()
+}
+
+// Poll returns the poll with the given Telegram poll ID.
+func (t *SessionTracker) Poll(pollID string) (PollInfo, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.polls[pollID]
+	if !ok {
+		return PollInfo{}, false
+	}
+	return *p, true
+}
+
+// buildQuizPoll renders the current question as a quiz poll, or returns nil
+// if polls are off or the question can't be sent as one.
+func (t *SessionTracker) buildQuizPoll(sessionID uint, qd *QuestionData, current, total int) *quizPoll {
+	t.mu.Lock()
+	enabled := t.quizPolls
+	t.mu.Unlock()
+	if !enabled || qd.Type != "single_choice" {
+		return nil
+	}
+	if len(qd.Options) < 2 || len(qd.Options) > pollMaxOptions {
+		return nil
+	}
+
+	question := fmt.Sprintf("%d/%d. %s", current, total, qd.Text)
+	if utf8.RuneCountInString(question) > pollQuestionLimit {
+		return nil
+	}
+	correctID, err := t.sessionSvc.CorrectOptionID(sessionID)
+	if err != nil {
+		return nil
+	}
+
+	poll := &quizPoll{question: question, correct: -1}
+	for i, o := range qd.Options {
+		if o.Text == "" || utf8.RuneCountInString(o.Text) > pollOptionLimit {
+			return nil
+		}
+		if o.ID == correctID {
+			poll.correct = i
+		}
+		poll.options = append(poll.options, o.Text)
+		poll.optionIDs = append(poll.optionIDs, o.ID)
+	}
+	if poll.correct < 0 {
+		return nil
+	}
+	return poll
+}
+
+// sendPollTo posts the question media followed by the poll and remembers the
+// poll so votes can be matched to the session.
+func (t *SessionTracker) sendPollTo(sessionID uint, chatID int64, images []services.ImageResponse, poll *quizPoll, kb interface{}, group bool) int64 {
+	if len(images) > 0 && t.media != nil {
+		t.media.SendQuestionMedia(chatID, images)
+	}
+	msgID, pollID, err := t.questions.SendQuizPoll(chatID, poll.question, poll.options, poll.correct, kb)
+	if err != nil {
+		log.Printf("send poll to %d: %v", chatID, err)
+		return 0
+	}
+
+	t.mu.Lock()
+	t.polls[pollID] = &PollInfo{
+		PollID:    pollID,
+		SessionID: sessionID,
+		ChatID:    chatID,
+		MessageID: msgID,
+		OptionIDs: poll.optionIDs,
+		Group:     group,
+	}
+	t.mu.Unlock()
+	return msgID
+}
+
+// closePolls stops every open poll of a session, so no votes arrive after the
+// answer is revealed.
+func (t *SessionTracker) closePolls(sessionID uint) {
+	t.mu.Lock()
+	var open []*PollInfo
+	for id, p := range t.polls {
+		if p.SessionID == sessionID {
+			open = append(open, p)
+			delete(t.polls, id)
+		}
+	}
+	t.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, p := range open {
+		wg.Add(1)
+		go func(p *PollInfo) {
+			defer wg.Done()
+			if err := t.results.StopPoll(p.ChatID, p.MessageID); err != nil {
+				log.Printf("stop poll in %d: %v", p.ChatID, err)
+			}
+		}(p)
+	}
+	wg.Wait()
+}
+
+// handlePollAnswer records a vote in a quiz poll as the voter's answer. Group
+// members who haven't joined yet are joined on their first vote.
+func (h *UpdateHandler) handlePollAnswer(pa *PollAnswer) {
+	if pa.User == nil || len(pa.OptionIDs) == 0 {
+		return
+	}
+	poll, ok := h.tracker.Poll(pa.PollID)
+	if !ok {
+		return
+	}
+	idx := pa.OptionIDs[0]
+	if idx < 0 || idx >= len(poll.OptionIDs) {
+		return
+	}
+	userID := pa.User.ID
+	optionID := poll.OptionIDs[idx]
+
+	err := h.sessionSvc.SubmitAnswer(poll.SessionID, userID, optionID)
+	if err != nil && poll.Group && strings.Contains(err.Error(), "participant not found") {
+		if _, joinErr := h.joinGroupSession(poll.SessionID, *pa.User); joinErr != nil {
+			log.Printf("poll answer from %d: %v", userID, joinErr)
+			return
+		}
+		err = h.sessionSvc.SubmitAnswer(poll.SessionID, userID, optionID)
+	}
+	if err != nil {
+		log.Printf("poll answer from %d: %v", userID, err)
+		return
+	}
+
+	if !poll.Group {
+		h.state.UpdateField(userID, func(s *UserState) {
+			s.SelectedOptionID = optionID
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package telegram
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Bot update delivery modes, selected by BOT_MODE.
+const (
+	BotModeWebhook = "webhook"
+	BotModePolling = "polling"
+)
+
+const (
+	longPollTimeout = 25 // seconds Telegram holds a getUpdates request open
+	pollBackoffMin  = time.Second
+	pollBackoffMax  = time.Minute
+)
+
+var longPollClient = &http.Client{Timeout: (longPollTimeout + 15) * time.Second}
+
+// startPolling runs a getUpdates loop for the bot until stopPolling is called.
+// Any webhook left over from webhook mode is removed first, since Telegram
+// refuses getUpdates while one is set.
+func (b *BotInstance) startPolling() {
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancelPoll = cancel
+	b.pollDone = make(chan struct{})
+
+	go func() {
+		defer close(b.pollDone)
+		if err := b.Client.DeleteWebhook(); err != nil {
+			log.Printf("[BotManager] host %d: delete webhook before polling: %v", b.HostID, err)
+		}
+		b.pollUpdates(ctx)
+	}()
+}
+
+// stopPolling cancels the in-flight long poll and waits for the loop to exit.
+func (b *BotInstance) stopPolling() {
+	if b.cancelPoll == nil {
+		return
+	}
+	b.cancelPoll()
+	<-b.pollDone
+}
+
+func (b *BotInstance) pollUpdates(ctx context.Context) {
+	var offset int64
+	backoff := pollBackoffMin
+
+	for {
+		updates, err := b.Client.GetUpdates(ctx, offset, longPollTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			log.Printf("[BotManager] host %d: getUpdates: %v (retry in %s)", b.HostID, err, backoff)
+			select {
+			case <-ctx.Done():
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > pollBackoffMax {
+				backoff = pollBackoffMax
+			}
+			continue
+		}
+		backoff = pollBackoffMin
+
+		for _, upd := range updates {
+			if upd.UpdateID >= offset {
+				offset = upd.UpdateID + 1
+			}
+			go b.Handler.Handle(upd)
+		}
+	}
+
+	// Confirm the handled updates so a restart doesn't receive them again.
+	if offset > 0 {
+		ackCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		b.Client.GetUpdates(ackCtx, offset, 0)
+	}
+}
@@ -0,0 +1,118 @@
+package telegram
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+
+	"quiz-game-backend/internal/models"
+	"quiz-game-backend/internal/services"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// uploadsDir is where UploadImage stores question media; URLs look like /uploads/<name>.
+const uploadsDir = "/uploads"
+
+// MediaSender delivers question media to players of one bot, uploading each
+// file once and reusing the returned file_id afterwards.
+type MediaSender struct {
+	db     *gorm.DB
+	client *Client
+	botKey string
+}
+
+func NewMediaSender(db *gorm.DB, client *Client, botKey string) *MediaSender {
+	return &MediaSender{db: db, client: client, botKey: botKey}
+}
+
+type questionMedia struct {
+	imageID uint
+	file    MediaFile
+}
+
+// SendQuestionMedia sends the images, audio and video of a question. Pictures
+// and videos go out as one album, audio tracks as another.
+func (m *MediaSender) SendQuestionMedia(chatID int64, images []services.ImageResponse) {
+	var visual, audio []questionMedia
+	for _, img := range images {
+		item, ok := m.resolve(img)
+		if !ok {
+			continue
+		}
+		if img.Type == "audio" {
+			audio = append(audio, item)
+		} else {
+			visual = append(visual, item)
+		}
+	}
+	m.sendBatch(chatID, visual)
+	m.sendBatch(chatID, audio)
+}
+
+func (m *MediaSender) resolve(img services.ImageResponse) (questionMedia, bool) {
+	item := questionMedia{imageID: img.ID, file: MediaFile{Type: img.Type}}
+
+	var cached models.TelegramFile
+	if m.db.Where("image_id = ? AND bot_key = ?", img.ID, m.botKey).First(&cached).Error == nil {
+		item.file.FileID = cached.FileID
+		return item, true
+	}
+
+	switch {
+	case strings.HasPrefix(img.URL, "/uploads/"):
+		item.file.Path = filepath.Join(uploadsDir, filepath.Base(img.URL))
+	case strings.HasPrefix(img.URL, "http://"), strings.HasPrefix(img.URL, "https://"):
+		item.file.URL = img.URL
+	default:
+		return item, false
+	}
+	return item, true
+}
+
+func (m *MediaSender) sendBatch(chatID int64, items []questionMedia) {
+	for len(items) > 0 {
+		n := len(items)
+		if n > 10 {
+			n = 10
+		}
+		chunk := items[:n]
+		items = items[n:]
+
+		if len(chunk) == 1 {
+			msg, err := m.client.sendMedia(chatID, chunk[0].file, "", "", nil)
+			if err != nil {
+				log.Printf("send media %d to %d: %v", chunk[0].imageID, chatID, err)
+				continue
+			}
+			m.remember(chunk[0], msg.FileID())
+			continue
+		}
+
+		files := make([]MediaFile, len(chunk))
+		for i, it := range chunk {
+			files[i] = it.file
+		}
+		msgs, err := m.client.SendMediaGroup(chatID, files)
+		if err != nil {
+			log.Printf("send media group to %d: %v", chatID, err)
+			continue
+		}
+		for i, msg := range msgs {
+			if i < len(chunk) {
+				m.remember(chunk[i], msg.FileID())
+			}
+		}
+	}
+}
+
+func (m *MediaSender) remember(item questionMedia, fileID string) {
+	if fileID == "" || item.file.FileID == fileID {
+		return
+	}
+	m.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "image_id"}, {Name: "bot_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"file_id"}),
+	}).Create(&models.TelegramFile{ImageID: item.imageID, BotKey: m.botKey, FileID: fileID})
+}
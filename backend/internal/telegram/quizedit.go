@@ -0,0 +1,318 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"quiz-game-backend/internal/i18n"
+	"quiz-game-backend/internal/models"
+	"quiz-game-backend/internal/services"
+)
+
+// importFileLimit caps quiz files sent to the bot for import.
+const importFileLimit = 1 << 20
+
+const quizTitleLimit = 255
+
+// optionColors matches the palette the web editor assigns to new options.
+var optionColors = []string{"#e21b3c", "#1368ce", "#d89e00", "#26890c", "#864cbf", "#0aa3b1"}
+
+func (h *UpdateHandler) showQuizList(userID, chatID int64, page int, editMsgID int64) {
+	lang := h.state.Locale(userID)
+	items, count, page, totalPages := h.quizPage(lang, page)
+
+	text := i18n.T(lang, "quizzes.title", count)
+	if count == 0 {
+		text = i18n.T(lang, "quizzes.empty")
+	}
+	kb := HostQuizListKeyboard(lang, items, page, totalPages, h.aiSvc != nil && h.aiSvc.IsAvailable())
+
+	if editMsgID > 0 {
+		if err := h.client.EditMessageText(chatID, editMsgID, text, "HTML", kb); err == nil {
+			return
+		}
+	}
+	h.sendAndTrack(chatID, userID, text, "HTML", kb)
+}
+
+// showQuizCard shows a quiz with its question count. note, if set, is put
+// above the card, e.g. to confirm the question just added.
+func (h *UpdateHandler) showQuizCard(userID, chatID int64, quizID uint, note string, editMsgID int64) {
+	lang := h.state.Locale(userID)
+	quiz, err := h.quizSvc.GetQuizByID(quizID, h.hostID)
+	if err != nil {
+		h.client.SendMessage(chatID, h.errText(userID, err), "", nil)
+		h.showQuizList(userID, chatID, 0, 0)
+		return
+	}
+
+	text := i18n.T(lang, "quiz.card", quiz.Title, quizQuestionCount(quiz))
+	if note != "" {
+		text = note + "\n\n" + text
+	}
+	kb := HostQuizCardKeyboard(lang, quiz.ID)
+
+	if editMsgID > 0 {
+		if err := h.client.EditMessageText(chatID, editMsgID, text, "HTML", kb); err == nil {
+			return
+		}
+	}
+	h.sendAndTrack(chatID, userID, text, "HTML", kb)
+}
+
+func quizQuestionCount(quiz *models.Quiz) int {
+	n := len(quiz.Questions)
+	for _, c := range quiz.Categories {
+		n += len(c.Questions)
+	}
+	return n
+}
+
+// promptHostInput switches the host to a typed-input state and asks for the
+// value. The cancel button returns to cancelData.
+func (h *UpdateHandler) promptHostInput(userID, chatID int64, state string, draft *QuestionDraft, key, cancelData string) {
+	h.state.UpdateField(userID, func(s *UserState) {
+		s.State = state
+		s.Draft = draft
+	})
+	lang := h.state.Locale(userID)
+	h.sendAndTrack(chatID, userID, i18n.T(lang, key), "HTML", HostCancelKeyboard(lang, cancelData))
+}
+
+// endHostInput returns the host to the remote after a typed-input step.
+func (h *UpdateHandler) endHostInput(userID int64) {
+	h.state.UpdateField(userID, func(s *UserState) {
+		s.State = StateHostRemote
+		s.Draft = nil
+	})
+}
+
+func (h *UpdateHandler) onQuizTitle(userID, chatID int64, text string) {
+	if text == "" || utf8.RuneCountInString(text) > quizTitleLimit {
+		h.client.SendMessage(chatID, h.tr(userID, "quiz.bad_title", quizTitleLimit), "", nil)
+		return
+	}
+	h.endHostInput(userID)
+
+	quiz, err := h.quizSvc.CreateQuiz(h.hostID, text)
+	if err != nil {
+		h.client.SendMessage(chatID, h.errText(userID, err), "", nil)
+		h.showQuizList(userID, chatID, 0, 0)
+		return
+	}
+	h.audit.Record(h.hostID, services.TelegramActor(userID), services.AuditQuizCreate, services.AuditTargetQuiz, quiz.ID, nil, h.audit.QuizSummary(quiz.ID))
+	h.showQuizCard(userID, chatID, quiz.ID, h.tr(userID, "quiz.created"), 0)
+}
+
+func (h *UpdateHandler) onQuestionText(userID, chatID int64, text string, us *UserState) {
+	if us.Draft == nil {
+		h.endHostInput(userID)
+		h.showQuizList(userID, chatID, 0, 0)
+		return
+	}
+	if text == "" {
+		h.client.SendMessage(chatID, h.tr(userID, "question.enter_text"), "HTML", nil)
+		return
+	}
+
+	draft := *us.Draft
+	draft.Text = text
+	cancel := fmt.Sprintf("host:quiz:%d", draft.QuizID)
+	switch draft.Type {
+	case models.QuestionTypeNumeric:
+		h.promptHostInput(userID, chatID, StateHostNumber, &draft, "question.enter_number", cancel)
+	case models.QuestionTypeMultipleChoice:
+		h.promptHostInput(userID, chatID, StateHostOptions, &draft, "question.enter_options_multi", cancel)
+	default:
+		h.promptHostInput(userID, chatID, StateHostOptions, &draft, "question.enter_options_single", cancel)
+	}
+}
+
+// parseOptionLines reads one answer option per line; a leading "*" marks a
+// correct one.
+func parseOptionLines(text string) []services.OptionInput {
+	var options []services.OptionInput
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		correct := strings.HasPrefix(line, "*")
+		line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+		if line == "" {
+			continue
+		}
+		options = append(options, services.OptionInput{
+			Text:      line,
+			IsCorrect: correct,
+			Color:     optionColors[len(options)%len(optionColors)],
+		})
+	}
+	return options
+}
+
+func (h *UpdateHandler) onQuestionOptions(userID, chatID int64, text string, us *UserState) {
+	if us.Draft == nil {
+		h.endHostInput(userID)
+		h.showQuizList(userID, chatID, 0, 0)
+		return
+	}
+	input := services.QuestionInput{
+		Text:    us.Draft.Text,
+		Type:    us.Draft.Type,
+		Options: parseOptionLines(text),
+	}
+	h.saveQuestion(userID, chatID, us.Draft.QuizID, input)
+}
+
+// parseNumberAnswer reads "42", "42 ± 2", "42 +- 2" or "42 2" as a correct
+// number with an optional tolerance.
+func parseNumberAnswer(text string) (float64, *float64, bool) {
+	text = strings.NewReplacer("±", " ", "+-", " ", ",", ".").Replace(text)
+	fields := strings.Fields(text)
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, nil, false
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, nil, false
+	}
+	if len(fields) == 1 {
+		return value, nil, true
+	}
+	tolerance, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || tolerance < 0 {
+		return 0, nil, false
+	}
+	return value, &tolerance, true
+}
+
+func (h *UpdateHandler) onQuestionNumber(userID, chatID int64, text string, us *UserState) {
+	if us.Draft == nil {
+		h.endHostInput(userID)
+		h.showQuizList(userID, chatID, 0, 0)
+		return
+	}
+	value, tolerance, ok := parseNumberAnswer(text)
+	if !ok {
+		h.client.SendMessage(chatID, h.tr(userID, "question.bad_number"), "HTML", nil)
+		return
+	}
+	input := services.QuestionInput{
+		Text:          us.Draft.Text,
+		Type:          models.QuestionTypeNumeric,
+		CorrectNumber: &value,
+		Tolerance:     tolerance,
+	}
+	h.saveQuestion(userID, chatID, us.Draft.QuizID, input)
+}
+
+// saveQuestion appends a question to the end of the quiz. Invalid options
+// keep the host in the same step so they can be sent again.
+func (h *UpdateHandler) saveQuestion(userID, chatID int64, quizID uint, input services.QuestionInput) {
+	quiz, err := h.quizSvc.GetQuizByID(quizID, h.hostID)
+	if err != nil {
+		h.endHostInput(userID)
+		h.client.SendMessage(chatID, h.errText(userID, err), "", nil)
+		h.showQuizList(userID, chatID, 0, 0)
+		return
+	}
+	for _, q := range quiz.Questions {
+		if q.OrderNum >= input.OrderNum {
+			input.OrderNum = q.OrderNum + 1
+		}
+	}
+
+	if _, err := h.quizSvc.CreateQuestion(quizID, h.hostID, input); err != nil {
+		h.client.SendMessage(chatID, h.errText(userID, err), "", nil)
+		return
+	}
+	h.endHostInput(userID)
+	h.showQuizCard(userID, chatID, quizID, h.tr(userID, "question.added"), 0)
+}
+
+func (h *UpdateHandler) onQuizImport(userID, chatID int64, msg *Message) {
+	if msg.Document == nil {
+		h.client.SendMessage(chatID, h.tr(userID, "import.not_file"), "", nil)
+		return
+	}
+	if msg.Document.FileSize > importFileLimit {
+		h.client.SendMessage(chatID, h.tr(userID, "import.too_large", importFileLimit>>10), "", nil)
+		return
+	}
+
+	body, err := h.client.DownloadFile(msg.Document.FileID, importFileLimit)
+	if errors.Is(err, errFileTooLarge) {
+		h.client.SendMessage(chatID, h.tr(userID, "import.too_large", importFileLimit>>10), "", nil)
+		return
+	}
+	if err != nil {
+		log.Printf("download import file from %d: %v", userID, err)
+		h.client.SendMessage(chatID, h.errText(userID, err), "", nil)
+		return
+	}
+
+	data, err := services.ParseQuizFile(msg.Document.FileName, body)
+	if err != nil {
+		h.client.SendMessage(chatID, h.tr(userID, "import.failed", i18n.Error(h.state.Locale(userID), err.Error())), "", nil)
+		return
+	}
+
+	title := strings.TrimSpace(data.Title)
+	if title == "" {
+		title = strings.TrimSuffix(msg.Document.FileName, path.Ext(msg.Document.FileName))
+	}
+	h.endHostInput(userID)
+	h.createQuizFrom(userID, chatID, title, data.ImportInput(), "import.done")
+}
+
+func (h *UpdateHandler) onAIPrompt(userID, chatID int64, text string) {
+	if text == "" {
+		h.client.SendMessage(chatID, h.tr(userID, "ai.prompt"), "HTML", nil)
+		return
+	}
+	h.endHostInput(userID)
+	if h.aiSvc == nil || !h.aiSvc.IsAvailable() {
+		h.client.SendMessage(chatID, h.tr(userID, "ai.unavailable"), "", nil)
+		h.showQuizList(userID, chatID, 0, 0)
+		return
+	}
+
+	h.sendAndTrack(chatID, userID, h.tr(userID, "ai.generating"), "", nil)
+	input, title, err := h.aiSvc.GenerateQuiz(text)
+	if err != nil {
+		log.Printf("AI quiz for %d: %v", userID, err)
+		h.client.SendMessage(chatID, h.tr(userID, "ai.failed"), "", nil)
+		h.showQuizList(userID, chatID, 0, 0)
+		return
+	}
+	if strings.TrimSpace(title) == "" {
+		title = text
+	}
+	h.createQuizFrom(userID, chatID, title, *input, "ai.done")
+}
+
+// createQuizFrom creates a quiz holding imported or generated questions and
+// shows its card.
+func (h *UpdateHandler) createQuizFrom(userID, chatID int64, title string, input services.ImportInput, doneKey string) {
+	if utf8.RuneCountInString(title) > quizTitleLimit {
+		title = string([]rune(title)[:quizTitleLimit])
+	}
+	quiz, err := h.quizSvc.CreateQuiz(h.hostID, title)
+	if err != nil {
+		h.client.SendMessage(chatID, h.errText(userID, err), "", nil)
+		h.showQuizList(userID, chatID, 0, 0)
+		return
+	}
+	count, err := h.quizSvc.ImportQuestions(quiz.ID, h.hostID, input)
+	if err != nil {
+		h.quizSvc.DeleteQuiz(quiz.ID, h.hostID)
+		h.client.SendMessage(chatID, h.errText(userID, err), "", nil)
+		h.showQuizList(userID, chatID, 0, 0)
+		return
+	}
+	h.audit.Record(h.hostID, services.TelegramActor(userID), services.AuditQuizCreate, services.AuditTargetQuiz, quiz.ID, nil, h.audit.QuizSummary(quiz.ID))
+	h.showQuizCard(userID, chatID, quiz.ID, h.tr(userID, doneKey, count), 0)
+}
@@ -0,0 +1,57 @@
+package telegram
+
+import "log"
+
+// RestoreSessions rebuilds the tracker after a restart from the host's active
+// sessions and the persisted conversation states, so running games keep
+// delivering questions to Telegram players and host remotes.
+func (h *UpdateHandler) RestoreSessions() {
+	sessions, err := h.sessionSvc.GetActiveSessions(h.hostID)
+	if err != nil || len(sessions) == 0 {
+		return
+	}
+	states := h.state.All()
+
+	restored := 0
+	for _, summary := range sessions {
+		sessState, err := h.sessionSvc.GetSession(summary.ID)
+		if err != nil {
+			continue
+		}
+
+		players := make(map[int64]int64)
+		for _, p := range sessState.Participants {
+			if p.TelegramID == 0 {
+				continue
+			}
+			us, ok := states[p.TelegramID]
+			if !ok || us.SessionID != sessState.ID {
+				continue
+			}
+			if us.State == StateInSession || us.State == StateEnterNumeric {
+				players[p.TelegramID] = p.TelegramID // private chat ID equals the user ID
+			}
+		}
+
+		var hostChatID int64
+		for tgID, us := range states {
+			if us.State != StateHostRemote {
+				continue
+			}
+			if us.SessionID == sessState.ID || (sessState.RoomID > 0 && us.RoomID == sessState.RoomID) {
+				hostChatID = tgID
+				break
+			}
+		}
+
+		if len(players) == 0 && hostChatID == 0 {
+			continue
+		}
+		h.tracker.Resume(sessState, players, hostChatID)
+		restored++
+	}
+
+	if restored > 0 {
+		log.Printf("[BotManager] host %d: resumed %d active sessions", h.hostID, restored)
+	}
+}
@@ -1,6 +1,17 @@
 package telegram
 
-import "sync"
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"quiz-game-backend/internal/i18n"
+	"quiz-game-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
 const (
 	StateNone          = ""
@@ -10,11 +21,39 @@ const (
 	StateHostPassword  = "host_password"
 	StateHostRemote    = "host_remote"
 	StateEnterNumeric  = "enter_numeric"
+	StateHostRename    = "host_rename"
+
+	// Host remote input steps for building quizzes.
+	StateHostQuizTitle    = "host_quiz_title"
+	StateHostQuestionText = "host_question_text"
+	StateHostOptions      = "host_options"
+	StateHostNumber       = "host_number"
+	StateHostImport       = "host_import"
+	StateHostAIPrompt     = "host_ai_prompt"
 )
 
+// hostInputStates are host remote states waiting for a typed answer. Pressing
+// any remote button abandons them.
+var hostInputStates = map[string]bool{
+	StateHostRename:       true,
+	StateHostQuizTitle:    true,
+	StateHostQuestionText: true,
+	StateHostOptions:      true,
+	StateHostNumber:       true,
+	StateHostImport:       true,
+	StateHostAIPrompt:     true,
+}
+
+// isHostState reports whether the user is in the host remote rather than
+// playing.
+func isHostState(state string) bool {
+	return state == StateHostRemote || state == StateHostPassword || hostInputStates[state]
+}
+
 type QuestionOption struct {
-	ID   uint   `json:"id"`
-	Text string `json:"text"`
+	ID        uint   `json:"id"`
+	Text      string `json:"text"`
+	MatchText string `json:"match_text,omitempty"`
 }
 
 type QuestionData struct {
@@ -22,38 +61,158 @@ type QuestionData struct {
 	Type      string           `json:"type"`
 	SessionID uint             `json:"session_id"`
 	Options   []QuestionOption `json:"options"`
+	// MatchChoices are the right-hand items of a matching question, sorted so
+	// their position doesn't give the answer away.
+	MatchChoices []string `json:"match_choices,omitempty"`
 }
 
+// QuestionDraft is a question being added from the host remote.
+type QuestionDraft struct {
+	QuizID uint   `json:"quiz_id"`
+	Type   string `json:"type"`
+	Text   string `json:"text,omitempty"`
+}
+
+// UserState is a user's place in the bot conversation. It is stored as JSON
+// in telegram_states and outlives Clear for carried-over fields, so it must
+// never hold passwords or other credentials; remote access is tracked by
+// RemoteAuthorization instead.
 type UserState struct {
-	State             string
-	Code              string
-	Nickname          string
-	SessionID         uint
-	RoomID            uint
-	QuestionData      *QuestionData
-	CurrentQNum       int
-	TotalQuestions    int
-	SelectedOptionID  uint
-	SelectedOptionIDs []uint
-	HostAuthPassword  string
-	LastBotMsgID      int64
+	State             string         `json:"state,omitempty"`
+	Code              string         `json:"code,omitempty"`
+	Nickname          string         `json:"nickname,omitempty"`
+	SessionID         uint           `json:"session_id,omitempty"`
+	RoomID            uint           `json:"room_id,omitempty"`
+	QuestionData      *QuestionData  `json:"question_data,omitempty"`
+	CurrentQNum       int            `json:"current_q_num,omitempty"`
+	TotalQuestions    int            `json:"total_questions,omitempty"`
+	SelectedOptionID  uint           `json:"selected_option_id,omitempty"`
+	SelectedOptionIDs []uint         `json:"selected_option_ids,omitempty"`
+	OrderIDs          []uint         `json:"order_ids,omitempty"`   // ordering: options in the order tapped so far
+	MatchPicks        []int          `json:"match_picks,omitempty"` // matching: index into MatchChoices for each left item answered so far
+	RenameTarget      string         `json:"rename_target,omitempty"`
+	Draft             *QuestionDraft `json:"draft,omitempty"`
+	LastBotMsgID      int64          `json:"last_bot_msg_id,omitempty"`
+	Language          string         `json:"language,omitempty"`      // chosen with /language; empty follows LanguageCode
+	LanguageCode      string         `json:"language_code,omitempty"` // last language_code reported by Telegram
 }
 
+// StateManager keeps the conversation state of a bot's users. Reads are served
+// from memory; every change is written through to Postgres so the state
+// survives restarts.
 type StateManager struct {
-	mu    sync.RWMutex
-	users map[int64]*UserState
+	db     *gorm.DB
+	botKey string
+
+	mu            sync.Mutex
+	users         map[int64]*UserState
+	defaultLocale string
 }
 
-func NewStateManager() *StateManager {
+func NewStateManager(db *gorm.DB, botKey string) *StateManager {
 	return &StateManager{
-		users: make(map[int64]*UserState),
+		db:            db,
+		botKey:        botKey,
+		users:         make(map[int64]*UserState),
+		defaultLocale: i18n.Default,
+	}
+}
+
+// SetDefaultLocale sets the language for users whose Telegram language isn't
+// supported, usually the host's bot language.
+func (m *StateManager) SetDefaultLocale(locale string) {
+	if !i18n.IsSupported(locale) {
+		locale = i18n.Default
+	}
+	m.mu.Lock()
+	m.defaultLocale = locale
+	m.mu.Unlock()
+}
+
+// DefaultLocale returns the bot language, used for messages addressed to a
+// whole group rather than one user.
+func (m *StateManager) DefaultLocale() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.defaultLocale
+}
+
+// Locale picks the language for a user's messages: the one chosen with
+// /language, then the Telegram client language, then the bot default.
+func (m *StateManager) Locale(userID int64) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.load(userID); ok {
+		if s.Language != "" {
+			return s.Language
+		}
+		if locale := i18n.Match(s.LanguageCode); locale != "" {
+			return locale
+		}
+	}
+	return m.defaultLocale
+}
+
+// SetLanguageCode records the language_code Telegram reported for a user.
+func (m *StateManager) SetLanguageCode(userID int64, code string) {
+	if code == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.load(userID)
+	if ok && s.LanguageCode == code {
+		return
+	}
+	if !ok {
+		s = &UserState{}
+	}
+	s.LanguageCode = code
+	m.save(userID, s)
+}
+
+// load returns the cached state of a user, reading it from the database on a
+// cache miss. The caller must hold m.mu.
+func (m *StateManager) load(userID int64) (*UserState, bool) {
+	if s, ok := m.users[userID]; ok {
+		return s, true
+	}
+
+	var row models.TelegramState
+	if err := m.db.Where("bot_key = ? AND telegram_id = ?", m.botKey, userID).First(&row).Error; err != nil {
+		return nil, false
+	}
+	var s UserState
+	if err := json.Unmarshal([]byte(row.Data), &s); err != nil {
+		log.Printf("state: decode user %d: %v", userID, err)
+		return nil, false
+	}
+	m.users[userID] = &s
+	return &s, true
+}
+
+// save caches and persists a user's state. The caller must hold m.mu.
+func (m *StateManager) save(userID int64, s *UserState) {
+	m.users[userID] = s
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		log.Printf("state: encode user %d: %v", userID, err)
+		return
+	}
+	row := models.TelegramState{BotKey: m.botKey, TelegramID: userID, Data: string(data), UpdatedAt: time.Now()}
+	if err := m.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "bot_key"}, {Name: "telegram_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"data", "updated_at"}),
+	}).Create(&row).Error; err != nil {
+		log.Printf("state: save user %d: %v", userID, err)
 	}
 }
 
 func (m *StateManager) Get(userID int64) *UserState {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	s, ok := m.users[userID]
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.load(userID)
 	if !ok {
 		return &UserState{}
 	}
@@ -64,32 +223,78 @@ func (m *StateManager) Get(userID int64) *UserState {
 func (m *StateManager) Set(userID int64, state *UserState) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if old, ok := m.users[userID]; ok {
-		state.HostAuthPassword = old.HostAuthPassword
+	if old, ok := m.load(userID); ok {
 		state.LastBotMsgID = old.LastBotMsgID
+		state.Language = old.Language
+		state.LanguageCode = old.LanguageCode
 	}
-	m.users[userID] = state
+	m.save(userID, state)
 }
 
 func (m *StateManager) Clear(userID int64) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if old, ok := m.users[userID]; ok {
-		m.users[userID] = &UserState{
-			HostAuthPassword: old.HostAuthPassword,
-		}
-	} else {
-		delete(m.users, userID)
+	if old, ok := m.load(userID); ok && (old.Language != "" || old.LanguageCode != "") {
+		m.save(userID, &UserState{
+			Language:     old.Language,
+			LanguageCode: old.LanguageCode,
+		})
+		return
 	}
+	delete(m.users, userID)
+	m.db.Where("bot_key = ? AND telegram_id = ?", m.botKey, userID).Delete(&models.TelegramState{})
 }
 
 func (m *StateManager) UpdateField(userID int64, fn func(s *UserState)) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	s, ok := m.users[userID]
+	s, ok := m.load(userID)
 	if !ok {
 		s = &UserState{}
-		m.users[userID] = s
 	}
 	fn(s)
+	m.save(userID, s)
+}
+
+// All returns the persisted state of every user of the bot.
+func (m *StateManager) All() map[int64]*UserState {
+	var rows []models.TelegramState
+	m.db.Where("bot_key = ?", m.botKey).Find(&rows)
+
+	result := make(map[int64]*UserState, len(rows))
+	for _, row := range rows {
+		var s UserState
+		if err := json.Unmarshal([]byte(row.Data), &s); err == nil {
+			result[row.TelegramID] = &s
+		}
+	}
+	return result
+}
+
+// evictIdle drops cached entries whose persisted row no longer exists, after
+// the TTL cleanup removed them.
+func (m *StateManager) evictIdle() {
+	var ids []int64
+	m.db.Model(&models.TelegramState{}).Where("bot_key = ?", m.botKey).Pluck("telegram_id", &ids)
+	alive := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		alive[id] = true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id := range m.users {
+		if !alive[id] {
+			delete(m.users, id)
+		}
+	}
+}
+
+// CleanupTelegramStates deletes conversation states untouched for longer than ttl.
+func CleanupTelegramStates(db *gorm.DB, ttl time.Duration) int64 {
+	res := db.Where("updated_at < ?", time.Now().Add(-ttl)).Delete(&models.TelegramState{})
+	if res.Error != nil {
+		log.Printf("state: cleanup: %v", res.Error)
+	}
+	return res.RowsAffected
 }
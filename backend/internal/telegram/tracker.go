@@ -3,10 +3,13 @@ package telegram
 import (
 	"fmt"
 	"log"
+	"math"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"quiz-game-backend/internal/i18n"
 	"quiz-game-backend/internal/services"
 )
 
@@ -28,34 +31,92 @@ type SessionInfo struct {
 	LastAnswerCount int
 	Participants    map[int64]*ParticipantInfo
 	HostRemote      *HostRemoteInfo
+	Group           *GroupInfo
 	mu              sync.Mutex
+	// syncMu serializes state application between session events and the
+	// fallback poll so a change is delivered to players only once.
+	syncMu sync.Mutex
+
+	// lastState is the snapshot applied last; answer events redraw the host
+	// control from it instead of reloading the session.
+	lastState      *services.SessionState
+	lastQuestionID uint
+
+	// Events are queued here by handleEvent and applied by the session's
+	// worker in pollLoop, so sends never block the event bus.
+	queue   []*services.SessionState
+	answers *answerUpdate
+	reload  bool
+	pending bool // an answer flush is scheduled
+	wake    chan struct{}
+}
+
+// answerUpdate is the newest answer count of a question seen in events.
+type answerUpdate struct {
+	questionID uint
+	count      int
+}
+
+// answerDebounce batches answer events into one host control and group
+// counter edit.
+const answerDebounce = time.Second
+
+// kick wakes the session's worker if it isn't already due to run.
+func (info *SessionInfo) kick() {
+	select {
+	case info.wake <- struct{}{}:
+	default:
+	}
+}
+
+// questionID returns the ID of the current question of a snapshot, or 0.
+func questionID(s *services.SessionState) uint {
+	if s.CurrentQuestionData == nil {
+		return 0
+	}
+	return s.CurrentQuestionData.ID
 }
 
 type SessionTracker struct {
 	client       *Client
+	questions    *Client // client, queued at question priority
+	results      *Client // client, queued at result priority
+	control      *Client // client, queued at host control priority
+	media        *MediaSender
 	state        *StateManager
 	sessionSvc   *services.SessionService
 	pollInterval time.Duration
 
-	mu       sync.Mutex
-	sessions map[uint]*SessionInfo
-	stopChs  map[uint]chan struct{}
+	mu          sync.Mutex
+	sessions    map[uint]*SessionInfo
+	stopChs     map[uint]chan struct{}
+	polls       map[string]*PollInfo
+	quizPolls   bool
+	unsubscribe func()
 }
 
 func NewSessionTracker(
 	client *Client,
+	media *MediaSender,
 	state *StateManager,
 	sessionSvc *services.SessionService,
 	pollInterval time.Duration,
 ) *SessionTracker {
-	return &SessionTracker{
+	t := &SessionTracker{
 		client:       client,
+		questions:    client.WithPriority(PriorityQuestion),
+		results:      client.WithPriority(PriorityResult),
+		control:      client.WithPriority(PriorityHostControl),
+		media:        media,
 		state:        state,
 		sessionSvc:   sessionSvc,
 		pollInterval: pollInterval,
 		sessions:     make(map[uint]*SessionInfo),
 		stopChs:      make(map[uint]chan struct{}),
+		polls:        make(map[string]*PollInfo),
 	}
+	t.unsubscribe = sessionSvc.Subscribe(t.handleEvent)
+	return t
 }
 
 func (t *SessionTracker) AddParticipant(sessionID uint, telegramID, chatID, messageID int64) {
@@ -68,11 +129,7 @@ func (t *SessionTracker) AddParticipant(sessionID uint, telegramID, chatID, mess
 			SessionID:    sessionID,
 			Participants: make(map[int64]*ParticipantInfo),
 		}
-		t.sessions[sessionID] = info
-
-		stopCh := make(chan struct{})
-		t.stopChs[sessionID] = stopCh
-		go t.pollLoop(sessionID, stopCh)
+		t.track(info)
 	}
 
 	info.mu.Lock()
@@ -84,6 +141,56 @@ func (t *SessionTracker) AddParticipant(sessionID uint, telegramID, chatID, mess
 	info.mu.Unlock()
 }
 
+// Resume starts tracking a session that was already running before a restart.
+// The last seen state is seeded from sessState, so only later changes are sent
+// out. players maps Telegram IDs to their chat IDs; a zero message ID makes the
+// next update arrive as a new message.
+func (t *SessionTracker) Resume(sessState *services.SessionState, players map[int64]int64, hostChatID int64) {
+	t.mu.Lock()
+	if _, exists := t.sessions[sessState.ID]; exists {
+		t.mu.Unlock()
+		return
+	}
+	info := &SessionInfo{
+		SessionID:       sessState.ID,
+		LastStatus:      sessState.Status,
+		LastQuestion:    sessState.CurrentQuestion,
+		LastAnswerCount: sessState.AnswerCount,
+		Participants:    make(map[int64]*ParticipantInfo, len(players)),
+		lastState:       sessState,
+		lastQuestionID:  questionID(sessState),
+	}
+	for tgID, chatID := range players {
+		info.Participants[tgID] = &ParticipantInfo{ChatID: chatID, TelegramID: tgID}
+	}
+	if hostChatID != 0 {
+		info.HostRemote = &HostRemoteInfo{ChatID: hostChatID}
+	}
+	t.track(info)
+	t.mu.Unlock()
+}
+
+// track registers a session and starts its worker. The caller holds t.mu.
+func (t *SessionTracker) track(info *SessionInfo) {
+	info.wake = make(chan struct{}, 1)
+	t.sessions[info.SessionID] = info
+
+	stopCh := make(chan struct{})
+	t.stopChs[info.SessionID] = stopCh
+	go t.pollLoop(info, stopCh)
+}
+
+// RemoveParticipant stops sending session updates to a Telegram player.
+func (t *SessionTracker) RemoveParticipant(telegramID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, info := range t.sessions {
+		info.mu.Lock()
+		delete(info.Participants, telegramID)
+		info.mu.Unlock()
+	}
+}
+
 func (t *SessionTracker) SetHostRemote(sessionID uint, chatID, messageID int64) {
 	t.mu.Lock()
 	info, exists := t.sessions[sessionID]
@@ -92,11 +199,7 @@ func (t *SessionTracker) SetHostRemote(sessionID uint, chatID, messageID int64)
 			SessionID:    sessionID,
 			Participants: make(map[int64]*ParticipantInfo),
 		}
-		t.sessions[sessionID] = info
-
-		stopCh := make(chan struct{})
-		t.stopChs[sessionID] = stopCh
-		go t.pollLoop(sessionID, stopCh)
+		t.track(info)
 	}
 	t.mu.Unlock()
 
@@ -125,8 +228,9 @@ func (t *SessionTracker) SendHostControl(sessionID uint) {
 		return
 	}
 
-	text := t.buildHostControlText(sessState)
-	kb := HostControlKeyboard(sessionID, sessState.Status, sessState.CurrentQuestion, sessState.TotalQuestions)
+	lang := t.state.Locale(hr.ChatID)
+	text := t.buildHostControlText(lang, sessState)
+	kb := HostControlKeyboard(lang, sessionID, sessState.Status, sessState.CurrentQuestion, sessState.TotalQuestions)
 
 	msgID := t.sendOrEditHost(hr, text, kb)
 	if msgID > 0 {
@@ -138,13 +242,13 @@ func (t *SessionTracker) SendHostControl(sessionID uint) {
 	}
 }
 
-func (t *SessionTracker) buildHostControlText(s *services.SessionState) string {
+func (t *SessionTracker) buildHostControlText(lang string, s *services.SessionState) string {
 	participantCount := len(s.Participants)
+	title := i18n.T(lang, "remote.title") + "\n\n"
 
 	switch s.Status {
 	case "waiting":
-		return fmt.Sprintf("🎯 <b>Пульт ведущего</b>\n\n📋 Квиз: <b>%s</b>\n🔑 Код: <b>%s</b>\n👥 Участников: <b>%d</b>\n📝 Вопросов: <b>%d</b>\n\n⏳ Ожидание участников...",
-			s.Quiz.Title, s.Code, participantCount, s.TotalQuestions)
+		return title + i18n.T(lang, "control.waiting", s.Quiz.Title, s.Code, participantCount, s.TotalQuestions)
 
 	case "question":
 		qText := ""
@@ -152,11 +256,12 @@ func (t *SessionTracker) buildHostControlText(s *services.SessionState) string {
 		if s.CurrentQuestionData != nil {
 			qText = s.CurrentQuestionData.Text
 			if s.CurrentQuestionData.CategoryName != "" {
-				catText = fmt.Sprintf("\n📁 Категория: <b>%s</b>", s.CurrentQuestionData.CategoryName)
+				catText = "\n" + i18n.T(lang, "control.category", s.CurrentQuestionData.CategoryName)
 			}
 		}
-		return fmt.Sprintf("🎯 <b>Пульт ведущего</b>\n\n❓ <b>Вопрос %d из %d</b>%s\n\n%s\n\n📊 Ответили: <b>%d</b> из <b>%d</b>",
-			s.CurrentQuestion, s.TotalQuestions, catText, qText, s.AnswerCount, participantCount)
+		return fmt.Sprintf("%s%s%s\n\n%s\n\n%s", title,
+			i18n.T(lang, "question.header", s.CurrentQuestion, s.TotalQuestions), catText, qText,
+			i18n.T(lang, "control.answered", s.AnswerCount, participantCount))
 
 	case "revealed":
 		qText := ""
@@ -165,31 +270,31 @@ func (t *SessionTracker) buildHostControlText(s *services.SessionState) string {
 			qText = s.CurrentQuestionData.Text
 			for _, opt := range s.CurrentQuestionData.Options {
 				if opt.IsCorrect != nil && *opt.IsCorrect {
-					correctText = fmt.Sprintf("\n\n✅ Правильный ответ: <b>%s</b>", opt.Text)
+					correctText = "\n\n" + i18n.T(lang, "control.correct", opt.Text)
 					break
 				}
 			}
 		}
-		return fmt.Sprintf("🎯 <b>Пульт ведущего</b>\n\n❓ <b>Вопрос %d из %d</b>\n\n%s%s\n\n📊 Ответили: <b>%d</b> из <b>%d</b>",
-			s.CurrentQuestion, s.TotalQuestions, qText, correctText, s.AnswerCount, participantCount)
+		return fmt.Sprintf("%s%s\n\n%s%s\n\n%s", title,
+			i18n.T(lang, "question.header", s.CurrentQuestion, s.TotalQuestions), qText, correctText,
+			i18n.T(lang, "control.answered", s.AnswerCount, participantCount))
 
 	case "finished":
-		return fmt.Sprintf("🎯 <b>Пульт ведущего</b>\n\n🏆 <b>Квиз завершён!</b>\n📋 %s\n👥 Участников: <b>%d</b>",
-			s.Quiz.Title, participantCount)
+		return title + i18n.T(lang, "control.finished", s.Quiz.Title, participantCount)
 
 	default:
-		return "🎯 <b>Пульт ведущего</b>\n\nСессия не найдена."
+		return title + i18n.T(lang, "control.not_found")
 	}
 }
 
 func (t *SessionTracker) sendOrEditHost(hr *HostRemoteInfo, text string, kb interface{}) int64 {
 	if hr.MessageID > 0 {
-		if err := t.client.EditMessageText(hr.ChatID, hr.MessageID, text, "HTML", kb); err == nil {
+		if err := t.control.EditMessageText(hr.ChatID, hr.MessageID, text, "HTML", kb); err == nil {
 			return 0
 		}
-		t.client.DeleteMessage(hr.ChatID, hr.MessageID)
+		t.control.DeleteMessage(hr.ChatID, hr.MessageID)
 	}
-	msgID, err := t.client.SendMessage(hr.ChatID, text, "HTML", kb)
+	msgID, err := t.control.SendMessage(hr.ChatID, text, "HTML", kb)
 	if err != nil {
 		log.Printf("send host control to %d: %v", hr.ChatID, err)
 		return 0
@@ -235,18 +340,16 @@ func (t *SessionTracker) SyncParticipant(sessionID uint, telegramID int64) {
 }
 
 func (t *SessionTracker) syncSendQuestion(info *SessionInfo, sessState *services.SessionState, tgID int64, p *ParticipantInfo) {
-	qd := sessState.CurrentQuestionData
-	current := sessState.CurrentQuestion
-	total := sessState.TotalQuestions
-	text := fmt.Sprintf("❓ <b>Вопрос %d из %d</b>\n\n%s", current, total, qd.Text)
+	qd := buildQuestionData(info.SessionID, sessState.CurrentQuestionData)
+	defer t.updateFSMData(tgID, qd, sessState.CurrentQuestion, sessState.TotalQuestions)
 
-	var opts []QuestionOption
-	for _, o := range qd.Options {
-		opts = append(opts, QuestionOption{ID: o.ID, Text: o.Text})
+	if poll := t.buildQuizPoll(info.SessionID, qd, sessState.CurrentQuestion, sessState.TotalQuestions); poll != nil {
+		t.sendQuizPollTo(info, tgID, p, sessState.CurrentQuestionData.Images, poll)
+		return
 	}
-	kb := AnswerKeyboard(info.SessionID, opts, 0)
 
-	msgID := t.sendOrEdit(p, text, kb)
+	text, kb := questionMessage(t.state.Locale(tgID), qd, sessState.CurrentQuestion, sessState.TotalQuestions)
+	msgID := t.sendQuestionTo(p, sessState.CurrentQuestionData.Images, text, kb)
 	if msgID > 0 {
 		info.mu.Lock()
 		if pp, ok := info.Participants[tgID]; ok {
@@ -254,8 +357,6 @@ func (t *SessionTracker) syncSendQuestion(info *SessionInfo, sessState *services
 		}
 		info.mu.Unlock()
 	}
-
-	t.updateFSM(tgID, info.SessionID, qd.Text, opts, current, total)
 }
 
 func (t *SessionTracker) syncSendResult(info *SessionInfo, sessState *services.SessionState, tgID int64, p *ParticipantInfo) {
@@ -268,8 +369,8 @@ func (t *SessionTracker) syncSendResult(info *SessionInfo, sessState *services.S
 		return
 	}
 
-	text := t.buildResultText(qd, result, current, total)
-	msgID := t.sendOrEdit(p, text, nil)
+	text := t.buildResultText(t.state.Locale(tgID), qd, result, current, total)
+	msgID := t.sendOrEdit(t.results, p, text, resultKeyboard(sessState))
 	if msgID > 0 {
 		info.mu.Lock()
 		if pp, ok := info.Participants[tgID]; ok {
@@ -291,6 +392,8 @@ func (t *SessionTracker) removeSession(sessionID uint) {
 }
 
 func (t *SessionTracker) Stop() {
+	t.unsubscribe()
+
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	for id, ch := range t.stopChs {
@@ -300,7 +403,50 @@ func (t *SessionTracker) Stop() {
 	t.sessions = make(map[uint]*SessionInfo)
 }
 
-func (t *SessionTracker) pollLoop(sessionID uint, stopCh chan struct{}) {
+// handleEvent queues session events for sessions this bot is tracking and
+// wakes their worker; it runs on the event bus and never sends itself. Answer
+// events carry no state, so they only matter for the answer counters of the
+// host remote and group chats and are debounced.
+func (t *SessionTracker) handleEvent(ev services.SessionEvent) {
+	t.mu.Lock()
+	info, ok := t.sessions[ev.SessionID]
+	t.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	info.mu.Lock()
+	if ev.State != nil {
+		info.queue = append(info.queue, ev.State)
+		info.mu.Unlock()
+		info.kick()
+		return
+	}
+	if info.HostRemote == nil && info.Group == nil {
+		info.mu.Unlock()
+		return
+	}
+	if ev.QuestionID == 0 {
+		info.reload = true
+		info.mu.Unlock()
+		info.kick()
+		return
+	}
+	if info.answers == nil || info.answers.questionID != ev.QuestionID || info.answers.count < ev.AnswerCount {
+		info.answers = &answerUpdate{questionID: ev.QuestionID, count: ev.AnswerCount}
+	}
+	schedule := !info.pending
+	info.pending = true
+	info.mu.Unlock()
+	if schedule {
+		time.AfterFunc(answerDebounce, info.kick)
+	}
+}
+
+// pollLoop is the session's worker. It applies queued events in order and, as
+// a fallback, reconciles changes whose events were missed, e.g. when the
+// subscriber queue overflowed.
+func (t *SessionTracker) pollLoop(info *SessionInfo, stopCh chan struct{}) {
 	ticker := time.NewTicker(t.pollInterval)
 	defer ticker.Stop()
 
@@ -308,12 +454,68 @@ func (t *SessionTracker) pollLoop(sessionID uint, stopCh chan struct{}) {
 		select {
 		case <-stopCh:
 			return
+		case <-info.wake:
+			t.drain(info)
 		case <-ticker.C:
-			t.checkSession(sessionID)
+			t.checkSession(info.SessionID)
 		}
 	}
 }
 
+// drain applies the events queued for a session.
+func (t *SessionTracker) drain(info *SessionInfo) {
+	for {
+		info.mu.Lock()
+		if len(info.queue) == 0 {
+			break
+		}
+		sessState := info.queue[0]
+		info.queue = info.queue[1:]
+		info.mu.Unlock()
+		t.applyState(info, sessState)
+	}
+	answers := info.answers
+	// Without a snapshot to redraw from, the session is loaded once.
+	reload := info.reload || (answers != nil && info.lastState == nil)
+	info.reload = false
+	info.answers = nil
+	info.pending = false
+	info.mu.Unlock()
+
+	if reload {
+		t.checkSession(info.SessionID)
+	} else if answers != nil {
+		t.applyAnswers(info, answers)
+	}
+}
+
+// applyAnswers shows a newer answer count of the current question on the host
+// remote and in the group chat.
+func (t *SessionTracker) applyAnswers(info *SessionInfo, answers *answerUpdate) {
+	info.syncMu.Lock()
+	defer info.syncMu.Unlock()
+
+	info.mu.Lock()
+	if info.LastStatus != "question" || info.lastQuestionID != answers.questionID || answers.count <= info.LastAnswerCount {
+		info.mu.Unlock()
+		return
+	}
+	info.LastAnswerCount = answers.count
+	sessState := *info.lastState
+	sessState.AnswerCount = answers.count
+	info.lastState = &sessState
+	hasHostRemote := info.HostRemote != nil
+	hasGroup := info.Group != nil
+	info.mu.Unlock()
+
+	if hasHostRemote {
+		t.updateHostControl(info, &sessState)
+	}
+	if hasGroup {
+		t.refreshGroupCount(info, answers.count)
+	}
+}
+
 func (t *SessionTracker) checkSession(sessionID uint) {
 	t.mu.Lock()
 	info, ok := t.sessions[sessionID]
@@ -326,7 +528,27 @@ func (t *SessionTracker) checkSession(sessionID uint) {
 	if err != nil {
 		return
 	}
+	t.applyState(info, sessState)
+}
+
+// stateProgress orders session snapshots so a stale one can be told apart.
+func stateProgress(status string, question int) int {
+	switch status {
+	case "question":
+		return question * 2
+	case "revealed":
+		return question*2 + 1
+	case "finished":
+		return math.MaxInt
+	}
+	return 0
+}
+
+func (t *SessionTracker) applyState(info *SessionInfo, sessState *services.SessionState) {
+	info.syncMu.Lock()
+	defer info.syncMu.Unlock()
 
+	sessionID := info.SessionID
 	status := sessState.Status
 	currentQ := sessState.CurrentQuestion
 	ansCount := sessState.AnswerCount
@@ -336,6 +558,13 @@ func (t *SessionTracker) checkSession(sessionID uint) {
 	prevQ := info.LastQuestion
 	prevAns := info.LastAnswerCount
 	hasHostRemote := info.HostRemote != nil
+	hasGroup := info.Group != nil
+
+	if stateProgress(status, currentQ) < stateProgress(prevStatus, prevQ) {
+		// An event snapshot older than what the poll already applied.
+		info.mu.Unlock()
+		return
+	}
 
 	statusChanged := prevStatus != status || prevQ != currentQ
 	answerCountChanged := prevAns != ansCount
@@ -347,6 +576,8 @@ func (t *SessionTracker) checkSession(sessionID uint) {
 	if answerCountChanged {
 		info.LastAnswerCount = ansCount
 	}
+	info.lastState = sessState
+	info.lastQuestionID = questionID(sessState)
 	info.mu.Unlock()
 
 	if !statusChanged && !answerCountChanged {
@@ -356,10 +587,21 @@ func (t *SessionTracker) checkSession(sessionID uint) {
 	if statusChanged {
 		if status == "question" && sessState.CurrentQuestionData != nil && currentQ != prevQ {
 			t.sendQuestion(info, sessState)
+			if hasGroup {
+				t.sendGroupQuestion(info, sessState)
+			}
 		} else if status == "revealed" && prevStatus == "question" {
+			t.closePolls(sessionID)
 			t.sendResults(info, sessState)
+			if hasGroup {
+				t.sendGroupReveal(info, sessState)
+			}
 		} else if status == "finished" && prevStatus != "finished" {
+			t.closePolls(sessionID)
 			t.sendLeaderboard(info)
+			if hasGroup {
+				t.sendGroupLeaderboard(info, prevQ > 0)
+			}
 
 			if hasHostRemote {
 				t.updateHostControl(info, sessState)
@@ -372,6 +614,9 @@ func (t *SessionTracker) checkSession(sessionID uint) {
 	if hasHostRemote && (statusChanged || answerCountChanged) {
 		t.updateHostControl(info, sessState)
 	}
+	if hasGroup && answerCountChanged && !statusChanged && status == "question" {
+		t.refreshGroupCount(info, ansCount)
+	}
 }
 
 func (t *SessionTracker) updateHostControl(info *SessionInfo, sessState *services.SessionState) {
@@ -382,8 +627,9 @@ func (t *SessionTracker) updateHostControl(info *SessionInfo, sessState *service
 		return
 	}
 
-	text := t.buildHostControlText(sessState)
-	kb := HostControlKeyboard(info.SessionID, sessState.Status, sessState.CurrentQuestion, sessState.TotalQuestions)
+	lang := t.state.Locale(hr.ChatID)
+	text := t.buildHostControlText(lang, sessState)
+	kb := HostControlKeyboard(lang, info.SessionID, sessState.Status, sessState.CurrentQuestion, sessState.TotalQuestions)
 
 	msgID := t.sendOrEditHost(hr, text, kb)
 	if msgID > 0 {
@@ -397,14 +643,14 @@ func (t *SessionTracker) updateHostControl(info *SessionInfo, sessState *service
 
 // sendOrEdit tries to edit the existing message; on failure sends a new one.
 // Returns the new messageID if a new message was sent, 0 if edit succeeded.
-func (t *SessionTracker) sendOrEdit(p *ParticipantInfo, text string, kb interface{}) int64 {
+func (t *SessionTracker) sendOrEdit(c *Client, p *ParticipantInfo, text string, kb interface{}) int64 {
 	if p.MessageID > 0 {
-		if err := t.client.EditMessageText(p.ChatID, p.MessageID, text, "HTML", kb); err == nil {
+		if err := c.EditMessageText(p.ChatID, p.MessageID, text, "HTML", kb); err == nil {
 			return 0
 		}
-		t.client.DeleteMessage(p.ChatID, p.MessageID)
+		c.DeleteMessage(p.ChatID, p.MessageID)
 	}
-	msgID, err := t.client.SendMessage(p.ChatID, text, "HTML", kb)
+	msgID, err := c.SendMessage(p.ChatID, text, "HTML", kb)
 	if err != nil {
 		log.Printf("send msg to %d: %v", p.ChatID, err)
 		return 0
@@ -412,37 +658,30 @@ func (t *SessionTracker) sendOrEdit(p *ParticipantInfo, text string, kb interfac
 	return msgID
 }
 
-func (t *SessionTracker) sendQuestion(info *SessionInfo, sessState *services.SessionState) {
-	qd := sessState.CurrentQuestionData
-	current := sessState.CurrentQuestion
-	total := sessState.TotalQuestions
-	qType := qd.Type
-	if qType == "" {
-		qType = "single_choice"
+// sendQuestionTo posts a question. Without media the previous message is edited
+// in place; with media the files go first and the question with its keyboard
+// follows as a new message so the buttons stay below the pictures.
+func (t *SessionTracker) sendQuestionTo(p *ParticipantInfo, images []services.ImageResponse, text string, kb interface{}) int64 {
+	if len(images) == 0 || t.media == nil {
+		return t.sendOrEdit(t.questions, p, text, kb)
 	}
-
-	var opts []QuestionOption
-	for _, o := range qd.Options {
-		opts = append(opts, QuestionOption{ID: o.ID, Text: o.Text})
+	if p.MessageID > 0 {
+		t.questions.DeleteMessage(p.ChatID, p.MessageID)
 	}
+	t.media.SendQuestionMedia(p.ChatID, images)
 
-	var text string
-	var kb interface{}
-
-	switch qType {
-	case "ordering", "matching":
-		text = fmt.Sprintf("❓ <b>Вопрос %d из %d</b>\n\n%s\n\n⚠️ Этот вопрос доступен только в веб-версии.\nОткройте страницу квиза в браузере для ответа.", current, total, qd.Text)
-		kb = nil
-	case "multiple_choice":
-		text = fmt.Sprintf("❓ <b>Вопрос %d из %d</b>\n\n%s\n\n<i>Выберите все правильные ответы и нажмите «Подтвердить»</i>", current, total, qd.Text)
-		kb = MultiChoiceKeyboard(info.SessionID, opts, nil)
-	case "numeric":
-		text = fmt.Sprintf("❓ <b>Вопрос %d из %d</b>\n\n%s\n\n<i>Введите число в чат:</i>", current, total, qd.Text)
-		kb = nil
-	default:
-		text = fmt.Sprintf("❓ <b>Вопрос %d из %d</b>\n\n%s", current, total, qd.Text)
-		kb = AnswerKeyboard(info.SessionID, opts, 0)
+	msgID, err := t.questions.SendMessage(p.ChatID, text, "HTML", kb)
+	if err != nil {
+		log.Printf("send msg to %d: %v", p.ChatID, err)
+		return 0
 	}
+	return msgID
+}
+
+func (t *SessionTracker) sendQuestion(info *SessionInfo, sessState *services.SessionState) {
+	qd := buildQuestionData(info.SessionID, sessState.CurrentQuestionData)
+	current := sessState.CurrentQuestion
+	total := sessState.TotalQuestions
 
 	info.mu.Lock()
 	participants := make(map[int64]*ParticipantInfo, len(info.Participants))
@@ -451,8 +690,32 @@ func (t *SessionTracker) sendQuestion(info *SessionInfo, sessState *services.Ses
 	}
 	info.mu.Unlock()
 
-	for tgID, p := range participants {
-		msgID := t.sendOrEdit(p, text, kb)
+	// Update every player's FSM first so nobody answers against the old question.
+	type rendered struct {
+		text string
+		kb   interface{}
+	}
+	byLocale := make(map[string]rendered)
+	messages := make(map[int64]rendered, len(participants))
+	for tgID := range participants {
+		t.updateFSMData(tgID, qd, current, total)
+
+		lang := t.state.Locale(tgID)
+		msg, ok := byLocale[lang]
+		if !ok {
+			msg.text, msg.kb = questionMessage(lang, qd, current, total)
+			byLocale[lang] = msg
+		}
+		messages[tgID] = msg
+	}
+	poll := t.buildQuizPoll(info.SessionID, qd, current, total)
+	t.fanOut(participants, func(tgID int64, p *ParticipantInfo) {
+		if poll != nil {
+			t.sendQuizPollTo(info, tgID, p, sessState.CurrentQuestionData.Images, poll)
+			return
+		}
+		msg := messages[tgID]
+		msgID := t.sendQuestionTo(p, sessState.CurrentQuestionData.Images, msg.text, msg.kb)
 		if msgID > 0 {
 			info.mu.Lock()
 			if pp, ok := info.Participants[tgID]; ok {
@@ -460,90 +723,143 @@ func (t *SessionTracker) sendQuestion(info *SessionInfo, sessState *services.Ses
 			}
 			info.mu.Unlock()
 		}
-		t.updateFSMTyped(tgID, info.SessionID, qd.Text, qType, opts, current, total)
+	})
+}
+
+// sendQuizPollTo sends the question to a player as a quiz poll. The poll can't
+// be edited, so the result goes out as a new message below it.
+func (t *SessionTracker) sendQuizPollTo(info *SessionInfo, tgID int64, p *ParticipantInfo, images []services.ImageResponse, poll *quizPoll) {
+	t.sendPollTo(info.SessionID, p.ChatID, images, poll, nil, false)
+	info.mu.Lock()
+	if pp, ok := info.Participants[tgID]; ok {
+		pp.MessageID = 0
 	}
+	info.mu.Unlock()
 }
 
-func (t *SessionTracker) updateFSM(userID int64, sessionID uint, qText string, opts []QuestionOption, current, total int) {
-	t.updateFSMTyped(userID, sessionID, qText, "single_choice", opts, current, total)
+// fanOut runs send for every participant concurrently; the client's outbox
+// paces the requests, so they go out as fast as Telegram's limits allow.
+func (t *SessionTracker) fanOut(participants map[int64]*ParticipantInfo, send func(tgID int64, p *ParticipantInfo)) {
+	var wg sync.WaitGroup
+	for tgID, p := range participants {
+		wg.Add(1)
+		go func(tgID int64, p *ParticipantInfo) {
+			defer wg.Done()
+			send(tgID, p)
+		}(tgID, p)
+	}
+	wg.Wait()
 }
 
-func (t *SessionTracker) updateFSMTyped(userID int64, sessionID uint, qText, qType string, opts []QuestionOption, current, total int) {
+func (t *SessionTracker) updateFSMData(userID int64, qd *QuestionData, current, total int) {
 	t.state.UpdateField(userID, func(s *UserState) {
-		s.QuestionData = &QuestionData{
-			Text:      qText,
-			Type:      qType,
-			SessionID: sessionID,
-			Options:   opts,
-		}
+		s.QuestionData = qd
 		s.CurrentQNum = current
 		s.TotalQuestions = total
 		s.SelectedOptionID = 0
 		s.SelectedOptionIDs = nil
-		if qType == "numeric" {
+		s.OrderIDs = nil
+		s.MatchPicks = nil
+		if qd.Type == "numeric" {
 			s.State = StateEnterNumeric
 		}
 	})
 }
 
-func (t *SessionTracker) buildResultText(qd *services.QuestionResponse, result *services.ParticipantResult, current, total int) string {
+func (t *SessionTracker) buildResultText(lang string, qd *services.QuestionResponse, result *services.ParticipantResult, current, total int) string {
 	var resultLine, scoreLine string
 	if !result.Answered {
-		resultLine = "⏰ Вы не успели ответить"
-		scoreLine = fmt.Sprintf("\nВсего очков: <b>%d</b>", result.TotalScore)
+		resultLine = i18n.T(lang, "result.missed")
+		scoreLine = "\n" + i18n.T(lang, "result.total", result.TotalScore)
 	} else if result.IsCorrect {
-		resultLine = "✅ <b>Правильно!</b>"
-		scoreLine = fmt.Sprintf("\nОчки за вопрос: <b>+%d</b> | Всего: <b>%d</b>", result.Score, result.TotalScore)
+		resultLine = i18n.T(lang, "result.correct")
+		scoreLine = "\n" + i18n.T(lang, "result.score", result.Score, result.TotalScore)
 	} else {
-		resultLine = "❌ <b>Неправильно</b>"
-		scoreLine = fmt.Sprintf("\nВсего очков: <b>%d</b>", result.TotalScore)
+		resultLine = i18n.T(lang, "result.wrong")
+		scoreLine = "\n" + i18n.T(lang, "result.total", result.TotalScore)
 	}
 
 	correctText := ""
+	if answer := correctAnswerText(lang, qd); answer != "" {
+		correctText = "\n\n" + answer
+	}
+
+	questionText := ""
 	if qd != nil {
-		qType := qd.Type
-		if qType == "" {
-			qType = "single_choice"
+		questionText = qd.Text
+	}
+
+	return fmt.Sprintf("%s\n\n%s\n\n%s%s%s\n\n%s", i18n.T(lang, "question.header", current, total),
+		questionText, resultLine, scoreLine, correctText, i18n.T(lang, "result.wait_next"))
+}
+
+// correctAnswerText renders the correct answer of a revealed question, or ""
+// if it isn't known.
+func correctAnswerText(lang string, qd *services.QuestionResponse) string {
+	if qd == nil {
+		return ""
+	}
+	correctText := ""
+	qType := qd.Type
+	if qType == "" {
+		qType = "single_choice"
+	}
+	switch qType {
+	case "single_choice":
+		for _, opt := range qd.Options {
+			if opt.IsCorrect != nil && *opt.IsCorrect {
+				correctText = i18n.T(lang, "result.answer", opt.Text)
+				break
+			}
 		}
-		switch qType {
-		case "single_choice":
-			for _, opt := range qd.Options {
-				if opt.IsCorrect != nil && *opt.IsCorrect {
-					correctText = fmt.Sprintf("\n\nПравильный ответ: <b>%s</b>", opt.Text)
-					break
-				}
+	case "multiple_choice":
+		var correct []string
+		for _, opt := range qd.Options {
+			if opt.IsCorrect != nil && *opt.IsCorrect {
+				correct = append(correct, opt.Text)
 			}
-		case "multiple_choice":
-			var correct []string
-			for _, opt := range qd.Options {
-				if opt.IsCorrect != nil && *opt.IsCorrect {
-					correct = append(correct, opt.Text)
-				}
+		}
+		if len(correct) > 0 {
+			correctText = i18n.T(lang, "result.answers", strings.Join(correct, ", "))
+		}
+	case "ordering":
+		ordered := make([]services.OptionResponse, 0, len(qd.Options))
+		for _, opt := range qd.Options {
+			if opt.CorrectPosition != nil {
+				ordered = append(ordered, opt)
 			}
-			if len(correct) > 0 {
-				correctText = "\n\nПравильные: <b>" + strings.Join(correct, ", ") + "</b>"
+		}
+		sort.Slice(ordered, func(i, j int) bool { return *ordered[i].CorrectPosition < *ordered[j].CorrectPosition })
+		if len(ordered) > 0 {
+			correctText = i18n.T(lang, "result.order")
+			for i, opt := range ordered {
+				correctText += fmt.Sprintf("\n%d. <b>%s</b>", i+1, opt.Text)
 			}
-		case "ordering":
-			correctText = "\n\n<i>Правильный порядок показан в веб-версии</i>"
-		case "matching":
-			correctText = "\n\n<i>Правильные пары показаны в веб-версии</i>"
-		case "numeric":
-			if qd.CorrectNumber != nil {
-				correctText = fmt.Sprintf("\n\nПравильный ответ: <b>%g</b>", *qd.CorrectNumber)
-				if qd.Tolerance != nil && *qd.Tolerance > 0 {
-					correctText += fmt.Sprintf(" (±%g)", *qd.Tolerance)
-				}
+		}
+	case "matching":
+		if len(qd.Options) > 0 {
+			correctText = i18n.T(lang, "result.pairs")
+			for _, opt := range qd.Options {
+				correctText += fmt.Sprintf("\n%s → <b>%s</b>", opt.Text, opt.MatchText)
+			}
+		}
+	case "numeric":
+		if qd.CorrectNumber != nil {
+			correctText = i18n.T(lang, "result.answer", fmt.Sprintf("%g", *qd.CorrectNumber))
+			if qd.Tolerance != nil && *qd.Tolerance > 0 {
+				correctText += fmt.Sprintf(" (±%g)", *qd.Tolerance)
 			}
 		}
 	}
+	return correctText
+}
 
-	questionText := ""
-	if qd != nil {
-		questionText = qd.Text
+// resultKeyboard offers reactions under results when the session runs in a room.
+func resultKeyboard(sessState *services.SessionState) interface{} {
+	if sessState.RoomID == 0 {
+		return nil
 	}
-
-	return fmt.Sprintf("❓ <b>Вопрос %d из %d</b>\n\n%s\n\n%s%s%s\n\n⏳ Ожидайте следующий вопрос...",
-		current, total, questionText, resultLine, scoreLine, correctText)
+	return ReactionKeyboard(sessState.RoomID)
 }
 
 func (t *SessionTracker) sendResults(info *SessionInfo, sessState *services.SessionState) {
@@ -558,14 +874,15 @@ func (t *SessionTracker) sendResults(info *SessionInfo, sessState *services.Sess
 	}
 	info.mu.Unlock()
 
-	for tgID, p := range participants {
+	kb := resultKeyboard(sessState)
+	t.fanOut(participants, func(tgID int64, p *ParticipantInfo) {
 		result, err := t.sessionSvc.GetParticipantResult(info.SessionID, tgID)
 		if err != nil {
-			continue
+			return
 		}
 
-		text := t.buildResultText(qd, result, current, total)
-		msgID := t.sendOrEdit(p, text, nil)
+		text := t.buildResultText(t.state.Locale(tgID), qd, result, current, total)
+		msgID := t.sendOrEdit(t.results, p, text, kb)
 		if msgID > 0 {
 			info.mu.Lock()
 			if pp, ok := info.Participants[tgID]; ok {
@@ -573,7 +890,7 @@ func (t *SessionTracker) sendResults(info *SessionInfo, sessState *services.Sess
 			}
 			info.mu.Unlock()
 		}
-	}
+	})
 }
 
 func (t *SessionTracker) sendLeaderboard(info *SessionInfo) {
@@ -582,17 +899,6 @@ func (t *SessionTracker) sendLeaderboard(info *SessionInfo) {
 		return
 	}
 
-	medals := map[int]string{1: "🥇", 2: "🥈", 3: "🥉"}
-	lines := []string{"🏆 <b>Квиз завершён! Итоги:</b>\n"}
-	for _, e := range entries {
-		medal, ok := medals[e.Position]
-		if !ok {
-			medal = fmt.Sprintf("%d.", e.Position)
-		}
-		lines = append(lines, fmt.Sprintf("%s <b>%s</b> — %d очков", medal, e.Nickname, e.TotalScore))
-	}
-	baseText := strings.Join(lines, "\n")
-
 	info.mu.Lock()
 	participants := make(map[int64]*ParticipantInfo, len(info.Participants))
 	for k, v := range info.Participants {
@@ -600,22 +906,36 @@ func (t *SessionTracker) sendLeaderboard(info *SessionInfo) {
 	}
 	info.mu.Unlock()
 
-	for tgID, p := range participants {
-		personal := baseText
+	t.fanOut(participants, func(tgID int64, p *ParticipantInfo) {
+		lang := t.state.Locale(tgID)
+		personal := leaderboardText(lang, entries)
 		for _, e := range entries {
 			if e.TelegramID == tgID {
-				personal += fmt.Sprintf("\n\n📍 Ваше место: <b>%d</b>", e.Position)
+				personal += "\n\n" + i18n.T(lang, "leaderboard.place", e.Position)
 				break
 			}
 		}
-		personal += "\n\nДля новой игры нажмите /start"
+		personal += "\n\n" + i18n.T(lang, "leaderboard.again")
 
 		if p.MessageID > 0 {
-			t.client.EditMessageText(p.ChatID, p.MessageID, personal, "HTML", nil)
+			t.results.EditMessageText(p.ChatID, p.MessageID, personal, "HTML", nil)
 		} else {
-			t.client.SendMessage(p.ChatID, personal, "HTML", nil)
+			t.results.SendMessage(p.ChatID, personal, "HTML", nil)
 		}
 
 		t.state.Clear(tgID)
+	})
+}
+
+func leaderboardText(lang string, entries []services.LeaderboardEntry) string {
+	medals := map[int]string{1: "🥇", 2: "🥈", 3: "🥉"}
+	lines := []string{i18n.T(lang, "leaderboard.title")}
+	for _, e := range entries {
+		medal, ok := medals[e.Position]
+		if !ok {
+			medal = fmt.Sprintf("%d.", e.Position)
+		}
+		lines = append(lines, i18n.T(lang, "leaderboard.entry", medal, e.Nickname, e.TotalScore))
 	}
+	return strings.Join(lines, "\n")
 }
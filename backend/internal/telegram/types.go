@@ -6,6 +6,7 @@ type Update struct {
 	UpdateID      int64          `json:"update_id"`
 	Message       *Message       `json:"message,omitempty"`
 	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+	PollAnswer    *PollAnswer    `json:"poll_answer,omitempty"`
 }
 
 type Message struct {
@@ -14,6 +15,13 @@ type Message struct {
 	Chat      Chat            `json:"chat"`
 	Text      string          `json:"text"`
 	Entities  []MessageEntity `json:"entities,omitempty"`
+	Document  *Document       `json:"document,omitempty"`
+}
+
+type Document struct {
+	FileID   string `json:"file_id"`
+	FileName string `json:"file_name,omitempty"`
+	FileSize int64  `json:"file_size,omitempty"`
 }
 
 type CallbackQuery struct {
@@ -23,13 +31,30 @@ type CallbackQuery struct {
 	Data    string   `json:"data"`
 }
 
+// PollAnswer is a vote in a non-anonymous poll sent by the bot. OptionIDs are
+// indexes into the poll's options.
+type PollAnswer struct {
+	PollID    string `json:"poll_id"`
+	User      *User  `json:"user,omitempty"`
+	OptionIDs []int  `json:"option_ids"`
+}
+
 type User struct {
-	ID        int64  `json:"id"`
-	FirstName string `json:"first_name"`
+	ID           int64  `json:"id"`
+	FirstName    string `json:"first_name"`
+	Username     string `json:"username,omitempty"`
+	LanguageCode string `json:"language_code,omitempty"`
 }
 
 type Chat struct {
-	ID int64 `json:"id"`
+	ID    int64  `json:"id"`
+	Type  string `json:"type"` // "private", "group", "supergroup" or "channel"
+	Title string `json:"title,omitempty"`
+}
+
+// IsGroup reports whether the chat is a group or supergroup.
+func (c Chat) IsGroup() bool {
+	return c.Type == "group" || c.Type == "supergroup"
 }
 
 type MessageEntity struct {
@@ -45,6 +70,7 @@ type InlineKeyboardMarkup struct {
 type InlineKeyboardButton struct {
 	Text         string `json:"text"`
 	CallbackData string `json:"callback_data,omitempty"`
+	URL          string `json:"url,omitempty"`
 }
 
 type ReplyKeyboardMarkup struct {
@@ -75,23 +101,118 @@ type EditMessageTextRequest struct {
 	ReplyMarkup json.RawMessage `json:"reply_markup,omitempty"`
 }
 
+type InputPollOption struct {
+	Text string `json:"text"`
+}
+
+type SendPollRequest struct {
+	ChatID          int64             `json:"chat_id"`
+	Question        string            `json:"question"`
+	Options         []InputPollOption `json:"options"`
+	IsAnonymous     bool              `json:"is_anonymous"`
+	Type            string            `json:"type,omitempty"`
+	CorrectOptionID int               `json:"correct_option_id"`
+	ReplyMarkup     json.RawMessage   `json:"reply_markup,omitempty"`
+}
+
+type StopPollRequest struct {
+	ChatID      int64           `json:"chat_id"`
+	MessageID   int64           `json:"message_id"`
+	ReplyMarkup json.RawMessage `json:"reply_markup,omitempty"`
+}
+
 type AnswerCallbackQueryRequest struct {
 	CallbackQueryID string `json:"callback_query_id"`
 	Text            string `json:"text,omitempty"`
 	ShowAlert       bool   `json:"show_alert,omitempty"`
 }
 
+type GetUpdatesRequest struct {
+	Offset         int64    `json:"offset,omitempty"`
+	Timeout        int      `json:"timeout"`
+	AllowedUpdates []string `json:"allowed_updates,omitempty"`
+}
+
 type SetWebhookRequest struct {
-	URL         string `json:"url"`
-	SecretToken string `json:"secret_token,omitempty"`
+	URL            string   `json:"url"`
+	SecretToken    string   `json:"secret_token,omitempty"`
+	AllowedUpdates []string `json:"allowed_updates,omitempty"`
 }
 
 type APIResponse struct {
-	OK          bool            `json:"ok"`
-	Description string          `json:"description,omitempty"`
-	Result      json.RawMessage `json:"result,omitempty"`
+	OK          bool                `json:"ok"`
+	ErrorCode   int                 `json:"error_code,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Result      json.RawMessage     `json:"result,omitempty"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
+}
+
+type ResponseParameters struct {
+	RetryAfter int `json:"retry_after,omitempty"`
+}
+
+// APIError is a request Telegram rejected. RetryAfter is set on HTTP 429.
+type APIError struct {
+	Code        int
+	Description string
+	RetryAfter  int
+}
+
+func (e *APIError) Error() string {
+	return "telegram: " + e.Description
 }
 
 type MessageResult struct {
 	MessageID int64 `json:"message_id"`
 }
+
+type PollMessageResult struct {
+	MessageID int64 `json:"message_id"`
+	Poll      struct {
+		ID string `json:"id"`
+	} `json:"poll"`
+}
+
+// InputMedia is one item of a sendMediaGroup request. Media holds a file_id,
+// a URL, or "attach://<name>" for a file uploaded in the same request.
+type InputMedia struct {
+	Type      string `json:"type"`
+	Media     string `json:"media"`
+	Caption   string `json:"caption,omitempty"`
+	ParseMode string `json:"parse_mode,omitempty"`
+}
+
+type File struct {
+	FileID   string `json:"file_id"`
+	FilePath string `json:"file_path,omitempty"`
+}
+
+type PhotoSize struct {
+	FileID string `json:"file_id"`
+	Width  int    `json:"width"`
+}
+
+type FileRef struct {
+	FileID string `json:"file_id"`
+}
+
+type MediaMessageResult struct {
+	MessageID int64       `json:"message_id"`
+	Photo     []PhotoSize `json:"photo,omitempty"`
+	Audio     *FileRef    `json:"audio,omitempty"`
+	Video     *FileRef    `json:"video,omitempty"`
+}
+
+// FileID returns the file_id Telegram assigned to the media in this message.
+// For photos the largest size is used.
+func (m MediaMessageResult) FileID() string {
+	switch {
+	case len(m.Photo) > 0:
+		return m.Photo[len(m.Photo)-1].FileID
+	case m.Audio != nil:
+		return m.Audio.FileID
+	case m.Video != nil:
+		return m.Video.FileID
+	}
+	return ""
+}
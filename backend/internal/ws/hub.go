@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -17,12 +18,23 @@ type Hub struct {
 	mu       sync.RWMutex
 	sessions map[uint]map[*websocket.Conn]bool
 	rooms    map[uint]map[*websocket.Conn]bool
+	members  map[uint]map[*websocket.Conn]bool
+	// displays holds projector connections by the display token they
+	// opened with.
+	displays map[string]map[*websocket.Conn]bool
+	// writers serializes writes to each connection: broadcasts come from
+	// request handlers and background timers at once, and a connection
+	// supports one writer at a time.
+	writers map[*websocket.Conn]*sync.Mutex
 }
 
 func NewHub() *Hub {
 	return &Hub{
 		sessions: make(map[uint]map[*websocket.Conn]bool),
 		rooms:    make(map[uint]map[*websocket.Conn]bool),
+		members:  make(map[uint]map[*websocket.Conn]bool),
+		displays: make(map[string]map[*websocket.Conn]bool),
+		writers:  make(map[*websocket.Conn]*sync.Mutex),
 	}
 }
 
@@ -34,6 +46,9 @@ func (h *Hub) AddConnection(sessionID uint, conn *websocket.Conn) {
 		h.sessions[sessionID] = make(map[*websocket.Conn]bool)
 	}
 	h.sessions[sessionID][conn] = true
+	if h.writers[conn] == nil {
+		h.writers[conn] = &sync.Mutex{}
+	}
 	log.Printf("ws: client connected to session %d (total: %d)", sessionID, len(h.sessions[sessionID]))
 }
 
@@ -43,6 +58,7 @@ func (h *Hub) RemoveConnection(sessionID uint, conn *websocket.Conn) {
 
 	if conns, ok := h.sessions[sessionID]; ok {
 		delete(conns, conn)
+		delete(h.writers, conn)
 		conn.Close()
 		if len(conns) == 0 {
 			delete(h.sessions, sessionID)
@@ -58,6 +74,9 @@ func (h *Hub) AddRoomConnection(roomID uint, conn *websocket.Conn) {
 		h.rooms[roomID] = make(map[*websocket.Conn]bool)
 	}
 	h.rooms[roomID][conn] = true
+	if h.writers[conn] == nil {
+		h.writers[conn] = &sync.Mutex{}
+	}
 	log.Printf("ws: client connected to room %d (total: %d)", roomID, len(h.rooms[roomID]))
 }
 
@@ -67,6 +86,7 @@ func (h *Hub) RemoveRoomConnection(roomID uint, conn *websocket.Conn) {
 
 	if conns, ok := h.rooms[roomID]; ok {
 		delete(conns, conn)
+		delete(h.writers, conn)
 		conn.Close()
 		if len(conns) == 0 {
 			delete(h.rooms, roomID)
@@ -75,49 +95,185 @@ func (h *Hub) RemoveRoomConnection(roomID uint, conn *websocket.Conn) {
 }
 
 func (h *Hub) Broadcast(sessionID uint, message WSMessage) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+	h.broadcast(h.sessions, sessionID, message)
+}
 
-	conns, ok := h.sessions[sessionID]
-	if !ok {
-		return
-	}
+func (h *Hub) BroadcastToRoom(roomID uint, message WSMessage) {
+	h.broadcast(h.rooms, roomID, message)
+}
 
+// broadcast sends a message to the connections of one session or room. The
+// hub lock is only held to pick the connections, not while writing; ones
+// that fail are closed and dropped afterwards.
+func (h *Hub) broadcast(groups map[uint]map[*websocket.Conn]bool, id uint, message WSMessage) {
 	data, err := json.Marshal(message)
 	if err != nil {
 		log.Printf("ws: marshal error: %v", err)
 		return
 	}
 
-	for conn := range conns {
-		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+	h.mu.RLock()
+	targets := make([]writeTarget, 0, len(groups[id]))
+	for conn := range groups[id] {
+		targets = append(targets, writeTarget{conn, h.writers[conn]})
+	}
+	h.mu.RUnlock()
+
+	var dead []*websocket.Conn
+	for _, t := range targets {
+		if err := write(t.conn, t.mu, websocket.TextMessage, data); err != nil {
 			log.Printf("ws: write error: %v", err)
-			conn.Close()
+			dead = append(dead, t.conn)
+		}
+	}
+	if len(dead) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, conn := range dead {
+		conn.Close()
+		if conns, ok := groups[id]; ok {
 			delete(conns, conn)
+			if len(conns) == 0 {
+				delete(groups, id)
+			}
 		}
+		delete(h.writers, conn)
 	}
 }
 
-func (h *Hub) BroadcastToRoom(roomID uint, message WSMessage) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+// writeWait bounds a single write, so a client that stopped reading can't
+// hold its connection's write lock.
+const writeWait = 10 * time.Second
 
-	conns, ok := h.rooms[roomID]
-	if !ok {
-		return
+// write sends one message on a connection, holding its write lock. A
+// connection without a lock has already been removed.
+func write(conn *websocket.Conn, mu *sync.Mutex, messageType int, data []byte) error {
+	if mu == nil {
+		return nil
 	}
+	mu.Lock()
+	defer mu.Unlock()
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return conn.WriteMessage(messageType, data)
+}
 
+// AddMemberConnection tags a room connection as belonging to a member so it
+// can be reached individually.
+func (h *Hub) AddMemberConnection(memberID uint, conn *websocket.Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.members[memberID] == nil {
+		h.members[memberID] = make(map[*websocket.Conn]bool)
+	}
+	h.members[memberID][conn] = true
+}
+
+func (h *Hub) RemoveMemberConnection(memberID uint, conn *websocket.Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if conns, ok := h.members[memberID]; ok {
+		delete(conns, conn)
+		if len(conns) == 0 {
+			delete(h.members, memberID)
+		}
+	}
+}
+
+// DisconnectMember sends a final message to every connection of a member and
+// closes them. The read loops then unwind and unregister the connections.
+func (h *Hub) DisconnectMember(memberID uint, message WSMessage) {
+	h.mu.Lock()
+	targets := h.detach(h.members[memberID])
+	delete(h.members, memberID)
+	h.mu.Unlock()
+
+	closeConnections(targets, message)
+}
+
+// AddDisplayConnection tags a room connection as a projector opened with a
+// display token, so it can be cut off when the token is replaced.
+func (h *Hub) AddDisplayConnection(token string, conn *websocket.Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.displays[token] == nil {
+		h.displays[token] = make(map[*websocket.Conn]bool)
+	}
+	h.displays[token][conn] = true
+}
+
+func (h *Hub) RemoveDisplayConnection(token string, conn *websocket.Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if conns, ok := h.displays[token]; ok {
+		delete(conns, conn)
+		if len(conns) == 0 {
+			delete(h.displays, token)
+		}
+	}
+}
+
+// DisconnectDisplay sends a final message to every projector opened with a
+// display token and closes them.
+func (h *Hub) DisconnectDisplay(token string, message WSMessage) {
+	h.mu.Lock()
+	targets := h.detach(h.displays[token])
+	delete(h.displays, token)
+	h.mu.Unlock()
+
+	closeConnections(targets, message)
+}
+
+// writeTarget is a connection with its write lock, picked under h.mu and
+// written to after releasing it.
+type writeTarget struct {
+	conn *websocket.Conn
+	mu   *sync.Mutex
+}
+
+// detach takes connections out of every session and room so broadcasts stop
+// reaching them, and returns them for closing. The caller must hold h.mu.
+func (h *Hub) detach(conns map[*websocket.Conn]bool) []writeTarget {
+	targets := make([]writeTarget, 0, len(conns))
+	for conn := range conns {
+		targets = append(targets, writeTarget{conn, h.writers[conn]})
+		for _, groups := range []map[uint]map[*websocket.Conn]bool{h.rooms, h.sessions} {
+			for id, groupConns := range groups {
+				delete(groupConns, conn)
+				if len(groupConns) == 0 {
+					delete(groups, id)
+				}
+			}
+		}
+		delete(h.writers, conn)
+	}
+	return targets
+}
+
+// closeConnections sends message and a close frame to each connection and
+// closes it. The hub lock must not be held: a slow client only delays itself.
+func closeConnections(targets []writeTarget, message WSMessage) {
+	if len(targets) == 0 {
+		return
+	}
 	data, err := json.Marshal(message)
 	if err != nil {
 		log.Printf("ws: marshal error: %v", err)
-		return
+		data = nil
 	}
 
-	for conn := range conns {
-		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-			log.Printf("ws: write error: %v", err)
-			conn.Close()
-			delete(conns, conn)
+	closeMsg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, message.Type)
+	for _, t := range targets {
+		if data != nil {
+			write(t.conn, t.mu, websocket.TextMessage, data)
 		}
+		write(t.conn, t.mu, websocket.CloseMessage, closeMsg)
+		t.conn.Close()
 	}
 }
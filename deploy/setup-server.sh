@@ -43,6 +43,7 @@ if [ ! -f "$APP_DIR/.env" ]; then
   echo "    Required values to set:"
   echo "    - JWT_SECRET (random string)"
   echo "    - BOT_API_KEY (random string)"
+  echo "    - BOT_TOKEN_KEYS (e.g. k1:\$(openssl rand -base64 32))"
   echo "    - DB_PASSWORD (secure password)"
   echo "    - WEBHOOK_BASE_URL (https://yourdomain.com)"
 else
@@ -0,0 +1,15 @@
+import api from './axios';
+
+export const getAPIKeys = () => api.get('/api-keys');
+
+export const createAPIKey = (name, scopes, expiresInDays) =>
+  api.post('/api-keys', { name, scopes, expires_in_days: expiresInDays });
+
+export const deleteAPIKey = (id) => api.delete(`/api-keys/${id}`);
+
+export const SCOPE_LABELS = {
+  'quizzes:read': 'Чтение квизов',
+  'quizzes:write': 'Изменение квизов',
+  'rooms:control': 'Управление комнатами',
+  'analytics:read': 'Результаты',
+};
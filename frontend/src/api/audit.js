@@ -0,0 +1,32 @@
+import api from './axios';
+
+export const getAuditLog = (params) => api.get('/audit', { params });
+
+export const AUDIT_ACTION_LABELS = {
+  'quiz.create': 'Создан квиз',
+  'quiz.update': 'Изменён квиз',
+  'quiz.delete': 'Удалён квиз',
+  'quiz.import': 'Импорт вопросов',
+  'room.open': 'Открыта комната',
+  'room.close': 'Закрыта комната',
+  'session.create': 'Запущен квиз',
+  'session.start': 'Первый вопрос',
+  'session.reveal': 'Показан ответ',
+  'session.next': 'Следующий вопрос',
+  'session.finish': 'Квиз завершён',
+  'settings.update': 'Изменены настройки',
+};
+
+export const AUDIT_GROUP_LABELS = {
+  quiz: 'Квизы',
+  room: 'Комнаты',
+  session: 'Сессии',
+  settings: 'Настройки',
+};
+
+export const AUDIT_ACTOR_LABELS = {
+  user: 'панель',
+  api_key: 'API-ключ',
+  telegram: 'пульт в Telegram',
+  system: 'автоматически',
+};
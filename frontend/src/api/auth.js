@@ -5,3 +5,57 @@ export const registerHost = (username, password) =>
 
 export const loginHost = (username, password) =>
   api.post('/auth/login', { username, password });
+
+export const logoutHost = (refreshToken) =>
+  api.post('/auth/logout', { refresh_token: refreshToken });
+
+export const logoutAllDevices = () => api.post('/auth/logout-all');
+
+export const getLoginSessions = () => api.get('/auth/sessions');
+
+export const revokeLoginSession = (id) => api.delete(`/auth/sessions/${id}`);
+
+export const changePassword = (currentPassword, newPassword) =>
+  api.put('/auth/password', { current_password: currentPassword, new_password: newPassword });
+
+export const resetPassword = (token, newPassword) =>
+  api.post('/auth/password/reset', { token, new_password: newPassword });
+
+export const changeUsername = (username) => api.put('/auth/username', { username });
+
+export const deleteAccount = (username, password) =>
+  api.delete('/auth/account', { data: { username, password } });
+
+export const getSSOConfig = () => api.get('/auth/oidc');
+
+export const startSSO = () => api.post('/auth/oidc/start');
+
+export const finishSSO = (code, state) => api.post('/auth/oidc/callback', { code, state });
+
+export const getIdentities = () => api.get('/auth/identities');
+
+export const startIdentityLink = () => api.post('/auth/identities/link');
+
+export const finishIdentityLink = (code, state) => api.post('/auth/identities/callback', { code, state });
+
+export const unlinkIdentity = (id) => api.delete(`/auth/identities/${id}`);
+
+// The state of a sign-in this browser started, checked when the provider
+// sends it back so a sign-in started elsewhere can't be slipped in.
+const SSO_FLOW_KEY = 'sso_flow';
+
+export const beginSSORedirect = (url, mode) => {
+  const state = new URL(url).searchParams.get('state');
+  sessionStorage.setItem(SSO_FLOW_KEY, JSON.stringify({ state, mode }));
+  window.location.href = url;
+};
+
+export const takeSSOFlow = () => {
+  try {
+    return JSON.parse(sessionStorage.getItem(SSO_FLOW_KEY)) || {};
+  } catch {
+    return {};
+  } finally {
+    sessionStorage.removeItem(SSO_FLOW_KEY);
+  }
+};
@@ -5,25 +5,92 @@ const api = axios.create({
   headers: { 'Content-Type': 'application/json' },
 });
 
+export const storeTokens = (data) => {
+  localStorage.setItem('token', data.token);
+  localStorage.setItem('refresh_token', data.refresh_token);
+};
+
+export const clearTokens = () => {
+  localStorage.removeItem('token');
+  localStorage.removeItem('refresh_token');
+  localStorage.removeItem('username');
+  localStorage.removeItem('workspace_id');
+};
+
+// One refresh at a time: requests failing together wait for the same one.
+let refreshing = null;
+
+const refreshTokens = async () => {
+  const used = localStorage.getItem('refresh_token');
+  if (!used) throw new Error('no refresh token');
+  try {
+    const { data } = await axios.post('/api/v1/auth/refresh', { refresh_token: used });
+    storeTokens(data);
+  } catch (err) {
+    // Another tab may have rotated the token first; its new pair is already stored.
+    const current = localStorage.getItem('refresh_token');
+    if (current && current !== used) return;
+    throw err;
+  }
+};
+
+const redirectToLogin = () => {
+  clearTokens();
+  if (!window.location.pathname.startsWith('/login')) {
+    window.location.href = '/login';
+  }
+};
+
 api.interceptors.request.use((config) => {
   const token = localStorage.getItem('token');
   if (token) {
     config.headers.Authorization = `Bearer ${token}`;
   }
+  const workspaceId = localStorage.getItem('workspace_id');
+  if (workspaceId) {
+    config.headers['X-Workspace-ID'] = workspaceId;
+  }
   return config;
 });
 
 api.interceptors.response.use(
   (res) => res,
-  (err) => {
-    if (err.response?.status === 401) {
-      localStorage.removeItem('token');
-      localStorage.removeItem('username');
-      if (!window.location.pathname.startsWith('/login')) {
-        window.location.href = '/login';
+  async (err) => {
+    const original = err.config;
+    // The workspace was left or access to it was taken away: fall back to our own.
+    if (err.response?.data?.code === 'workspace_access' && localStorage.getItem('workspace_id')) {
+      localStorage.removeItem('workspace_id');
+      window.location.href = '/dashboard';
+      return Promise.reject(err);
+    }
+    // The workspace admits single sign-on logins only: leave it, or sign in again if it is our own.
+    if (err.response?.data?.code === 'sso_required') {
+      if (localStorage.getItem('workspace_id')) {
+        localStorage.removeItem('workspace_id');
+        window.location.href = '/dashboard';
+      } else {
+        clearTokens();
+        window.location.href = '/login?sso=required';
       }
+      return Promise.reject(err);
+    }
+    if (err.response?.status !== 401 || !original || original.url?.startsWith('/auth/')) {
+      return Promise.reject(err);
+    }
+    if (original._retried || !localStorage.getItem('refresh_token')) {
+      redirectToLogin();
+      return Promise.reject(err);
+    }
+
+    original._retried = true;
+    try {
+      refreshing = refreshing || refreshTokens().finally(() => { refreshing = null; });
+      await refreshing;
+    } catch {
+      redirectToLogin();
+      return Promise.reject(err);
     }
-    return Promise.reject(err);
+    return api(original);
   }
 );
 
@@ -10,7 +10,7 @@ export const playJoin = (code, nickname, token) =>
   playApi.post('/play/join', { code, nickname, token });
 
 export const playReconnect = (token, code) =>
-  playApi.get('/play/reconnect', { params: { token, code } });
+  playApi.post('/play/reconnect', { token, code });
 
 export const playAnswer = (sessionId, memberId, token, optionId) =>
   playApi.post('/play/answer', {
@@ -26,8 +26,8 @@ export const playGetState = (token, code) =>
 export const playUpdateNickname = (token, roomCode, nickname) =>
   playApi.put('/play/nickname', { token, room_code: roomCode, nickname });
 
-export const playGetMyResult = (sessionId, memberId) =>
-  playApi.get('/play/my-result', { params: { session_id: sessionId, member_id: memberId } });
+export const playGetMyResult = (sessionId, token) =>
+  playApi.get('/play/my-result', { params: { session_id: sessionId, token } });
 
 export const playAnswerComplex = (sessionId, memberId, token, answerData) =>
   playApi.post('/play/answer-complex', {
@@ -39,3 +39,12 @@ export const playAnswerComplex = (sessionId, memberId, token, answerData) =>
 
 export const playLeave = (token, roomCode) =>
   playApi.post('/play/leave', { token, room_code: roomCode });
+
+export const playReact = (token, roomCode, emoji) =>
+  playApi.post('/play/react', { token, room_code: roomCode, emoji });
+
+export const playSendChat = (token, roomCode, text) =>
+  playApi.post('/play/chat', { token, room_code: roomCode, text });
+
+export const playGetChat = (token, code) =>
+  playApi.get('/play/chat', { params: { token, code } });
@@ -29,3 +29,27 @@ export const roomLeaderboard = (roomId) =>
 
 export const listRoomHistory = () =>
   api.get('/rooms/history');
+
+export const roomChat = (roomId) =>
+  api.get(`/rooms/${roomId}/chat`);
+
+export const roomClearChat = (roomId) =>
+  api.delete(`/rooms/${roomId}/chat`);
+
+export const roomChatSettings = (roomId, settings) =>
+  api.put(`/rooms/${roomId}/chat/settings`, settings);
+
+export const roomMute = (roomId, { memberId, telegramId, muted }) =>
+  api.post(`/rooms/${roomId}/chat/mute`, { member_id: memberId, telegram_id: telegramId, muted });
+
+export const roomKick = (roomId, { memberId, telegramId, ban }) =>
+  api.post(`/rooms/${roomId}/kick`, { member_id: memberId, telegram_id: telegramId, ban });
+
+export const roomRename = (roomId, { memberId, telegramId, nickname }) =>
+  api.put(`/rooms/${roomId}/rename`, { member_id: memberId, telegram_id: telegramId, nickname });
+
+export const roomBans = (roomId) =>
+  api.get(`/rooms/${roomId}/bans`);
+
+export const roomUnban = (roomId, banId) =>
+  api.delete(`/rooms/${roomId}/bans/${banId}`);
@@ -3,3 +3,9 @@ import api from './axios';
 export const getSettings = () => api.get('/settings');
 
 export const updateSettings = (data) => api.put('/settings', data);
+
+export const createPairingCode = () => api.post('/settings/remote/pairing-code');
+
+export const getRemoteAuthorizations = () => api.get('/settings/remote/authorizations');
+
+export const revokeRemoteAuthorization = (id) => api.delete(`/settings/remote/authorizations/${id}`);
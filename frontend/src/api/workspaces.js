@@ -0,0 +1,20 @@
+import api from './axios';
+
+export const getWorkspaces = () => api.get('/workspaces');
+
+export const getMembers = () => api.get('/workspaces/members');
+
+export const addMember = (username, role) =>
+  api.post('/workspaces/members', { username, role });
+
+export const updateMember = (id, role) =>
+  api.put(`/workspaces/members/${id}`, { role });
+
+export const removeMember = (id) => api.delete(`/workspaces/members/${id}`);
+
+export const ROLE_LABELS = {
+  owner: 'Владелец',
+  editor: 'Редактор',
+  presenter: 'Ведущий',
+  viewer: 'Наблюдатель',
+};
@@ -1,14 +1,36 @@
+import { useEffect, useState } from 'react';
 import { useDispatch, useSelector } from 'react-redux';
 import { useNavigate } from 'react-router-dom';
 import { logout } from '../store/authSlice';
+import { logoutHost } from '../api/auth';
+import { getWorkspaces, ROLE_LABELS } from '../api/workspaces';
 import './Header.css';
 
 export default function Header() {
   const dispatch = useDispatch();
   const navigate = useNavigate();
   const username = useSelector((s) => s.auth.username);
+  const [workspaces, setWorkspaces] = useState([]);
+  const workspaceId = localStorage.getItem('workspace_id') || '';
+
+  useEffect(() => {
+    getWorkspaces()
+      .then(({ data }) => setWorkspaces(data || []))
+      .catch(() => {});
+  }, []);
+
+  const switchWorkspace = (id) => {
+    if (id && String(id) !== String(workspaces[0]?.id)) {
+      localStorage.setItem('workspace_id', id);
+    } else {
+      localStorage.removeItem('workspace_id');
+    }
+    window.location.href = '/dashboard';
+  };
 
   const handleLogout = () => {
+    const refreshToken = localStorage.getItem('refresh_token');
+    if (refreshToken) logoutHost(refreshToken).catch(() => {});
     dispatch(logout());
     navigate('/login');
   };
@@ -19,6 +41,19 @@ export default function Header() {
         Quiz Game
       </div>
       <div className="header-right">
+        {workspaces.length > 1 && (
+          <select
+            className="header-workspace"
+            value={workspaceId || workspaces[0].id}
+            onChange={(e) => switchWorkspace(e.target.value)}
+          >
+            {workspaces.map((w) => (
+              <option key={w.id} value={w.id}>
+                {w.name} · {ROLE_LABELS[w.role] || w.role}
+              </option>
+            ))}
+          </select>
+        )}
         <button className="btn-settings" onClick={() => navigate('/settings')}>Настройки</button>
         <span className="header-user">{username}</span>
         <button className="btn-logout" onClick={handleLogout}>Выйти</button>
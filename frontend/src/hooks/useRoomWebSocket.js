@@ -1,13 +1,24 @@
 import { useEffect, useRef } from 'react';
 
-export default function useRoomWebSocket(roomCode, onMessage) {
+const HEARTBEAT_MS = 30000;
+
+export default function useRoomWebSocket(roomCode, onMessage, token) {
   const wsRef = useRef(null);
 
   useEffect(() => {
     if (!roomCode) return;
 
     const proto = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-    const url = `${proto}//${window.location.host}/ws/room/${roomCode}`;
+    const query = token ? `?token=${encodeURIComponent(token)}` : '';
+    const url = `${proto}//${window.location.host}/ws/room/${roomCode}${query}`;
+
+    // Players report activity so the host sees them as online; a hidden tab goes quiet and turns "away".
+    const heartbeat = token ? setInterval(() => {
+      const ws = wsRef.current;
+      if (ws && ws.readyState === WebSocket.OPEN && document.visibilityState === 'visible') {
+        ws.send(JSON.stringify({ type: 'ping' }));
+      }
+    }, HEARTBEAT_MS) : null;
 
     const connect = () => {
       const ws = new WebSocket(url);
@@ -28,10 +39,11 @@ export default function useRoomWebSocket(roomCode, onMessage) {
     connect();
 
     return () => {
+      if (heartbeat) clearInterval(heartbeat);
       if (wsRef.current) {
         wsRef.current.onclose = null;
         wsRef.current.close();
       }
     };
-  }, [roomCode]);
+  }, [roomCode, token]);
 }
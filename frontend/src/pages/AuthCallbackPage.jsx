@@ -0,0 +1,60 @@
+import { useEffect, useRef, useState } from 'react';
+import { useDispatch } from 'react-redux';
+import { Link, useNavigate, useSearchParams } from 'react-router-dom';
+import { ssoLogin } from '../store/authSlice';
+import { finishIdentityLink, takeSSOFlow } from '../api/auth';
+import './Auth.css';
+
+// The identity provider sends the browser back here with code and state,
+// both for signing in and for linking an identity in the settings.
+export default function AuthCallbackPage() {
+  const [searchParams] = useSearchParams();
+  const dispatch = useDispatch();
+  const navigate = useNavigate();
+  const [error, setError] = useState('');
+  const done = useRef(false);
+
+  useEffect(() => {
+    if (done.current) return;
+    done.current = true;
+
+    const code = searchParams.get('code');
+    const state = searchParams.get('state');
+    const flow = takeSSOFlow();
+    if (searchParams.get('error')) {
+      setError(searchParams.get('error_description') || 'Провайдер отклонил вход');
+      return;
+    }
+    if (!code || !state || flow.state !== state) {
+      setError('Вход был начат не в этом браузере или устарел. Попробуйте снова.');
+      return;
+    }
+
+    if (flow.mode === 'link') {
+      finishIdentityLink(code, state)
+        .then(() => navigate('/settings', { replace: true }))
+        .catch((err) => setError(err.response?.data?.error || 'Не удалось привязать учётную запись'));
+      return;
+    }
+    dispatch(ssoLogin({ code, state })).then((res) => {
+      if (res.error) setError(res.payload);
+      else navigate('/dashboard', { replace: true });
+    });
+  }, []);
+
+  return (
+    <div className="auth-page">
+      <div className="auth-card">
+        <h1>Quiz Game</h1>
+        {error ? (
+          <>
+            <div className="error-msg">{error}</div>
+            <div className="auth-footer"><Link to="/login">Вернуться ко входу</Link></div>
+          </>
+        ) : (
+          <p className="auth-callback">Выполняется вход...</p>
+        )}
+      </div>
+    </div>
+  );
+}
@@ -27,7 +27,7 @@ export default function DashboardPage() {
   useEffect(() => {
     dispatch(loadQuizzes());
     getSettings()
-      .then(({ data }) => setHasBotToken(!!data.bot_token))
+      .then(({ data }) => setHasBotToken(!!(data.bot_token || data.bot_link)))
       .catch(() => setHasBotToken(false));
     checkAIStatus()
       .then(({ data }) => setAiAvailable(data.available))
@@ -1,7 +1,8 @@
 import { useState, useEffect } from 'react';
 import { useDispatch, useSelector } from 'react-redux';
-import { Link, useNavigate } from 'react-router-dom';
+import { Link, useNavigate, useSearchParams } from 'react-router-dom';
 import { login, clearError } from '../store/authSlice';
+import { getSSOConfig, startSSO, beginSSORedirect } from '../api/auth';
 import './Auth.css';
 
 export default function LoginPage() {
@@ -9,9 +10,15 @@ export default function LoginPage() {
   const [password, setPassword] = useState('');
   const dispatch = useDispatch();
   const navigate = useNavigate();
+  const [searchParams] = useSearchParams();
+  const [sso, setSso] = useState(null);
+  const [ssoError, setSsoError] = useState('');
   const { loading, error, token } = useSelector((s) => s.auth);
 
   useEffect(() => { dispatch(clearError()); }, []);
+  useEffect(() => {
+    getSSOConfig().then(({ data }) => { if (data.enabled) setSso(data); }).catch(() => {});
+  }, []);
   useEffect(() => { if (token) navigate('/dashboard'); }, [token]);
 
   const handleSubmit = (e) => {
@@ -19,13 +26,27 @@ export default function LoginPage() {
     dispatch(login({ username, password }));
   };
 
+  const handleSSO = async () => {
+    setSsoError('');
+    try {
+      const { data } = await startSSO();
+      beginSSORedirect(data.url, 'login');
+    } catch (err) {
+      setSsoError(err.response?.data?.error || 'Не удалось начать вход');
+    }
+  };
+
   return (
     <div className="auth-page">
       <div className="auth-card">
         <h1>Quiz Game</h1>
         <p className="subtitle">Войдите в личный кабинет</p>
 
+        {searchParams.get('sso') === 'required' && !error && (
+          <div className="error-msg">Это рабочее пространство требует входа через единый вход (SSO)</div>
+        )}
         {error && <div className="error-msg">{error}</div>}
+        {ssoError && <div className="error-msg">{ssoError}</div>}
 
         <form onSubmit={handleSubmit}>
           <div className="form-group">
@@ -41,6 +62,14 @@ export default function LoginPage() {
           </button>
         </form>
 
+        {sso && (
+          <button type="button" className="btn btn-outline auth-sso" onClick={handleSSO}>
+            Войти через {sso.name}
+          </button>
+        )}
+
+        <p className="auth-hint">Забыли пароль? Попросите администратора прислать ссылку для сброса.</p>
+
         <div className="auth-footer">
           Нет аккаунта? <Link to="/register">Зарегистрироваться</Link>
         </div>
@@ -9,16 +9,15 @@ import MediaViewer, { Lightbox } from '../components/MediaViewer';
 import './PlayPage.css';
 
 const LS_KEY = 'quizgame_play';
-const LS_TOKEN_KEY = 'quizgame_device_token';
+const LS_TOKEN_KEY = 'quizgame_player_token';
+// Renew the player token this long before it expires.
+const TOKEN_RENEW_MARGIN_MS = 10 * 60 * 1000;
 
 function loadStorage() { try { return JSON.parse(localStorage.getItem(LS_KEY)) || {}; } catch { return {}; } }
 function saveStorage(data) { localStorage.setItem(LS_KEY, JSON.stringify(data)); }
 function clearStorage() { localStorage.removeItem(LS_KEY); }
-function getDeviceToken() {
-  let t = localStorage.getItem(LS_TOKEN_KEY);
-  if (!t) { t = 'xxxx-xxxx-xxxx-xxxx'.replace(/x/g, () => Math.random().toString(36)[2]); localStorage.setItem(LS_TOKEN_KEY, t); }
-  return t;
-}
+function loadToken() { try { return JSON.parse(localStorage.getItem(LS_TOKEN_KEY)) || {}; } catch { return {}; } }
+function saveToken(data) { localStorage.setItem(LS_TOKEN_KEY, JSON.stringify({ token: data.token, expiresAt: data.token_expires_at })); }
 
 function SortablePlayItem({ id, children }) {
   const { attributes, listeners, setNodeRef, transform, transition, isDragging } = useSortable({ id });
@@ -36,7 +35,8 @@ export default function PlayPage() {
   const [phase, setPhase] = useState('join');
   const [code, setCode] = useState('');
   const [nickname, setNickname] = useState('');
-  const [token] = useState(() => getDeviceToken());
+  const [token, setToken] = useState(() => loadToken().token || '');
+  const [tokenExpiresAt, setTokenExpiresAt] = useState(() => loadToken().expiresAt || null);
   const [room, setRoom] = useState(null);
   const [member, setMember] = useState(null);
   const [members, setMembers] = useState([]);
@@ -65,7 +65,7 @@ export default function PlayPage() {
 
   useEffect(() => {
     const stored = loadStorage();
-    if (stored.roomCode) { setCode(stored.roomCode); tryReconnect(token, stored.roomCode); }
+    if (stored.roomCode && token) { setCode(stored.roomCode); tryReconnect(token, stored.roomCode); }
     else if (urlCode) setCode(urlCode);
   }, []);
 
@@ -74,10 +74,11 @@ export default function PlayPage() {
       const { data } = await playReconnect(t, c);
       enterRoom(data);
       if (data.leaderboard) setLeaderboard(data.leaderboard);
-    } catch { clearStorage(); }
+    } catch { clearStorage(); setPhase('join'); }
   };
 
   const enterRoom = (data) => {
+    applyToken(data);
     setRoom(data.room);
     setMember(data.member);
     setMembers(data.members || data.room?.members || []);
@@ -94,6 +95,24 @@ export default function PlayPage() {
     } else setPhase('lobby');
   };
 
+  // Join and reconnect hand out a new player token; the old one may stop working.
+  const applyToken = (data) => {
+    if (!data.token) return;
+    saveToken(data);
+    tokenRef.current = data.token;
+    setToken(data.token);
+    setTokenExpiresAt(data.token_expires_at);
+  };
+
+  useEffect(() => {
+    if (!room?.code || !tokenExpiresAt) return;
+    const delay = Math.max(new Date(tokenExpiresAt).getTime() - Date.now() - TOKEN_RENEW_MARGIN_MS, 0);
+    const timer = setTimeout(async () => {
+      try { const { data } = await playReconnect(tokenRef.current, room.code); applyToken(data); } catch {}
+    }, delay);
+    return () => clearTimeout(timer);
+  }, [room?.code, tokenExpiresAt]);
+
   const resetAnswer = (sess) => {
     setSelectedOption(null);
     setSelectedOptions([]);
@@ -152,15 +171,24 @@ export default function PlayPage() {
         setSession(null); sessionRef.current = null;
         setPhase('lobby'); resetAnswer(null); setLeaderboard([]);
       }
-    } catch { clearStorage(); setPhase('join'); }
+    } catch (err) {
+      // An expired or replaced token can still be renewed; anything else means we are out of the room.
+      if (err.response?.status === 401) { tryReconnect(t, rc); return; }
+      clearStorage(); setPhase('join');
+    }
   }, []);
 
   const onWsMessage = useCallback((msg) => {
     if (msg.type === 'room_closed') { clearStorage(); setPhase('join'); setRoom(null); return; }
+    if (msg.type === 'kicked') {
+      clearStorage(); setPhase('join'); setRoom(null);
+      setError(msg.data?.banned ? 'Ведущий удалил вас из комнаты и закрыл доступ' : 'Ведущий удалил вас из комнаты');
+      return;
+    }
     refreshState();
   }, [refreshState]);
 
-  useRoomWebSocket(room?.code, onWsMessage);
+  useRoomWebSocket(room?.code, onWsMessage, token);
 
   const handleSingleAnswer = async (optionId) => {
     if (!session || !member) return;
@@ -0,0 +1,66 @@
+import { useState } from 'react';
+import { Link, useSearchParams } from 'react-router-dom';
+import { resetPassword } from '../api/auth';
+import './Auth.css';
+
+// Opened from the reset link an administrator sends; the token comes in the
+// query string.
+export default function ResetPasswordPage() {
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get('token') || '';
+  const [password, setPassword] = useState('');
+  const [confirm, setConfirm] = useState('');
+  const [error, setError] = useState('');
+  const [saving, setSaving] = useState(false);
+  const [done, setDone] = useState(false);
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    if (password !== confirm) {
+      setError('Пароли не совпадают');
+      return;
+    }
+    setError('');
+    setSaving(true);
+    try {
+      await resetPassword(token, password);
+      setDone(true);
+    } catch (err) {
+      setError(err.response?.data?.error || 'Не удалось сбросить пароль');
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="auth-page">
+      <div className="auth-card">
+        <h1>Quiz Game</h1>
+        {done ? (
+          <p className="auth-callback">Пароль изменён. Войдите с новым паролем.</p>
+        ) : !token ? (
+          <div className="error-msg">В ссылке нет токена сброса. Попросите администратора прислать новую.</div>
+        ) : (
+          <>
+            <p className="subtitle">Задайте новый пароль</p>
+            {error && <div className="error-msg">{error}</div>}
+            <form onSubmit={handleSubmit}>
+              <div className="form-group">
+                <label>Новый пароль</label>
+                <input type="password" value={password} onChange={(e) => setPassword(e.target.value)} minLength={6} maxLength={72} required />
+              </div>
+              <div className="form-group">
+                <label>Повторите пароль</label>
+                <input type="password" value={confirm} onChange={(e) => setConfirm(e.target.value)} minLength={6} maxLength={72} required />
+              </div>
+              <button type="submit" className="btn btn-primary" disabled={saving}>
+                {saving ? 'Сохранение...' : 'Сохранить пароль'}
+              </button>
+            </form>
+          </>
+        )}
+        <div className="auth-footer"><Link to="/login">Вернуться ко входу</Link></div>
+      </div>
+    </div>
+  );
+}
@@ -1,14 +1,57 @@
 import { useEffect, useState } from 'react';
 import { useNavigate } from 'react-router-dom';
+import { useDispatch, useSelector } from 'react-redux';
 import Header from '../components/Header';
-import { getSettings, updateSettings } from '../api/settings';
+import { getSettings, updateSettings, createPairingCode, getRemoteAuthorizations, revokeRemoteAuthorization } from '../api/settings';
+import {
+  getLoginSessions, revokeLoginSession, logoutAllDevices, getIdentities, startIdentityLink, unlinkIdentity, beginSSORedirect,
+  changePassword, changeUsername, deleteAccount,
+} from '../api/auth';
+import { logout, setUsername } from '../store/authSlice';
+import { getAPIKeys, createAPIKey, deleteAPIKey, SCOPE_LABELS } from '../api/apiKeys';
+import { getWorkspaces, getMembers, addMember, updateMember, removeMember, ROLE_LABELS } from '../api/workspaces';
+import { getAuditLog, AUDIT_ACTION_LABELS, AUDIT_GROUP_LABELS, AUDIT_ACTOR_LABELS } from '../api/audit';
 import './SettingsPage.css';
 
 export default function SettingsPage() {
   const navigate = useNavigate();
+  const dispatch = useDispatch();
+  const username = useSelector((s) => s.auth.username);
+  const [newUsername, setNewUsername] = useState(username || '');
+  const [currentPassword, setCurrentPassword] = useState('');
+  const [newPassword, setNewPassword] = useState('');
+  const [accountMessage, setAccountMessage] = useState('');
+  const [accountError, setAccountError] = useState('');
+  const [loginSessions, setLoginSessions] = useState([]);
+  const [selfId, setSelfId] = useState(null);
+  const [role, setRole] = useState('owner');
+  const [members, setMembers] = useState([]);
+  const [newMember, setNewMember] = useState('');
+  const [newRole, setNewRole] = useState('presenter');
+  const [teamError, setTeamError] = useState('');
+  const [apiKeys, setApiKeys] = useState([]);
+  const [keyName, setKeyName] = useState('');
+  const [keyScopes, setKeyScopes] = useState(['quizzes:read']);
+  const [keyExpiry, setKeyExpiry] = useState(90);
+  const [createdKey, setCreatedKey] = useState('');
+  const [keyError, setKeyError] = useState('');
   const [botToken, setBotToken] = useState('');
+  const [maskedToken, setMaskedToken] = useState('');
   const [botLink, setBotLink] = useState('');
   const [remotePassword, setRemotePassword] = useState('');
+  const [remotePasswordSet, setRemotePasswordSet] = useState(false);
+  const [remoteAuths, setRemoteAuths] = useState([]);
+  const [pairing, setPairing] = useState(null);
+  const [pairingError, setPairingError] = useState('');
+  const [botLanguage, setBotLanguage] = useState('ru');
+  const [botQuizPolls, setBotQuizPolls] = useState(false);
+  const [ssoAvailable, setSsoAvailable] = useState(false);
+  const [ssoRequired, setSsoRequired] = useState(false);
+  const [identities, setIdentities] = useState([]);
+  const [ssoError, setSsoError] = useState('');
+  const [auditEntries, setAuditEntries] = useState([]);
+  const [auditGroup, setAuditGroup] = useState('');
+  const [auditMore, setAuditMore] = useState(false);
   const [loading, setLoading] = useState(true);
   const [saving, setSaving] = useState(false);
   const [message, setMessage] = useState('');
@@ -16,21 +59,293 @@ export default function SettingsPage() {
   useEffect(() => {
     getSettings()
       .then(({ data }) => {
-        setBotToken(data.bot_token || '');
+        setMaskedToken(data.bot_token || '');
         setBotLink(data.bot_link || '');
-        setRemotePassword(data.remote_password || '');
+        setRemotePasswordSet(!!data.remote_password_set);
+        setBotLanguage(data.bot_language || 'ru');
+        setBotQuizPolls(!!data.bot_quiz_polls);
+        setSsoAvailable(!!data.sso_available);
+        setSsoRequired(!!data.sso_required);
       })
       .finally(() => setLoading(false));
+    loadLoginSessions();
+    loadIdentities();
+    getWorkspaces()
+      .then(({ data }) => {
+        const current = localStorage.getItem('workspace_id');
+        const ws = data.find((w) => String(w.id) === current) || data[0];
+        setSelfId(data[0]?.id);
+        setRole(ws?.role || 'owner');
+        if (!ws || ws.role === 'owner') {
+          loadAPIKeys();
+          loadRemoteAuths();
+          loadAudit('');
+        }
+      })
+      .catch(() => {});
+    loadMembers();
   }, []);
 
-  const handleSave = async (e) => {
+  const canManage = role === 'owner';
+
+  const loadAPIKeys = () => {
+    getAPIKeys()
+      .then(({ data }) => setApiKeys(data || []))
+      .catch(() => {});
+  };
+
+  const toggleScope = (scope) => {
+    setKeyScopes((prev) => (prev.includes(scope) ? prev.filter((s) => s !== scope) : [...prev, scope]));
+  };
+
+  const handleCreateKey = async (e) => {
+    e.preventDefault();
+    if (!keyName.trim()) return;
+    setKeyError('');
+    try {
+      const { data } = await createAPIKey(keyName.trim(), keyScopes, Number(keyExpiry));
+      setCreatedKey(data.key);
+      setKeyName('');
+      loadAPIKeys();
+    } catch (err) {
+      setKeyError(err.response?.data?.error || 'Не удалось создать ключ');
+    }
+  };
+
+  const handleDeleteKey = async (k) => {
+    if (!window.confirm(`Отозвать ключ «${k.name}»? Скрипты с ним перестанут работать.`)) return;
+    await deleteAPIKey(k.id).catch(() => {});
+    loadAPIKeys();
+  };
+
+  const AUDIT_PAGE = 30;
+
+  // Loads the newest entries, or the page older than beforeId.
+  const loadAudit = (group, beforeId) => {
+    const params = { limit: AUDIT_PAGE };
+    if (group) params.action = group;
+    if (beforeId) params.before_id = beforeId;
+    getAuditLog(params)
+      .then(({ data }) => {
+        const page = data || [];
+        setAuditEntries((prev) => (beforeId ? [...prev, ...page] : page));
+        setAuditMore(page.length === AUDIT_PAGE);
+      })
+      .catch(() => {});
+  };
+
+  const handleAuditGroup = (group) => {
+    setAuditGroup(group);
+    loadAudit(group);
+  };
+
+  const auditDetails = (e) => {
+    const after = e.after || {};
+    const before = e.before || {};
+    switch (e.target_type) {
+      case 'quiz':
+        return after.title || before.title || '';
+      case 'room':
+        return after.code || before.code ? `комната ${after.code || before.code}` : '';
+      case 'session':
+        return after.current_question ? `вопрос ${after.current_question}` : '';
+      case 'settings': {
+        const changed = Object.keys(after).filter((k) => JSON.stringify(after[k]) !== JSON.stringify(before[k]));
+        if (changed.includes('bot_token')) return `токен бота: ${after.bot_token || 'удалён'}`;
+        return changed.length ? changed.join(', ') : '';
+      }
+      default:
+        return '';
+    }
+  };
+
+  const loadRemoteAuths = () => {
+    getRemoteAuthorizations()
+      .then(({ data }) => setRemoteAuths(data || []))
+      .catch(() => {});
+  };
+
+  const handleCreatePairing = async () => {
+    setPairingError('');
+    try {
+      const { data } = await createPairingCode();
+      setPairing(data);
+    } catch (err) {
+      setPairingError(err.response?.data?.error || 'Не удалось создать код');
+    }
+  };
+
+  const handleRevokeRemote = async (a) => {
+    if (!window.confirm(`Отключить ${a.name || a.telegram_id} от пульта?`)) return;
+    await revokeRemoteAuthorization(a.id).catch(() => {});
+    loadRemoteAuths();
+  };
+
+  const loadMembers = () => {
+    getMembers()
+      .then(({ data }) => setMembers(data || []))
+      .catch(() => {});
+  };
+
+  const handleAddMember = async (e) => {
+    e.preventDefault();
+    if (!newMember.trim()) return;
+    setTeamError('');
+    try {
+      await addMember(newMember.trim(), newRole);
+      setNewMember('');
+      loadMembers();
+    } catch (err) {
+      setTeamError(err.response?.data?.error || 'Не удалось добавить');
+    }
+  };
+
+  const handleRoleChange = async (id, value) => {
+    setTeamError('');
+    try {
+      await updateMember(id, value);
+      loadMembers();
+    } catch (err) {
+      setTeamError(err.response?.data?.error || 'Не удалось изменить роль');
+    }
+  };
+
+  const handleRemoveMember = async (m) => {
+    const leaving = m.host_id === selfId;
+    if (!window.confirm(leaving ? 'Покинуть эту команду?' : `Убрать ${m.username} из команды?`)) return;
+    try {
+      await removeMember(m.id);
+      if (leaving) {
+        localStorage.removeItem('workspace_id');
+        window.location.href = '/dashboard';
+        return;
+      }
+      loadMembers();
+    } catch (err) {
+      setTeamError(err.response?.data?.error || 'Не удалось удалить');
+    }
+  };
+
+  const loadLoginSessions = () => {
+    getLoginSessions()
+      .then(({ data }) => setLoginSessions(data || []))
+      .catch(() => {});
+  };
+
+  const loadIdentities = () => {
+    getIdentities()
+      .then(({ data }) => setIdentities(data || []))
+      .catch(() => {});
+  };
+
+  const handleLinkIdentity = async () => {
+    setSsoError('');
+    try {
+      const { data } = await startIdentityLink();
+      beginSSORedirect(data.url, 'link');
+    } catch (err) {
+      setSsoError(err.response?.data?.error || 'Не удалось начать привязку');
+    }
+  };
+
+  const handleUnlinkIdentity = async (id) => {
+    if (!window.confirm('Отвязать эту учётную запись? Входить через неё станет нельзя.')) return;
+    setSsoError('');
+    try {
+      await unlinkIdentity(id);
+      loadIdentities();
+    } catch (err) {
+      setSsoError(err.response?.data?.error || 'Не удалось отвязать');
+    }
+  };
+
+  const handleSSORequired = async (required) => {
+    if (required && !window.confirm('Требовать единый вход? Участники команды без входа через SSO потеряют доступ к пространству, а вход по паролю в этот аккаунт отключится.')) return;
+    setSsoError('');
+    try {
+      const { data } = await updateSettings({ sso_required: required });
+      setSsoRequired(!!data.sso_required);
+    } catch (err) {
+      setSsoError(err.response?.data?.error || 'Не удалось сохранить');
+    }
+  };
+
+  const handleRevoke = async (id) => {
+    await revokeLoginSession(id).catch(() => {});
+    loadLoginSessions();
+  };
+
+  const handleLogoutAll = async () => {
+    if (!window.confirm('Выйти на всех устройствах, включая это?')) return;
+    await logoutAllDevices().catch(() => {});
+    dispatch(logout());
+    navigate('/login');
+  };
+
+  const handleChangeUsername = async (e) => {
+    e.preventDefault();
+    setAccountMessage('');
+    setAccountError('');
+    try {
+      const { data } = await changeUsername(newUsername.trim());
+      dispatch(setUsername(data.username));
+      setAccountMessage('Имя пользователя изменено');
+    } catch (err) {
+      setAccountError(err.response?.data?.error || 'Не удалось сменить имя');
+    }
+  };
+
+  const handleChangePassword = async (e) => {
     e.preventDefault();
+    setAccountMessage('');
+    setAccountError('');
+    try {
+      await changePassword(currentPassword, newPassword);
+      setCurrentPassword('');
+      setNewPassword('');
+      setAccountMessage('Пароль изменён, на других устройствах выполнен выход');
+      loadLoginSessions();
+    } catch (err) {
+      setAccountError(err.response?.data?.error || 'Не удалось сменить пароль');
+    }
+  };
+
+  const handleDeleteAccount = async () => {
+    if (!window.confirm('Удалить аккаунт? Квизы, комнаты, история сессий, бот и загруженные картинки будут удалены безвозвратно.')) return;
+    const confirmName = window.prompt('Введите имя пользователя для подтверждения');
+    if (confirmName === null) return;
+    const password = window.prompt('Введите пароль (для аккаунта без пароля оставьте пустым — нужен вход через SSO не раньше 10 минут назад)');
+    if (password === null) return;
+    setAccountMessage('');
+    setAccountError('');
+    try {
+      await deleteAccount(confirmName, password);
+      dispatch(logout());
+      navigate('/login');
+    } catch (err) {
+      setAccountError(err.response?.data?.error || 'Не удалось удалить аккаунт');
+    }
+  };
+
+  // The token and remote password are sent only when they change: '' removes them.
+  const saveSettings = async (newBotToken, newRemotePassword) => {
     setSaving(true);
     setMessage('');
     try {
-      const { data } = await updateSettings({ bot_token: botToken, remote_password: remotePassword });
+      const { data } = await updateSettings({
+        bot_token: newBotToken,
+        remote_password: newRemotePassword,
+        bot_language: botLanguage,
+        bot_quiz_polls: botQuizPolls,
+      });
       setBotLink(data.bot_link || '');
-      setRemotePassword(data.remote_password || '');
+      setBotToken('');
+      setMaskedToken(data.bot_token || '');
+      setRemotePassword('');
+      setRemotePasswordSet(!!data.remote_password_set);
+      if (newRemotePassword !== undefined) loadRemoteAuths();
+      setBotLanguage(data.bot_language || 'ru');
+      setBotQuizPolls(!!data.bot_quiz_polls);
       setMessage('Настройки сохранены');
       setTimeout(() => setMessage(''), 3000);
     } catch (err) {
@@ -40,6 +355,24 @@ export default function SettingsPage() {
     }
   };
 
+  const handleSave = (e) => {
+    e.preventDefault();
+    saveSettings(
+      botToken.trim() ? botToken.trim() : undefined,
+      remotePassword.trim() ? remotePassword : undefined,
+    );
+  };
+
+  const handleRemovePassword = () => {
+    if (!window.confirm('Удалить пароль пульта? Вошедшие по паролю потеряют доступ, останется вход по коду подключения.')) return;
+    saveSettings(undefined, '');
+  };
+
+  const handleDisconnectBot = () => {
+    if (!window.confirm('Отключить бота? Игроки не смогут играть через Telegram, пока вы не добавите токен снова.')) return;
+    saveSettings('', undefined);
+  };
+
   if (loading) return <><Header /><div className="dashboard"><div className="loading">Загрузка...</div></div></>;
 
   return (
@@ -51,7 +384,13 @@ export default function SettingsPage() {
           <button className="btn btn-outline btn-sm" onClick={() => navigate('/dashboard')}>← Назад</button>
         </div>
 
-        <form onSubmit={handleSave} className="settings-form">
+        {!canManage && (
+          <p className="settings-hint">
+            Настройки бота этой команды может менять только владелец.
+          </p>
+        )}
+
+        {canManage && <form onSubmit={handleSave} className="settings-form">
           <div className="settings-section">
             <h3>Telegram-бот</h3>
             <p className="settings-hint">
@@ -59,15 +398,51 @@ export default function SettingsPage() {
             </p>
 
             <label className="settings-label">
-              Токен бота
+              {maskedToken ? 'Новый токен бота' : 'Токен бота'}
               <input
                 type="text"
                 className="settings-input"
                 value={botToken}
                 onChange={(e) => setBotToken(e.target.value)}
-                placeholder="123456789:AAHk..."
+                placeholder={maskedToken ? `Сейчас ${maskedToken} — оставьте пустым, чтобы не менять` : '123456789:AAHk...'}
+                autoComplete="off"
+              />
+            </label>
+            {maskedToken && (
+              <p className="settings-hint">
+                Токен хранится в зашифрованном виде и целиком не показывается.{' '}
+                <button type="button" className="btn btn-outline btn-sm" onClick={handleDisconnectBot} disabled={saving}>
+                  Отключить бота
+                </button>
+              </p>
+            )}
+
+            <label className="settings-label">
+              Язык бота по умолчанию
+              <select
+                className="settings-input"
+                value={botLanguage}
+                onChange={(e) => setBotLanguage(e.target.value)}
+              >
+                <option value="ru">Русский</option>
+                <option value="en">English</option>
+              </select>
+            </label>
+            <p className="settings-hint">
+              Бот отвечает на языке Telegram игрока, если он поддерживается, иначе — на этом. Игрок может сменить язык командой /language.
+            </p>
+
+            <label className="settings-label settings-check">
+              <input
+                type="checkbox"
+                checked={botQuizPolls}
+                onChange={(e) => setBotQuizPolls(e.target.checked)}
               />
+              Вопросы с одним ответом — опросами-викторинами Telegram
             </label>
+            <p className="settings-hint">
+              Вопрос приходит нативным опросом: игрок сразу видит, угадал ли он, а изменить ответ нельзя. Опрос закрывается, когда ведущий показывает ответ. Вопросы с длинным текстом или вариантами остаются кнопками.
+            </p>
 
             {botLink && (
               <div className="settings-bot-link">
@@ -80,18 +455,25 @@ export default function SettingsPage() {
             <h3>Пульт ведущего</h3>
             <p className="settings-hint">
               Задайте пароль, чтобы управлять квизом прямо из Telegram-бота. В боте нажмите «🎯 Пульт ведущего» и введите этот пароль.
+              После пяти неверных попыток вход блокируется на 15 минут. Смена пароля отключает всех, кто вошёл по старому.
             </p>
 
             <label className="settings-label">
-              Пароль для пульта
+              {remotePasswordSet ? 'Новый пароль для пульта' : 'Пароль для пульта'}
               <input
-                type="text"
+                type="password"
                 className="settings-input"
                 value={remotePassword}
                 onChange={(e) => setRemotePassword(e.target.value)}
-                placeholder="Придумайте пароль"
+                placeholder={remotePasswordSet ? 'Пароль задан — оставьте пустым, чтобы не менять' : 'Придумайте пароль'}
+                autoComplete="new-password"
               />
             </label>
+            {remotePasswordSet && (
+              <button type="button" className="btn btn-outline btn-sm" onClick={handleRemovePassword} disabled={saving}>
+                Удалить пароль
+              </button>
+            )}
           </div>
 
           <div style={{ display: 'flex', gap: 12, alignItems: 'center' }}>
@@ -100,7 +482,340 @@ export default function SettingsPage() {
             </button>
             {message && <span className={message.startsWith('Ошибка') ? 'text-error' : 'text-success'}>{message}</span>}
           </div>
-        </form>
+        </form>}
+
+        <div className="settings-form settings-sessions">
+          <div className="settings-section">
+            <h3>Команда</h3>
+            <p className="settings-hint">
+              Коллеги входят под своими аккаунтами и работают с квизами и комнатами этого аккаунта.
+              Редактор меняет квизы и проводит игры, ведущий — только проводит, наблюдатель видит квизы и результаты.
+            </p>
+
+            {members.map((m) => (
+              <div key={m.id} className="login-session">
+                <div className="login-session-info">
+                  <div className="login-session-agent">{m.username}</div>
+                </div>
+                {canManage ? (
+                  <select
+                    className="settings-input team-role"
+                    value={m.role}
+                    onChange={(e) => handleRoleChange(m.id, e.target.value)}
+                  >
+                    {Object.entries(ROLE_LABELS).map(([value, label]) => (
+                      <option key={value} value={value}>{label}</option>
+                    ))}
+                  </select>
+                ) : (
+                  <span className="login-session-meta">{ROLE_LABELS[m.role] || m.role}</span>
+                )}
+                {(canManage || m.host_id === selfId) && (
+                  <button type="button" className="btn btn-outline btn-sm" onClick={() => handleRemoveMember(m)}>
+                    {m.host_id === selfId ? 'Покинуть' : 'Убрать'}
+                  </button>
+                )}
+              </div>
+            ))}
+
+            {canManage && (
+              <form className="team-add" onSubmit={handleAddMember}>
+                <input
+                  type="text"
+                  className="settings-input"
+                  value={newMember}
+                  onChange={(e) => setNewMember(e.target.value)}
+                  placeholder="Логин коллеги"
+                />
+                <select className="settings-input team-role" value={newRole} onChange={(e) => setNewRole(e.target.value)}>
+                  {Object.entries(ROLE_LABELS).map(([value, label]) => (
+                    <option key={value} value={value}>{label}</option>
+                  ))}
+                </select>
+                <button type="submit" className="btn btn-primary btn-sm">Добавить</button>
+              </form>
+            )}
+            {teamError && <p className="text-error">{teamError}</p>}
+          </div>
+        </div>
+
+        {canManage && (
+          <div className="settings-form settings-sessions">
+            <div className="settings-section">
+              <h3>Доступ к пульту</h3>
+              <p className="settings-hint">
+                Код подключения открывает пульт без пароля: он одноразовый и действует 10 минут.
+                Ниже — Telegram-аккаунты, вошедшие в пульт; отключите тех, кому доступ больше не нужен.
+              </p>
+
+              {remoteAuths.map((a) => (
+                <div key={a.id} className="login-session">
+                  <div className="login-session-info">
+                    <div className="login-session-agent">{a.name || `Telegram ID ${a.telegram_id}`}</div>
+                    <div className="login-session-meta">
+                      {a.method === 'pairing' ? 'по коду' : 'по паролю'}
+                      {' · '}активность {new Date(a.last_used_at).toLocaleString('ru-RU')}
+                    </div>
+                  </div>
+                  <button type="button" className="btn btn-outline btn-sm" onClick={() => handleRevokeRemote(a)}>
+                    Отключить
+                  </button>
+                </div>
+              ))}
+
+              {pairing && (
+                <div className="settings-bot-link">
+                  Код подключения: <code className="api-key-value">{pairing.code}</code>
+                  {pairing.link && (
+                    <>
+                      <br />Или откройте ссылку на телефоне: <a href={pairing.link} target="_blank" rel="noreferrer">{pairing.link}</a>
+                    </>
+                  )}
+                  <br />Действует до {new Date(pairing.expires_at).toLocaleTimeString('ru-RU')}
+                </div>
+              )}
+
+              <button type="button" className="btn btn-outline btn-sm" onClick={handleCreatePairing}>
+                Создать код подключения
+              </button>
+              {pairingError && <p className="text-error">{pairingError}</p>}
+            </div>
+          </div>
+        )}
+
+        {canManage && (
+          <div className="settings-form settings-sessions">
+            <div className="settings-section">
+              <h3>API-ключи</h3>
+              <p className="settings-hint">
+                Ключи для скриптов и CI: загрузка квизов, запуск комнат, выгрузка результатов без пароля.
+                Передавайте ключ в заголовке <code>Authorization: Bearer &lt;ключ&gt;</code>.
+              </p>
+
+              {apiKeys.map((k) => (
+                <div key={k.id} className="login-session">
+                  <div className="login-session-info">
+                    <div className="login-session-agent">{k.name} <code>{k.prefix}…</code></div>
+                    <div className="login-session-meta">
+                      {k.scopes.map((sc) => SCOPE_LABELS[sc] || sc).join(', ')}
+                      {' · '}
+                      {k.last_used_at ? `использован ${new Date(k.last_used_at).toLocaleString('ru-RU')}` : 'не использовался'}
+                      {k.expires_at && ` · до ${new Date(k.expires_at).toLocaleDateString('ru-RU')}`}
+                    </div>
+                  </div>
+                  <button type="button" className="btn btn-outline btn-sm" onClick={() => handleDeleteKey(k)}>
+                    Отозвать
+                  </button>
+                </div>
+              ))}
+
+              {createdKey && (
+                <div className="settings-bot-link">
+                  Скопируйте ключ — больше он показан не будет:<br />
+                  <code className="api-key-value">{createdKey}</code>
+                </div>
+              )}
+
+              <form className="api-key-form" onSubmit={handleCreateKey}>
+                <input
+                  type="text"
+                  className="settings-input"
+                  value={keyName}
+                  onChange={(e) => setKeyName(e.target.value)}
+                  placeholder="Название, например «CI»"
+                />
+                <div className="api-key-scopes">
+                  {Object.entries(SCOPE_LABELS).map(([scope, label]) => (
+                    <label key={scope} className="settings-check">
+                      <input type="checkbox" checked={keyScopes.includes(scope)} onChange={() => toggleScope(scope)} />
+                      {label}
+                    </label>
+                  ))}
+                </div>
+                <select className="settings-input team-role" value={keyExpiry} onChange={(e) => setKeyExpiry(e.target.value)}>
+                  <option value={30}>30 дней</option>
+                  <option value={90}>90 дней</option>
+                  <option value={365}>1 год</option>
+                  <option value={0}>Бессрочно</option>
+                </select>
+                <button type="submit" className="btn btn-primary btn-sm">Создать ключ</button>
+              </form>
+              {keyError && <p className="text-error">{keyError}</p>}
+            </div>
+          </div>
+        )}
+
+        {canManage && (
+          <div className="settings-form settings-sessions">
+            <div className="settings-section">
+              <h3>Журнал действий</h3>
+              <p className="settings-hint">
+                Кто и откуда менял квизы, управлял комнатами и сессиями, менял настройки.
+              </p>
+
+              <select className="settings-input team-role" value={auditGroup} onChange={(e) => handleAuditGroup(e.target.value)}>
+                <option value="">Все действия</option>
+                {Object.entries(AUDIT_GROUP_LABELS).map(([group, label]) => (
+                  <option key={group} value={group}>{label}</option>
+                ))}
+              </select>
+
+              {auditEntries.length === 0 && <p className="settings-hint">Записей пока нет.</p>}
+              {auditEntries.map((e) => (
+                <div key={e.id} className="login-session">
+                  <div className="login-session-info">
+                    <div className="login-session-agent">
+                      {AUDIT_ACTION_LABELS[e.action] || e.action}
+                      {auditDetails(e) && ` — ${auditDetails(e)}`}
+                    </div>
+                    <div className="login-session-meta">
+                      {new Date(e.created_at).toLocaleString('ru-RU')}
+                      {' · '}
+                      {e.actor_name || e.actor_id}
+                      {` (${AUDIT_ACTOR_LABELS[e.actor_type] || e.actor_type})`}
+                      {e.ip && ` · ${e.ip}`}
+                    </div>
+                  </div>
+                </div>
+              ))}
+
+              {auditMore && (
+                <button
+                  type="button"
+                  className="btn btn-outline btn-sm"
+                  onClick={() => loadAudit(auditGroup, auditEntries[auditEntries.length - 1]?.id)}
+                >
+                  Показать ещё
+                </button>
+              )}
+            </div>
+          </div>
+        )}
+
+        {(ssoAvailable || identities.length > 0) && (
+          <div className="settings-form settings-sessions">
+            <div className="settings-section">
+              <h3>Единый вход (SSO)</h3>
+              <p className="settings-hint">
+                Привяжите корпоративную учётную запись, чтобы входить в личный кабинет через провайдера единого входа.
+              </p>
+
+              {identities.map((i) => (
+                <div key={i.id} className="login-session">
+                  <div className="login-session-info">
+                    <div className="login-session-agent">{i.email || i.subject}</div>
+                    <div className="login-session-meta">
+                      {i.issuer}{i.last_login_at && ` · вход ${new Date(i.last_login_at).toLocaleString('ru-RU')}`}
+                    </div>
+                  </div>
+                  <button type="button" className="btn btn-outline btn-sm" onClick={() => handleUnlinkIdentity(i.id)}>
+                    Отвязать
+                  </button>
+                </div>
+              ))}
+
+              {ssoAvailable && (
+                <button type="button" className="btn btn-outline btn-sm" onClick={handleLinkIdentity}>
+                  Привязать учётную запись
+                </button>
+              )}
+
+              {canManage && ssoAvailable && (
+                <label className="settings-label settings-check">
+                  <input type="checkbox" checked={ssoRequired} onChange={(e) => handleSSORequired(e.target.checked)} />
+                  Требовать единый вход для этого пространства
+                </label>
+              )}
+              {ssoError && <p className="text-error">{ssoError}</p>}
+            </div>
+          </div>
+        )}
+
+        <div className="settings-form settings-sessions">
+          <div className="settings-section">
+            <h3>Аккаунт</h3>
+
+            <form className="team-add" onSubmit={handleChangeUsername}>
+              <input
+                type="text"
+                className="settings-input"
+                value={newUsername}
+                onChange={(e) => setNewUsername(e.target.value)}
+                placeholder="Имя пользователя"
+                minLength={3}
+                maxLength={100}
+                required
+              />
+              <button type="submit" className="btn btn-primary btn-sm" disabled={!newUsername.trim() || newUsername.trim() === username}>
+                Сменить имя
+              </button>
+            </form>
+
+            <form className="team-add" onSubmit={handleChangePassword}>
+              <input
+                type="password"
+                className="settings-input"
+                value={currentPassword}
+                onChange={(e) => setCurrentPassword(e.target.value)}
+                placeholder="Текущий пароль"
+                autoComplete="current-password"
+              />
+              <input
+                type="password"
+                className="settings-input"
+                value={newPassword}
+                onChange={(e) => setNewPassword(e.target.value)}
+                placeholder="Новый пароль"
+                autoComplete="new-password"
+                minLength={6}
+                maxLength={72}
+                required
+              />
+              <button type="submit" className="btn btn-primary btn-sm">Сменить пароль</button>
+            </form>
+
+            {accountMessage && <p className="text-success">{accountMessage}</p>}
+            {accountError && <p className="text-error">{accountError}</p>}
+
+            <p className="settings-hint">
+              Удаление аккаунта стирает квизы, комнаты, историю сессий, настройки бота и загруженные картинки. Отменить его нельзя.
+            </p>
+            <button type="button" className="btn btn-outline btn-sm" onClick={handleDeleteAccount}>
+              Удалить аккаунт
+            </button>
+          </div>
+        </div>
+
+        <div className="settings-form settings-sessions">
+          <div className="settings-section">
+            <h3>Активные входы</h3>
+            <p className="settings-hint">
+              Устройства, на которых выполнен вход в личный кабинет. Завершите сеанс, если не узнаёте устройство.
+            </p>
+
+            {loginSessions.map((s) => (
+              <div key={s.id} className="login-session">
+                <div className="login-session-info">
+                  <div className="login-session-agent">{s.user_agent || 'Неизвестное устройство'}</div>
+                  <div className="login-session-meta">
+                    {s.ip}{s.method === 'sso' && ' · через SSO'} · активность {new Date(s.last_used_at).toLocaleString('ru-RU')}
+                  </div>
+                </div>
+                {s.current ? (
+                  <span className="login-session-current">Это устройство</span>
+                ) : (
+                  <button type="button" className="btn btn-outline btn-sm" onClick={() => handleRevoke(s.id)}>
+                    Завершить
+                  </button>
+                )}
+              </div>
+            ))}
+
+            <button type="button" className="btn btn-outline btn-sm" onClick={handleLogoutAll}>
+              Выйти на всех устройствах
+            </button>
+          </div>
+        </div>
       </div>
     </>
   );
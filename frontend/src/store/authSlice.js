@@ -1,10 +1,11 @@
 import { createSlice, createAsyncThunk } from '@reduxjs/toolkit';
-import { loginHost, registerHost } from '../api/auth';
+import { loginHost, registerHost, finishSSO } from '../api/auth';
+import { storeTokens, clearTokens } from '../api/axios';
 
 export const login = createAsyncThunk('auth/login', async ({ username, password }, { rejectWithValue }) => {
   try {
     const { data } = await loginHost(username, password);
-    localStorage.setItem('token', data.token);
+    storeTokens(data);
     localStorage.setItem('username', username);
     return { token: data.token, username };
   } catch (err) {
@@ -15,7 +16,7 @@ export const login = createAsyncThunk('auth/login', async ({ username, password
 export const register = createAsyncThunk('auth/register', async ({ username, password }, { rejectWithValue }) => {
   try {
     const { data } = await registerHost(username, password);
-    localStorage.setItem('token', data.token);
+    storeTokens(data);
     localStorage.setItem('username', username);
     return { token: data.token, username };
   } catch (err) {
@@ -23,6 +24,17 @@ export const register = createAsyncThunk('auth/register', async ({ username, pas
   }
 });
 
+export const ssoLogin = createAsyncThunk('auth/ssoLogin', async ({ code, state }, { rejectWithValue }) => {
+  try {
+    const { data } = await finishSSO(code, state);
+    storeTokens(data);
+    localStorage.setItem('username', data.username);
+    return { token: data.token, username: data.username };
+  } catch (err) {
+    return rejectWithValue(err.response?.data?.error || 'Single sign-on failed');
+  }
+});
+
 const authSlice = createSlice({
   name: 'auth',
   initialState: {
@@ -35,8 +47,11 @@ const authSlice = createSlice({
     logout(state) {
       state.token = null;
       state.username = null;
-      localStorage.removeItem('token');
-      localStorage.removeItem('username');
+      clearTokens();
+    },
+    setUsername(state, action) {
+      state.username = action.payload;
+      localStorage.setItem('username', action.payload);
     },
     clearError(state) {
       state.error = null;
@@ -49,9 +64,12 @@ const authSlice = createSlice({
       .addCase(login.rejected, (s, a) => { s.loading = false; s.error = a.payload; })
       .addCase(register.pending, (s) => { s.loading = true; s.error = null; })
       .addCase(register.fulfilled, (s, a) => { s.loading = false; s.token = a.payload.token; s.username = a.payload.username; })
-      .addCase(register.rejected, (s, a) => { s.loading = false; s.error = a.payload; });
+      .addCase(register.rejected, (s, a) => { s.loading = false; s.error = a.payload; })
+      .addCase(ssoLogin.pending, (s) => { s.loading = true; s.error = null; })
+      .addCase(ssoLogin.fulfilled, (s, a) => { s.loading = false; s.token = a.payload.token; s.username = a.payload.username; })
+      .addCase(ssoLogin.rejected, (s, a) => { s.loading = false; s.error = a.payload; });
   },
 });
 
-export const { logout, clearError } = authSlice.actions;
+export const { logout, setUsername, clearError } = authSlice.actions;
 export default authSlice.reducer;